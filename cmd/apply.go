@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/koh-sh/commd/internal/markdown"
+)
+
+// Run executes the apply subcommand.
+func (a *ApplyCmd) Run() error {
+	source, err := os.ReadFile(a.File)
+	if err != nil {
+		return fmt.Errorf("reading file: %w", err)
+	}
+	doc, err := markdown.Parse(source)
+	if err != nil {
+		return fmt.Errorf("parsing markdown: %w", err)
+	}
+	data, err := os.ReadFile(a.Review)
+	if err != nil {
+		return fmt.Errorf("reading review file: %w", err)
+	}
+	result, orphans, err := markdown.ParseReview(data, doc)
+	if err != nil {
+		return fmt.Errorf("parsing review file %s: %w", a.Review, err)
+	}
+
+	// Suggestions on lines past the end of the file cannot be located.
+	for i := range orphans {
+		orphans[i].Orphaned = true
+	}
+	var suggestions []markdown.ReviewComment
+	for _, c := range slices.Concat(result.Comments, orphans) {
+		if c.Suggestion != nil && a.selected(c) {
+			suggestions = append(suggestions, c)
+		}
+	}
+	for _, ref := range a.Only {
+		if !slices.ContainsFunc(suggestions, func(c markdown.ReviewComment) bool { return c.FormatLineRef() == ref }) {
+			return fmt.Errorf("no suggestion on %s in %s", ref, a.Review)
+		}
+	}
+	if len(suggestions) == 0 {
+		return fmt.Errorf("no suggestions found in %s", a.Review)
+	}
+
+	failed := 0
+	for _, c := range suggestions {
+		if c.Orphaned {
+			fmt.Fprintf(os.Stderr, "commd: warning: skipped suggestion on %s: %v\n", c.FormatLineRef(), markdown.ErrSuggestionConflict)
+			failed++
+		}
+	}
+	current := slices.DeleteFunc(slices.Clone(suggestions), func(c markdown.ReviewComment) bool { return c.Orphaned })
+	lines, applied := markdown.ApplySuggestions(doc.SourceLines, current)
+	for _, r := range applied {
+		ref := r.Comment.FormatLineRef()
+		switch {
+		case r.Err != nil:
+			fmt.Fprintf(os.Stderr, "commd: warning: skipped suggestion on %s: %v\n", ref, r.Err)
+			failed++
+		case r.Offset() != 0:
+			fmt.Fprintf(os.Stderr, "Applied suggestion on %s at L%d (offset %+d lines)\n", ref, r.Line, r.Offset())
+		default:
+			fmt.Fprintf(os.Stderr, "Applied suggestion on %s\n", ref)
+		}
+	}
+
+	// SourceLines keeps the trailing empty line, so joining restores the final newline.
+	out := strings.Join(lines, "\n")
+	if a.Write {
+		if err := os.WriteFile(a.File, []byte(out), 0o644); err != nil {
+			return fmt.Errorf("writing file: %w", err)
+		}
+	} else {
+		fmt.Print(out)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d suggestion(s) could not be applied", failed, len(suggestions))
+	}
+	return nil
+}
+
+// selected reports whether c is one of the suggestions chosen with --only.
+func (a *ApplyCmd) selected(c markdown.ReviewComment) bool {
+	return len(a.Only) == 0 || slices.Contains(a.Only, c.FormatLineRef())
+}
@@ -29,8 +29,10 @@ func (h *HookCmd) runExit(r io.Reader) int {
 	spawner := pane.ByName(h.Spawner)
 
 	exitCode, err := cchook.Run(input, cchook.RunConfig{
-		Spawner: spawner,
-		Theme:   h.Theme,
+		Spawner:   spawner,
+		Theme:     h.Theme,
+		LeftRatio: h.LeftRatio,
+		NoColor:   noColor(h.NoColor),
 	})
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "commd cchook: %v\n", err)
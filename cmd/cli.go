@@ -6,6 +6,7 @@ import (
 	"github.com/alecthomas/kong"
 	tea "github.com/charmbracelet/bubbletea"
 	ghclient "github.com/koh-sh/commd/internal/github"
+	"github.com/koh-sh/commd/internal/tui"
 )
 
 // CLI is the top-level command structure for commd.
@@ -14,34 +15,87 @@ type CLI struct {
 	PR       PRCmd      `cmd:"" help:"Review Markdown files in a GitHub PR"`
 	Cclocate LocateCmd  `cmd:"cclocate" help:"Locate file path from Claude Code transcript"`
 	Cchook   HookCmd    `cmd:"cchook" help:"Run as Claude Code PostToolUse hook"`
+	Strip    StripCmd   `cmd:"" help:"Remove review annotations written by review --output annotate"`
+	Apply    ApplyCmd   `cmd:"" help:"Apply the suggested changes in a review to the reviewed file"`
+	Config   ConfigCmd  `cmd:"" help:"Inspect configuration"`
 	Version  VersionCmd `cmd:"" help:"Show version"`
 }
 
 // HookCmd is the hook subcommand.
 type HookCmd struct {
-	Spawner string `enum:"wezterm,tmux,auto" default:"auto" help:"Force specific multiplexer (wezterm|tmux|auto)"`
-	Theme   string `enum:"dark,light" default:"dark" help:"Color theme (dark|light)"`
+	Spawner   string `enum:"wezterm,tmux,auto" default:"auto" env:"COMMD_SPAWNER" help:"Force specific multiplexer (wezterm|tmux|auto)"`
+	Theme     string `config:"-" env:"COMMD_THEME" help:"Color theme for the review pane (default: the review's configured theme)"`
+	LeftRatio int    `config:"-" env:"COMMD_LEFT_RATIO" help:"Initial section list width in percent for the review pane (default: the review's configured width)"`
+	NoColor   bool   `help:"Disable colors (also enabled by a non-empty NO_COLOR)"`
 }
 
 // ReviewCmd is the review subcommand.
 type ReviewCmd struct {
 	File        string `arg:"" help:"Path to the Markdown file"`
-	Output      string `enum:"clipboard,stdout,file" default:"clipboard" help:"Output method (clipboard|stdout|file)"`
-	OutputPath  string `help:"File path for file output" type:"path"`
-	Theme       string `enum:"dark,light" default:"dark" help:"Color theme (dark|light)"`
-	TrackViewed bool   `help:"Persist viewed state to sidecar file for change detection across sessions"`
+	Output      string `enum:"clipboard,stdout,file,annotate" default:"clipboard" env:"COMMD_OUTPUT" help:"Output method (clipboard|stdout|file|annotate)"`
+	OutputPath  string `help:"File path for file output, or for the annotated copy (default: <name>.annotated.md)" type:"path" env:"COMMD_OUTPUT_PATH"`
+	Annotate    string `enum:"html,note" default:"html" env:"COMMD_ANNOTATE" help:"Annotation style for --output annotate: HTML comments or > [!NOTE] blocks (html|note)"`
+	Format      string `enum:"markdown,json" default:"markdown" env:"COMMD_FORMAT" help:"Review format (markdown|json); json ignores --template, --quote and --order"`
+	Template    string `type:"path" env:"COMMD_TEMPLATE" help:"Go text/template file used to render the review (default: built-in format)"`
+	Quote       bool   `env:"COMMD_QUOTE" help:"Quote the commented source lines under line comments and excerpt commented sections"`
+	Order       string `enum:"added,document,label,severity" default:"added" env:"COMMD_ORDER" help:"Comment order: added (sections, then lines), document (lines nested under their section), label or severity (blocking first)"`
+	Theme       string `default:"dark" env:"COMMD_THEME" help:"Color theme (dark|light|auto|high-contrast|solarized or a theme defined in config)"`
+	LeftRatio   int    `default:"30" env:"COMMD_LEFT_RATIO" help:"Initial section list width in percent (10-50)"`
+	TrackViewed bool   `env:"COMMD_TRACK_VIEWED" help:"Persist viewed state to sidecar file for change detection across sessions"`
+	Resume      string `help:"Resume from a review file previously written by commd review" type:"existingfile"`
+	Drafts      bool   `default:"true" negatable:"" help:"Persist in-progress comments to a draft sidecar file and offer to restore them"`
+	NoColor     bool   `help:"Disable colors (also enabled by a non-empty NO_COLOR)"`
 
-	teaOpts []tea.ProgramOption // for testing: override tea.NewProgram options
+	RequireViewed     bool `env:"COMMD_REQUIRE_VIEWED" help:"Refuse approval until every section is marked viewed"`
+	RequireNoBlocking bool `env:"COMMD_REQUIRE_NO_BLOCKING" help:"Refuse approval while any blocking comment exists"`
+	MinViewed         int  `env:"COMMD_MIN_VIEWED" help:"Percentage of sections that must be marked viewed before submitting (0 = off)"`
+
+	teaOpts []tea.ProgramOption  // for testing: override tea.NewProgram options
+	keyMap  *tui.KeyMap          // key bindings from config (nil = defaults)
+	themes  map[string]tui.Theme // custom themes from config
 }
 
 // PRCmd is the pr subcommand for reviewing Markdown files in a GitHub PR.
 type PRCmd struct {
-	URL   string `arg:"" help:"GitHub PR URL (e.g. https://github.com/owner/repo/pull/123)"`
-	File  string `help:"Review a specific file instead of showing file picker"`
-	Theme string `enum:"dark,light" default:"dark" help:"Color theme (dark|light)"`
+	Ref       string   `arg:"" optional:"" name:"pr" help:"PR URL, owner/repo#123, or #123 in the origin repo (default: the PR of the current branch)"`
+	File      string   `help:"Review a specific file instead of showing file picker"`
+	Theme     string   `default:"dark" env:"COMMD_THEME" help:"Color theme (dark|light|auto|high-contrast|solarized or a theme defined in config)"`
+	LeftRatio int      `default:"30" env:"COMMD_LEFT_RATIO" help:"Initial section list width in percent (10-50)"`
+	Hosts     []string `name:"host" env:"COMMD_GITHUB_HOSTS" help:"GitHub Enterprise Server hosts to accept in PR URLs (e.g. github.example.com)"`
+	APIURL    string   `name:"api-url" env:"COMMD_GITHUB_API_URL" help:"GitHub API base URL (default: derived from the PR host)"`
+	NoColor   bool     `help:"Disable colors (also enabled by a non-empty NO_COLOR)"`
+
+	RequireViewed     bool `env:"COMMD_REQUIRE_VIEWED" help:"Refuse approval until every section of every reviewed file is marked viewed"`
+	RequireNoBlocking bool `env:"COMMD_REQUIRE_NO_BLOCKING" help:"Refuse approval while any blocking comment exists"`
+	MinViewed         int  `env:"COMMD_MIN_VIEWED" help:"Percentage of sections that must be marked viewed before finishing a file (0 = off)"`
+
+	OverviewComments string `enum:"file,body" default:"file" env:"COMMD_OVERVIEW_COMMENTS" help:"Post overview comments as file-level PR comments or in the review body (file|body)"`
+
+	teaOpts []tea.ProgramOption     // for testing: override tea.NewProgram options
+	client  *ghclient.Client        // GitHub client, created on first use (tests set it to override)
+	pending *ghclient.PendingReview // pending review being resumed: the comments commd loaded from it
+	ownPR   bool                    // the viewer authored the PR: no approving or requesting changes
+	keyMap  *tui.KeyMap             // key bindings from config (nil = defaults)
+	themes  map[string]tui.Theme    // custom themes from config
+}
+
+// StripCmd is the strip subcommand.
+type StripCmd struct {
+	File  string `arg:"" help:"Path to the annotated Markdown file" type:"existingfile"`
+	Write bool   `short:"w" help:"Write the result back to the file instead of stdout"`
+}
+
+// ApplyCmd is the apply subcommand.
+type ApplyCmd struct {
+	Review string   `arg:"" help:"Review file written by commd review" type:"existingfile"`
+	File   string   `arg:"" help:"Path to the reviewed Markdown file" type:"existingfile"`
+	Only   []string `help:"Only apply the suggestions on these line references (e.g. L12,L20-L24)"`
+	Write  bool     `short:"w" help:"Write the result back to the file instead of stdout"`
+}
 
-	teaOpts []tea.ProgramOption // for testing: override tea.NewProgram options
-	client  *ghclient.Client    // for testing: override GitHub client
+// ConfigCmd is the config subcommand.
+type ConfigCmd struct {
+	Show ConfigShowCmd `cmd:"" help:"Print resolved configuration values and where each came from"`
 }
 
 // LocateCmd is the locate subcommand.
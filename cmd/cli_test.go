@@ -9,11 +9,16 @@ import (
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/alecthomas/kong"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/koh-sh/commd/internal/config"
 	ghclient "github.com/koh-sh/commd/internal/github"
 	"github.com/koh-sh/commd/internal/markdown"
 )
@@ -425,7 +430,7 @@ func TestLocateCmdRunStdinMode(t *testing.T) {
 }
 
 func TestPRCmdRunInvalidURL(t *testing.T) {
-	p := &PRCmd{URL: "not-a-url"}
+	p := &PRCmd{Ref: "not-a-url"}
 	err := p.Run()
 	if err == nil {
 		t.Fatal("expected error for invalid URL")
@@ -435,7 +440,7 @@ func TestPRCmdRunInvalidURL(t *testing.T) {
 func TestPRCmdRunNoToken(t *testing.T) {
 	t.Setenv("GITHUB_TOKEN", "")
 	t.Setenv("PATH", "") // disable gh CLI fallback
-	p := &PRCmd{URL: "https://github.com/owner/repo/pull/1"}
+	p := &PRCmd{Ref: "https://github.com/owner/repo/pull/1"}
 	err := p.Run()
 	if err == nil {
 		t.Fatal("expected error when no token available")
@@ -452,7 +457,7 @@ func TestPRCmdRunNoMDFiles(t *testing.T) {
 
 	client := ghclient.NewClientWithHTTP(srv.Client(), srv.URL+"/")
 	p := &PRCmd{
-		URL:    "https://github.com/owner/repo/pull/1",
+		Ref:    "https://github.com/owner/repo/pull/1",
 		client: client,
 	}
 	err := p.Run()
@@ -461,6 +466,20 @@ func TestPRCmdRunNoMDFiles(t *testing.T) {
 	}
 }
 
+func TestPRCmdRunShortRef(t *testing.T) {
+	srv := prTestServer(t, []map[string]string{
+		{"filename": "main.go", "status": "modified"},
+	}, "")
+
+	p := &PRCmd{
+		Ref:    "owner/repo#1",
+		client: ghclient.NewClientWithHTTP(srv.Client(), srv.URL+"/"),
+	}
+	if err := p.Run(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
 func TestPRCmdRunFileNotInPR(t *testing.T) {
 	srv := prTestServer(t, []map[string]string{
 		{"filename": "README.md", "status": "modified"},
@@ -468,7 +487,7 @@ func TestPRCmdRunFileNotInPR(t *testing.T) {
 
 	client := ghclient.NewClientWithHTTP(srv.Client(), srv.URL+"/")
 	p := &PRCmd{
-		URL:    "https://github.com/owner/repo/pull/1",
+		Ref:    "https://github.com/owner/repo/pull/1",
 		File:   "missing.md",
 		client: client,
 	}
@@ -494,7 +513,7 @@ func TestPRCmdRunWithFileFlag(t *testing.T) {
 	pw.Close()
 
 	p := &PRCmd{
-		URL:     "https://github.com/owner/repo/pull/1",
+		Ref:     "https://github.com/owner/repo/pull/1",
 		File:    "README.md",
 		Theme:   "dark",
 		client:  client,
@@ -506,6 +525,88 @@ func TestPRCmdRunWithFileFlag(t *testing.T) {
 	}
 }
 
+func TestPRCmdPlaceComments(t *testing.T) {
+	newResults := func() []ghclient.FileReviewResult {
+		doc := &markdown.Document{Sections: []*markdown.Section{{ID: "S1", Title: "Intro", StartLine: 1, EndLine: 10}}}
+		return []ghclient.FileReviewResult{{
+			Path: "README.md",
+			Doc:  doc,
+			Diff: ghclient.ParsePatch("@@ -4,2 +4,2 @@\n a\n+b"),
+			Review: &markdown.ReviewResult{Comments: []markdown.ReviewComment{
+				{SectionID: "S1", Action: markdown.ActionNote, Body: "Outside", StartLine: 8},
+			}},
+		}}
+	}
+
+	tests := []struct {
+		name      string
+		input     string
+		wantOK    bool
+		wantMoved int
+		wantLine  int
+	}{
+		{name: "nearest line", input: "\r", wantOK: true, wantLine: 5},
+		{name: "review comment", input: "j\r", wantOK: true, wantMoved: 1},
+		{name: "cancel", input: "q"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := newResults()
+			p := &PRCmd{teaOpts: []tea.ProgramOption{tea.WithInput(strings.NewReader(tt.input))}}
+			moved, ok, err := p.placeComments(results)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if ok != tt.wantOK || len(moved) != tt.wantMoved {
+				t.Fatalf("placeComments() = %+v, %v, want %d moved, ok %v", moved, ok, tt.wantMoved, tt.wantOK)
+			}
+			if tt.wantLine > 0 {
+				if got := results[0].Review.Comments[0].StartLine; got != tt.wantLine {
+					t.Errorf("StartLine = %d, want %d", got, tt.wantLine)
+				}
+			}
+		})
+	}
+
+	t.Run("nothing misplaced", func(t *testing.T) {
+		results := newResults()
+		results[0].Diff = nil
+		if moved, ok, err := (&PRCmd{}).placeComments(results); moved != nil || !ok || err != nil {
+			t.Errorf("placeComments() = %v, %v, %v", moved, ok, err)
+		}
+	})
+}
+
+func TestApprovalBlocked(t *testing.T) {
+	results := func(c markdown.ReviewComment) []ghclient.FileReviewResult {
+		return []ghclient.FileReviewResult{
+			{Path: "a.md", Review: &markdown.ReviewResult{}},
+			{Path: "b.md", Review: &markdown.ReviewResult{Comments: []markdown.ReviewComment{c}}},
+		}
+	}
+	blocking := markdown.ReviewComment{SectionID: "S1", Action: markdown.ActionIssue, Decoration: markdown.DecorationBlocking, Body: "Wrong"}
+	note := markdown.ReviewComment{SectionID: "S1", Action: markdown.ActionNote, Body: "Fine"}
+
+	tests := []struct {
+		name        string
+		policy      markdown.Policy
+		results     []ghclient.FileReviewResult
+		wantBlocked bool
+	}{
+		{"blocking comment", markdown.Policy{NoBlocking: true}, results(blocking), true},
+		{"no blocking comment", markdown.Policy{NoBlocking: true}, results(note), false},
+		{"policy off", markdown.Policy{}, results(blocking), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := approvalBlocked(tt.policy, tt.results, 2, 2)
+			if (len(got) > 0) != tt.wantBlocked {
+				t.Errorf("approvalBlocked() = %q, want blocked %v", got, tt.wantBlocked)
+			}
+		})
+	}
+}
+
 func TestPRCmdSubmitReviewComment(t *testing.T) {
 	srv := prTestServer(t, nil, "")
 
@@ -547,6 +648,139 @@ func TestPRCmdSubmitReviewApprove(t *testing.T) {
 	}
 }
 
+func TestPRCmdSubmitReviewRequestChanges(t *testing.T) {
+	srv := prTestServer(t, nil, "")
+
+	client := ghclient.NewClientWithHTTP(srv.Client(), srv.URL+"/")
+	ref := &ghclient.PRRef{Owner: "owner", Repo: "repo", Number: 1}
+
+	p := &PRCmd{Theme: "dark"}
+	err := p.submitReview(context.Background(), client, ref, nil, "REQUEST_CHANGES", "Please fix the install steps")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestPRCmdSubmitReviewThreadChanges(t *testing.T) {
+	srv := prTestServer(t, nil, "")
+
+	client := ghclient.NewClientWithHTTP(srv.Client(), srv.URL+"/")
+	ref := &ghclient.PRRef{Owner: "owner", Repo: "repo", Number: 1}
+
+	tests := []struct {
+		name    string
+		results []ghclient.FileReviewResult
+	}{
+		{
+			name: "replies only",
+			results: []ghclient.FileReviewResult{{
+				Path:    "README.md",
+				Doc:     &markdown.Document{},
+				Review:  &markdown.ReviewResult{},
+				Replies: []ghclient.ThreadReply{{ThreadID: "T1", Body: "Fixed"}},
+			}},
+		},
+		{
+			name: "resolutions only",
+			results: []ghclient.FileReviewResult{{
+				Path:        "README.md",
+				Doc:         &markdown.Document{},
+				Review:      &markdown.ReviewResult{},
+				Resolutions: []ghclient.ThreadResolution{{ThreadID: "T1", Resolve: true}},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &PRCmd{Theme: "dark"}
+			if err := p.submitReview(context.Background(), client, ref, tt.results, "COMMENT", ""); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestRequestChangesReasons(t *testing.T) {
+	results := []ghclient.FileReviewResult{
+		{Path: "a.md", Review: &markdown.ReviewResult{Comments: []markdown.ReviewComment{
+			{Action: markdown.ActionIssue, Decoration: markdown.DecorationBlocking},
+			{Action: markdown.ActionIssue},
+			{Action: markdown.ActionIssue},
+			{Action: markdown.ActionNote},
+		}}},
+		{Path: "b.md", Review: &markdown.ReviewResult{Comments: []markdown.ReviewComment{
+			{Action: markdown.ActionNitpick, Decoration: markdown.DecorationBlocking},
+		}}},
+		{Path: "c.md", Review: &markdown.ReviewResult{Comments: []markdown.ReviewComment{{Action: markdown.ActionQuestion}}}},
+		{Path: "d.md"},
+	}
+	want := []string{"a.md: 1 blocking comment(s), 2 issue(s)", "b.md: 1 blocking comment(s)"}
+	if got := requestChangesReasons(results); !slices.Equal(got, want) {
+		t.Errorf("requestChangesReasons() = %q, want %q", got, want)
+	}
+	if got := requestChangesReasons(results[2:]); got != nil {
+		t.Errorf("requestChangesReasons() without blocking or issue comments = %q, want nil", got)
+	}
+}
+
+func TestThreadChangeSummary(t *testing.T) {
+	results := []ghclient.FileReviewResult{
+		{Path: "a.md", Replies: []ghclient.ThreadReply{{ThreadID: "T1"}, {ThreadID: "T2"}}},
+		{Path: "b.md", Resolutions: []ghclient.ThreadResolution{{ThreadID: "T3", Resolve: true}, {ThreadID: "T4"}}},
+		{Path: "c.md"},
+	}
+	want := []string{
+		"",
+		"Replies to existing threads:",
+		"  a.md: 2 reply(ies)",
+		"",
+		"Thread resolutions:",
+		"  b.md: 1 to resolve, 1 to unresolve",
+	}
+	if got := threadChangeSummary(results); !slices.Equal(got, want) {
+		t.Errorf("threadChangeSummary() = %q, want %q", got, want)
+	}
+	if got := threadChangeSummary(results[2:]); got != nil {
+		t.Errorf("threadChangeSummary() without changes = %q, want nil", got)
+	}
+}
+
+func TestPRCmdBuildSubmission(t *testing.T) {
+	doc := &markdown.Document{Sections: []*markdown.Section{{ID: "S1", Title: "Intro", StartLine: 1, EndLine: 5}}}
+	results := []ghclient.FileReviewResult{{
+		Path: "a.md",
+		Doc:  doc,
+		Review: &markdown.ReviewResult{Comments: []markdown.ReviewComment{
+			{SectionID: markdown.OverviewSectionID, Action: markdown.ActionNote, Body: "Overall"},
+			{SectionID: "S1", Action: markdown.ActionNote, Body: "Line", StartLine: 2},
+		}},
+	}}
+
+	tests := []struct {
+		mode      string
+		wantFiles int
+		wantBody  string
+	}{
+		{mode: "file", wantFiles: 1, wantBody: "LGTM"},
+		{mode: "body", wantFiles: 0, wantBody: "LGTM\n\n### a.md\n\n**[note]** Overall"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			p := &PRCmd{OverviewComments: tt.mode}
+			sub := p.buildSubmission(results, "COMMENT", "LGTM")
+			if len(sub.FileComments) != tt.wantFiles {
+				t.Errorf("FileComments = %+v, want %d", sub.FileComments, tt.wantFiles)
+			}
+			if len(sub.Review.Comments) != 1 {
+				t.Errorf("line comments = %d, want 1", len(sub.Review.Comments))
+			}
+			if got := sub.Review.GetBody(); got != tt.wantBody {
+				t.Errorf("body = %q, want %q", got, tt.wantBody)
+			}
+		})
+	}
+}
+
 func TestPRCmdSubmitReviewError(t *testing.T) {
 	mux := http.NewServeMux()
 	mux.HandleFunc("POST /repos/owner/repo/pulls/1/reviews", func(w http.ResponseWriter, _ *http.Request) {
@@ -616,8 +850,28 @@ func prTestServer(t *testing.T, files []map[string]string, fileContent string) *
 		})
 	}
 
+	// Existing review threads
+	mux.HandleFunc("POST /graphql", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(map[string]any{
+			"data": map[string]any{"repository": map[string]any{"pullRequest": map[string]any{
+				"reviewThreads": map[string]any{"nodes": []any{}},
+			}}},
+		}); err != nil {
+			t.Fatalf("encoding threads: %v", err)
+		}
+	})
+
 	// Create review
 	mux.HandleFunc("POST /repos/owner/repo/pulls/1/reviews", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(map[string]any{"id": 1, "node_id": "PRR_1"}); err != nil {
+			t.Fatalf("encoding review: %v", err)
+		}
+	})
+
+	// Submit a pending review
+	mux.HandleFunc("POST /repos/owner/repo/pulls/1/reviews/1/events", func(w http.ResponseWriter, _ *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
 		if err := json.NewEncoder(w).Encode(map[string]any{"id": 1}); err != nil {
 			t.Fatalf("encoding review: %v", err)
@@ -628,3 +882,562 @@ func prTestServer(t *testing.T, files []map[string]string, fileContent string) *
 	t.Cleanup(srv.Close)
 	return srv
 }
+
+func TestLoadResumedReview(t *testing.T) {
+	doc, err := markdown.Parse([]byte("# Plan\n\n## Step 1\n\nContent.\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpDir := t.TempDir()
+
+	t.Run("valid review", func(t *testing.T) {
+		path := filepath.Join(tmpDir, "review.md")
+		review := "# Review\n\nPlease review and address the following comments on: plan.md\n\n" +
+			"## Verdicts\n- `S1: Step 1` reject\n\n" +
+			"## S1: Step 1\n[note] keep\n\n## S2: Deleted\n[issue] drop\n"
+		if err := os.WriteFile(path, []byte(review), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		result, err := loadResumedReview(path, doc)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(result.Comments) != 1 || result.Comments[0].Body != "keep" {
+			t.Errorf("comments = %+v, want only the S1 comment", result.Comments)
+		}
+		if result.Verdicts["S1"] != markdown.VerdictReject {
+			t.Errorf("verdicts = %v, want S1 rejected", result.Verdicts)
+		}
+	})
+
+	t.Run("suggestion re-anchored", func(t *testing.T) {
+		edited, err := markdown.Parse([]byte("# Plan\n\nIntro.\n\n## Step 1\n\nContent.\n"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(tmpDir, "suggestion.md")
+		review := "# Review\n\nPlease review and address the following comments on: plan.md\n\n---\n\n" +
+			"`L5` [suggestion] Reword\n\n```diff\n@@ -5 +5 @@\n-Content.\n+Body.\n```\n"
+		if err := os.WriteFile(path, []byte(review), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		result, err := loadResumedReview(path, edited)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(result.Comments) != 1 || result.Comments[0].StartLine != 7 || result.Comments[0].Orphaned {
+			t.Errorf("comments = %+v, want the suggestion moved to L7", result.Comments)
+		}
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := loadResumedReview(filepath.Join(tmpDir, "missing.md"), doc)
+		if err == nil || !strings.Contains(err.Error(), "reading review file") {
+			t.Errorf("error = %v, want 'reading review file'", err)
+		}
+	})
+
+	t.Run("not a review", func(t *testing.T) {
+		path := filepath.Join(tmpDir, "plan.md")
+		if err := os.WriteFile(path, []byte("# Plan\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		_, err := loadResumedReview(path, doc)
+		if err == nil || !strings.Contains(err.Error(), "parsing review file") {
+			t.Errorf("error = %v, want 'parsing review file'", err)
+		}
+	})
+}
+
+// parseWithConfig parses args into a fresh CLI with cfg as the config resolver.
+func parseWithConfig(t *testing.T, cfg *config.Config, args ...string) (*CLI, *kong.Context) {
+	t.Helper()
+	var cli CLI
+	parser, err := kong.New(&cli, kong.Resolvers(ConfigResolver(cfg)), kong.Bind(cfg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, err := parser.Parse(args)
+	if err != nil {
+		t.Fatalf("Parse(%v) error: %v", args, err)
+	}
+	return &cli, ctx
+}
+
+// loadTestConfig writes content to a repo-level config file and loads it.
+func loadTestConfig(t *testing.T, content string) *config.Config {
+	t.Helper()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, config.RepoFileName), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := config.Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cfg
+}
+
+func TestConfigResolver(t *testing.T) {
+	cfg := loadTestConfig(t, `
+theme = "light"
+leftRatio = 40
+
+[review]
+output = "stdout"
+track-viewed = true
+
+[pr]
+api-url = "https://ghe.example.com/api/v3/"
+host = ["ghe.example.com", "ghe2.example.com"]
+`)
+
+	t.Run("config fills unset flags", func(t *testing.T) {
+		cli, _ := parseWithConfig(t, cfg, "review", "plan.md")
+		r := cli.Review
+		if r.Theme != "light" || r.LeftRatio != 40 || r.Output != "stdout" || !r.TrackViewed {
+			t.Errorf("review = %+v, want values from config", r)
+		}
+	})
+
+	t.Run("command table only applies to its command", func(t *testing.T) {
+		cli, _ := parseWithConfig(t, cfg, "pr", "https://github.com/o/r/pull/1")
+		if cli.PR.Theme != "light" || cli.PR.APIURL != "https://ghe.example.com/api/v3/" || !slices.Equal(cli.PR.Hosts, []string{"ghe.example.com", "ghe2.example.com"}) {
+			t.Errorf("pr = %+v, want theme and api-url from config", cli.PR)
+		}
+	})
+
+	t.Run("hook leaves review settings to the review config", func(t *testing.T) {
+		cli, _ := parseWithConfig(t, cfg, "cchook")
+		if cli.Cchook.Theme != "" || cli.Cchook.LeftRatio != 0 {
+			t.Errorf("cchook = %+v, want theme and left-ratio unset", cli.Cchook)
+		}
+	})
+
+	t.Run("env overrides config", func(t *testing.T) {
+		t.Setenv("COMMD_THEME", "dark")
+		cli, _ := parseWithConfig(t, cfg, "review", "plan.md")
+		if cli.Review.Theme != "dark" {
+			t.Errorf("theme = %s, want dark from env", cli.Review.Theme)
+		}
+	})
+
+	t.Run("flag overrides env and config", func(t *testing.T) {
+		t.Setenv("COMMD_OUTPUT", "file")
+		cli, _ := parseWithConfig(t, cfg, "review", "--output", "clipboard", "plan.md")
+		if cli.Review.Output != "clipboard" {
+			t.Errorf("output = %s, want clipboard from flag", cli.Review.Output)
+		}
+	})
+}
+
+func TestConfigShow(t *testing.T) {
+	cfg := loadTestConfig(t, `
+[review]
+theme = "light"
+
+[labels]
+actions = ["issue", "security"]
+`)
+	t.Setenv("COMMD_SPAWNER", "tmux")
+	_, ctx := parseWithConfig(t, cfg, "config", "show")
+
+	var buf bytes.Buffer
+	if err := showConfig(&buf, ctx.Model, cfg); err != nil {
+		t.Fatalf("showConfig() error: %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []*regexp.Regexp{
+		regexp.MustCompile(`review\.theme\s+light\s+\S+\.commd\.toml`),
+		regexp.MustCompile(`pr\.theme\s+dark\s+default`),
+		regexp.MustCompile(`cchook\.spawner\s+tmux\s+env COMMD_SPAWNER`),
+		regexp.MustCompile(`review\.track-viewed\s+false\s+default`),
+		regexp.MustCompile(`labels\.actions\s+issue,security\s+\S+\.commd\.toml`),
+		regexp.MustCompile(`labels\.default\s+issue\s+default`),
+	} {
+		if !want.MatchString(out) {
+			t.Errorf("output does not match %s:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "version.") || strings.Contains(out, "config.") {
+		t.Errorf("output should not list commands without flags:\n%s", out)
+	}
+}
+
+func TestKeyConfigConflict(t *testing.T) {
+	cfg := loadTestConfig(t, "[keys]\nsubmit = \"c\"\n")
+	var cli CLI
+	parser, err := kong.New(&cli, kong.Resolvers(ConfigResolver(cfg)), kong.Bind(cfg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = parser.Parse([]string{"review", "plan.md"})
+	if err == nil || !strings.Contains(err.Error(), `key "c" is bound to both comment and submit`) {
+		t.Errorf("Parse() error = %v, want key conflict", err)
+	}
+
+	cfg = loadTestConfig(t, "[keys]\npreset = \"emacs\"\nsubmit = \"S\"\n")
+	cli2, _ := parseWithConfig(t, cfg, "review", "plan.md")
+	if cli2.Review.keyMap == nil || cli2.Review.keyMap.Submit.Keys()[0] != "S" {
+		t.Errorf("review key map = %+v, want remapped submit", cli2.Review.keyMap)
+	}
+}
+
+func TestThemeConfig(t *testing.T) {
+	cfg := loadTestConfig(t, `
+[themes.mine]
+base = "light"
+colors = { diff-added = "#00ff00" }
+`)
+	cli, _ := parseWithConfig(t, cfg, "review", "--theme", "mine", "plan.md")
+	theme, err := resolveTheme(cli.Review.Theme, cli.Review.themes, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if theme.Name != "mine" || theme.Dark {
+		t.Errorf("theme = %+v, want light-based custom theme", theme)
+	}
+
+	if _, err := resolveTheme("neon", cli.Review.themes, false); err == nil || !strings.Contains(err.Error(), `unknown theme "neon"`) {
+		t.Errorf("resolveTheme(neon) error = %v, want unknown theme", err)
+	}
+
+	cfg = loadTestConfig(t, "[themes.mine.colors]\ntitle = \"red\"\n")
+	var bad CLI
+	parser, err := kong.New(&bad, kong.Resolvers(ConfigResolver(cfg)), kong.Bind(cfg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := parser.Parse([]string{"review", "plan.md"}); err == nil || !strings.Contains(err.Error(), "invalid theme config") {
+		t.Errorf("Parse() error = %v, want invalid theme config", err)
+	}
+}
+
+func TestNoColor(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	if noColor(false) {
+		t.Error("noColor(false) with empty NO_COLOR = true, want false")
+	}
+	if !noColor(true) {
+		t.Error("noColor(true) = false, want true")
+	}
+	t.Setenv("NO_COLOR", "1")
+	if !noColor(false) {
+		t.Error("noColor(false) with NO_COLOR=1 = false, want true")
+	}
+}
+
+func TestReviewCmdRunInvalidTemplate(t *testing.T) {
+	tmpDir := t.TempDir()
+	planFile := filepath.Join(tmpDir, "plan.md")
+	if err := os.WriteFile(planFile, []byte("# Plan\n\n## Step 1\n\nContent.\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tmplFile := filepath.Join(tmpDir, "review.tmpl")
+	if err := os.WriteFile(tmplFile, []byte("{{if}}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	r := &ReviewCmd{File: planFile, Output: "stdout", Template: tmplFile}
+	if err := r.Run(); err == nil || !strings.Contains(err.Error(), "parsing template") {
+		t.Errorf("error = %v, want template parse error before the TUI starts", err)
+	}
+}
+
+func TestWriteAnnotatedAndStrip(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "plan.md")
+	source := "# Plan\n\n## Step 1\n\nContent.\n"
+	if err := os.WriteFile(file, []byte(source), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	doc, err := markdown.Parse([]byte(source))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := &ReviewCmd{File: file, Annotate: markdown.AnnotateNote}
+	comments := []markdown.ReviewComment{{SectionID: "S1", Action: markdown.ActionIssue, Body: "Too short."}}
+	if err := r.writeAnnotated(source, doc, comments); err != nil {
+		t.Fatal(err)
+	}
+	annotatedPath := filepath.Join(dir, "plan.annotated.md")
+	annotated, err := os.ReadFile(annotatedPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(annotated), "## Step 1\n\n> [!NOTE]\n> commd: [issue] Too short.\n") {
+		t.Errorf("annotated copy =\n%s", annotated)
+	}
+
+	s := &StripCmd{File: annotatedPath, Write: true}
+	if err := s.Run(); err != nil {
+		t.Fatal(err)
+	}
+	stripped, err := os.ReadFile(annotatedPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(stripped) != source {
+		t.Errorf("stripped =\n%q\nwant:\n%q", stripped, source)
+	}
+}
+
+func TestApplyCmdRun(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "plan.md")
+	source := "# Plan\n\n## Setup\n\nnpm install\nnpm test\n"
+	if err := os.WriteFile(file, []byte(source), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	doc, err := markdown.Parse([]byte(source))
+	if err != nil {
+		t.Fatal(err)
+	}
+	review := markdown.FormatReview(&markdown.ReviewResult{Comments: []markdown.ReviewComment{
+		{SectionID: "S1", Action: markdown.ActionSuggestion, Body: "Use ci.", StartLine: 5,
+			Suggestion: &markdown.Suggestion{Original: []string{"npm install"}, Replacement: []string{"npm ci"}}},
+		{SectionID: "S1", Action: markdown.ActionSuggestion, StartLine: 6,
+			Suggestion: &markdown.Suggestion{Original: []string{"npm run test"}, Replacement: []string{"npm test -- --ci"}}},
+	}}, doc, "plan.md")
+	reviewPath := filepath.Join(dir, "review.md")
+	if err := os.WriteFile(reviewPath, []byte(review), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("conflicting suggestion is skipped", func(t *testing.T) {
+		a := &ApplyCmd{Review: reviewPath, File: file, Write: true}
+		err := a.Run()
+		if err == nil || !strings.Contains(err.Error(), "1 of 2 suggestion(s) could not be applied") {
+			t.Fatalf("Run() error = %v", err)
+		}
+		got, _ := os.ReadFile(file)
+		if want := "# Plan\n\n## Setup\n\nnpm ci\nnpm test\n"; string(got) != want {
+			t.Errorf("file =\n%q\nwant:\n%q", got, want)
+		}
+	})
+
+	t.Run("unknown --only reference", func(t *testing.T) {
+		a := &ApplyCmd{Review: reviewPath, File: file, Only: []string{"L9"}}
+		if err := a.Run(); err == nil || !strings.Contains(err.Error(), "no suggestion on L9") {
+			t.Errorf("Run() error = %v", err)
+		}
+	})
+}
+
+func TestReviewCmdFormatReview(t *testing.T) {
+	doc, err := markdown.Parse([]byte("# Plan\n\n## Step 1\n\nContent.\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	review := &markdown.ReviewResult{
+		Comments: []markdown.ReviewComment{{SectionID: "S1", Action: markdown.ActionNote, Body: "ok"}},
+		Verdicts: map[string]markdown.Verdict{"S1": markdown.VerdictApprove},
+	}
+
+	md := (&ReviewCmd{File: "plan.md", Format: "markdown"}).formatReview(nil, review, doc)
+	if !strings.Contains(md, "## Verdicts\n- `S1: Step 1` approve\n") {
+		t.Errorf("markdown output =\n%s", md)
+	}
+
+	out := (&ReviewCmd{File: "plan.md", Format: "json"}).formatReview(nil, review, doc)
+	var got markdown.JSONReview
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("json output is invalid: %v\n%s", err, out)
+	}
+	if len(got.Comments) != 1 || len(got.Verdicts) != 1 || got.Verdicts[0].Verdict != markdown.VerdictApprove {
+		t.Errorf("json output = %+v", got)
+	}
+}
+
+func TestSubmitPolicy(t *testing.T) {
+	policy, err := submitPolicy(true, false, 80)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !policy.RequireViewed || policy.NoBlocking || policy.MinViewed != 80 {
+		t.Errorf("submitPolicy() = %+v", policy)
+	}
+	if _, err := submitPolicy(false, false, 120); err == nil || !strings.Contains(err.Error(), "min-viewed") {
+		t.Errorf("err = %v, want a min-viewed error", err)
+	}
+}
+
+func TestFileThreads(t *testing.T) {
+	created := time.Date(2026, 1, 2, 3, 4, 0, 0, time.UTC)
+	threads := []ghclient.ReviewThread{
+		{ID: "T1", Path: "a.md", Line: 5, Side: "RIGHT", IsResolved: true, Comments: []ghclient.ThreadComment{{Author: "alice", Body: "Typo", CreatedAt: created}}},
+		{Path: "b.md", Line: 2},
+		{Path: "a.md", IsOutdated: true},
+		{ID: "T4", Path: "a.md", Line: 8, Comments: []ghclient.ThreadComment{
+			{Author: "bob", Body: "Why?"}, {Author: "me", Body: "Because", Pending: true}, {Author: "me", Body: "See above", Pending: true},
+		}},
+		{ID: "T5", Path: "a.md", Line: 9, Comments: []ghclient.ThreadComment{{Author: "me", Body: "New", Pending: true}}},
+	}
+	got, replied := fileThreads(threads, "a.md")
+	if len(got) != 3 {
+		t.Fatalf("fileThreads() returned %d threads, want 3 (the pending thread is a comment)", len(got))
+	}
+	if !slices.Equal(replied, []string{"T4"}) {
+		t.Errorf("replied = %v, want [T4]", replied)
+	}
+	if g := got[2]; len(g.Comments) != 1 || g.Reply != "Because\n\nSee above" {
+		t.Errorf("thread with pending replies = %+v", g)
+	}
+	if g := got[0]; g.ID != "T1" || g.Line != 5 || g.Side != "RIGHT" || !g.Resolved || len(g.Comments) != 1 || g.Comments[0].Author != "alice" || !g.Comments[0].CreatedAt.Equal(created) {
+		t.Errorf("thread = %+v", g)
+	}
+	if g := got[1]; g.Line != 0 || !g.Outdated {
+		t.Errorf("outdated thread = %+v", g)
+	}
+}
+
+func TestNewExpandableDiffData(t *testing.T) {
+	head := fileLines([]byte("a\nb\nc\nd\nE\nf\n"))
+	fetches := 0
+	base := sync.OnceValue(func() []string {
+		fetches++
+		return fileLines([]byte("a\nb\nc\nd\ne\nf\n"))
+	})
+	data := newExpandableDiffData(ghclient.ParsePatch("@@ -5,1 +5,1 @@\n-e\n+E"), head, base)
+	if fetches != 0 {
+		t.Errorf("base lines fetched %d time(s) before expanding, want 0", fetches)
+	}
+
+	// Expand above the hunk: lines 1-4 are added as context outside the patch
+	up := data.Expand(0, false)
+	if up == nil {
+		t.Fatal("Expand() above = nil")
+	}
+	if !slices.Equal(up.LineMap, []int{1, 2, 3, 4, 5, 5}) ||
+		!slices.Equal(up.SideMap, []string{"RIGHT", "RIGHT", "RIGHT", "RIGHT", "LEFT", "RIGHT"}) ||
+		!slices.Equal(up.TypeMap, []byte("    -+")) ||
+		!slices.Equal(up.Outside, []bool{true, true, true, true, false, false}) {
+		t.Errorf("Expand() above = %+v", up)
+	}
+
+	// Expanded data expands further; line 6 is the last line on both sides
+	down := up.Expand(5, true)
+	if down == nil || !slices.Equal(down.LineMap, []int{1, 2, 3, 4, 5, 5, 6}) {
+		t.Fatalf("Expand() below = %+v, want line 6 added", down)
+	}
+	if down.Expand(0, false) != nil || down.Expand(6, true) != nil {
+		t.Error("a fully expanded diff should not expand")
+	}
+	if fetches != 1 {
+		t.Errorf("base lines fetched %d time(s), want 1", fetches)
+	}
+}
+
+func TestPendingComments(t *testing.T) {
+	doc, err := markdown.Parse([]byte("# Title\n\n## Intro\n\nline 5\nline 6\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pending := &ghclient.PendingReview{
+		Comments: []ghclient.PendingComment{
+			{ID: 1, PRReviewComment: ghclient.PRReviewComment{Path: "a.md", Line: 5, Side: "RIGHT", Body: "**[issue (blocking)]** Wrong"}},
+			{ID: 2, PRReviewComment: ghclient.PRReviewComment{Path: "a.md", Line: 0, Body: "outdated"}},
+			{ID: 3, PRReviewComment: ghclient.PRReviewComment{Path: "b.md", Line: 5, Body: "other file"}},
+		},
+		Replies: []ghclient.PendingComment{{ID: 4, ThreadID: "T1"}, {ID: 5, ThreadID: "T2"}},
+	}
+
+	comments, loaded := pendingComments(pending, "a.md", doc)
+	if len(comments) != 1 || len(loaded) != 1 || loaded[0].ID != 1 {
+		t.Fatalf("pendingComments() = %+v, %+v; want only comment 1", comments, loaded)
+	}
+	if c := comments[0]; c.Action != markdown.ActionIssue || c.Decoration != markdown.DecorationBlocking || c.Body != "Wrong" || c.StartLine != 5 || c.SectionID != "S1" {
+		t.Errorf("comment = %+v", c)
+	}
+	if c, l := pendingComments(nil, "a.md", doc); c != nil || l != nil {
+		t.Errorf("pendingComments(nil) = %v, %v", c, l)
+	}
+
+	if got := pendingReplies(pending, []string{"T2"}); len(got) != 1 || got[0].ID != 5 {
+		t.Errorf("pendingReplies() = %+v, want reply 5", got)
+	}
+}
+
+func TestPRCmdSavePendingReview(t *testing.T) {
+	srv := prTestServer(t, nil, "")
+
+	client := ghclient.NewClientWithHTTP(srv.Client(), srv.URL+"/")
+	ref := &ghclient.PRRef{Owner: "owner", Repo: "repo", Number: 1}
+	results := []ghclient.FileReviewResult{{
+		Path: "README.md",
+		Doc:  &markdown.Document{Sections: []*markdown.Section{{ID: "S1", Title: "Intro", StartLine: 3}}},
+		Review: &markdown.ReviewResult{
+			Comments: []markdown.ReviewComment{{SectionID: "S1", Action: markdown.ActionNote, Body: "note", StartLine: 4}},
+		},
+	}}
+
+	p := &PRCmd{Theme: "dark"}
+	if err := p.savePendingReview(context.Background(), client, ref, results, ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestPRCmdSubmitReviewDiscardsEmptiedPending(t *testing.T) {
+	var calls []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("DELETE /repos/owner/repo/pulls/1/reviews/7", func(w http.ResponseWriter, _ *http.Request) {
+		calls = append(calls, "discard 7")
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(map[string]any{"id": 7}); err != nil {
+			t.Fatalf("encoding review: %v", err)
+		}
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	client := ghclient.NewClientWithHTTP(srv.Client(), srv.URL+"/")
+	ref := &ghclient.PRRef{Owner: "owner", Repo: "repo", Number: 1}
+
+	// The only comment of the resumed review was deleted in the TUI
+	results := []ghclient.FileReviewResult{{
+		Path:   "README.md",
+		Doc:    &markdown.Document{Sections: []*markdown.Section{{ID: "S1", Title: "Intro", StartLine: 3}}},
+		Review: &markdown.ReviewResult{},
+	}}
+	p := &PRCmd{pending: &ghclient.PendingReview{
+		ID: 7, NodeID: "PRR_7",
+		Comments: []ghclient.PendingComment{{ID: 1, PRReviewComment: ghclient.PRReviewComment{Path: "README.md", Body: "old", Line: 4, Side: "RIGHT"}}},
+	}}
+	if err := p.submitReview(context.Background(), client, ref, results, "COMMENT", ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"discard 7"}; !slices.Equal(calls, want) {
+		t.Errorf("calls = %q, want %q", calls, want)
+	}
+}
+
+func TestPRCmdHosts(t *testing.T) {
+	tests := []struct {
+		name   string
+		hosts  []string
+		apiURL string
+		want   []string
+	}{
+		{name: "none"},
+		{name: "flag", hosts: []string{"ghe.example.com"}, want: []string{"ghe.example.com"}},
+		{name: "api url host", apiURL: "https://ghe.example.com/api/v3/", want: []string{"ghe.example.com"}},
+		{name: "github.com api url", apiURL: "https://api.github.com/"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &PRCmd{Hosts: tt.hosts, APIURL: tt.apiURL}
+			if got := p.hosts(); !slices.Equal(got, tt.want) {
+				t.Errorf("hosts() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,255 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/alecthomas/kong"
+	"github.com/koh-sh/commd/internal/config"
+	"github.com/koh-sh/commd/internal/markdown"
+	"github.com/koh-sh/commd/internal/tui"
+)
+
+// ConfigResolver returns a kong resolver that fills unset flags from cfg.
+// Precedence, lowest first: flag default, config files, environment variables, flags.
+func ConfigResolver(cfg *config.Config) kong.Resolver {
+	return &configResolver{cfg: cfg}
+}
+
+type configResolver struct {
+	cfg *config.Config
+}
+
+// Validate warns about config keys that do not match any flag.
+func (r *configResolver) Validate(app *kong.Application) error {
+	for _, key := range r.cfg.UnknownKeys(configurableFlags(app)) {
+		fmt.Fprintf(os.Stderr, "commd: warning: unknown config key %s\n", key)
+	}
+	return nil
+}
+
+// Resolve returns the configured value for flag, unless one of its
+// environment variables is set (kong has already applied it).
+func (r *configResolver) Resolve(_ *kong.Context, parent *kong.Path, flag *kong.Flag) (any, error) {
+	if parent.Command == nil || !configurable(flag) || envSet(flag.Envs) {
+		return nil, nil
+	}
+	v, _, ok := r.cfg.Lookup(parent.Command.Name, flag.Name)
+	if !ok {
+		return nil, nil
+	}
+	return v, nil
+}
+
+// AfterApply installs the configured comment label set, key bindings and
+// custom themes. Invalid settings, including conflicting key bindings, are
+// reported here, before any TUI starts.
+func (c *CLI) AfterApply(cfg *config.Config) error {
+	ls, err := cfg.Labels.LabelSet()
+	if err != nil {
+		return err
+	}
+	if err := markdown.SetLabels(ls); err != nil {
+		return err
+	}
+	km, err := tui.NewKeyMap(cfg.Keys.Preset, cfg.Keys.Bindings)
+	if err != nil {
+		return fmt.Errorf("invalid key config: %w", err)
+	}
+	c.Review.keyMap = &km
+	c.PR.keyMap = &km
+
+	themes, err := buildThemes(cfg.Themes)
+	if err != nil {
+		return fmt.Errorf("invalid theme config: %w", err)
+	}
+	c.Review.themes = themes
+	c.PR.themes = themes
+	return nil
+}
+
+// buildThemes converts the [themes.<name>] tables into TUI themes.
+func buildThemes(defs map[string]config.Theme) (map[string]tui.Theme, error) {
+	themes := make(map[string]tui.Theme, len(defs))
+	for _, name := range slices.Sorted(maps.Keys(defs)) {
+		def := defs[name]
+		t, err := tui.NewTheme(name, def.Base, def.Colors, def.GlamourStyle)
+		if err != nil {
+			return nil, err
+		}
+		themes[name] = t
+	}
+	return themes, nil
+}
+
+// resolveTheme looks up the theme for the --theme value. When colours are
+// disabled, lipgloss is switched to plain output as well, so every style in
+// the TUI (dialogs and file picker included) drops its colours.
+func resolveTheme(name string, themes map[string]tui.Theme, noColorFlag bool) (tui.Theme, error) {
+	t, err := tui.LookupTheme(name, themes)
+	if err != nil {
+		return tui.Theme{}, err
+	}
+	if noColor(noColorFlag) {
+		t.NoColor = true
+		tui.DisableColor()
+	}
+	return t, nil
+}
+
+// noColor reports whether colours are disabled by flag or by the NO_COLOR
+// convention (any non-empty value, see https://no-color.org).
+func noColor(flag bool) bool {
+	return flag || os.Getenv("NO_COLOR") != ""
+}
+
+// ConfigShowCmd prints the resolved configuration.
+type ConfigShowCmd struct{}
+
+// Run executes the config show subcommand.
+func (c *ConfigShowCmd) Run(ctx *kong.Context, cfg *config.Config) error {
+	return showConfig(os.Stdout, ctx.Model, cfg)
+}
+
+// showConfig writes every configurable flag, label and key setting with its
+// resolved value and source ("default", "env NAME" or a config file path).
+func showConfig(w io.Writer, app *kong.Application, cfg *config.Config) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, node := range configurableCommands(app) {
+		for _, flag := range node.Flags {
+			if !configurable(flag) {
+				continue
+			}
+			value, source := resolveFlag(node.Name, flag, cfg)
+			fmt.Fprintf(tw, "%s.%s\t%s\t%s\n", node.Name, flag.Name, value, source)
+		}
+	}
+
+	ls, err := cfg.Labels.LabelSet()
+	if err != nil {
+		return err
+	}
+	decos := make([]string, 0, len(ls.Decorations))
+	for _, d := range ls.Decorations[1:] {
+		decos = append(decos, string(d))
+	}
+	colors := make([]string, 0, len(ls.Colors))
+	for _, a := range ls.Actions {
+		if c := ls.Color(a); c != "" {
+			colors = append(colors, fmt.Sprintf("%s=%s", a, c))
+		}
+	}
+	labelRows := []struct{ key, value string }{
+		{"actions", joinLabels(ls.Actions)},
+		{"default", string(ls.Default)},
+		{"decorations", strings.Join(decos, ",")},
+		{"colors", strings.Join(colors, ",")},
+	}
+	for _, row := range labelRows {
+		fmt.Fprintf(tw, "labels.%s\t%s\t%s\n", row.key, displayValue(row.value), tableSource(cfg, "labels", row.key))
+	}
+
+	preset := cfg.Keys.Preset
+	if preset == "" {
+		preset = tui.KeyPresetVim
+	}
+	fmt.Fprintf(tw, "keys.preset\t%s\t%s\n", preset, tableSource(cfg, "keys", "preset"))
+	for _, name := range slices.Sorted(maps.Keys(cfg.Keys.Bindings)) {
+		keys := strings.Join(cfg.Keys.Bindings[name], ",")
+		fmt.Fprintf(tw, "keys.%s\t%s\t%s\n", name, displayValue(keys), tableSource(cfg, "keys", name))
+	}
+	return tw.Flush()
+}
+
+// resolveFlag returns the effective value of a flag outside of command-line
+// parsing, and where it came from.
+func resolveFlag(command string, flag *kong.Flag, cfg *config.Config) (value, source string) {
+	for _, env := range flag.Envs {
+		if v, ok := os.LookupEnv(env); ok {
+			return displayValue(v), "env " + env
+		}
+	}
+	if v, path, ok := cfg.Lookup(command, flag.Name); ok {
+		return displayValue(fmt.Sprint(v)), path
+	}
+	def := flag.Default
+	if def == "" && flag.IsBool() {
+		def = "false"
+	}
+	return displayValue(def), "default"
+}
+
+// configurableCommands returns the top-level commands that have visible flags.
+func configurableCommands(app *kong.Application) []*kong.Node {
+	var nodes []*kong.Node
+	for _, node := range app.Children {
+		if node.Type != kong.CommandNode || node.Hidden {
+			continue
+		}
+		for _, flag := range node.Flags {
+			if configurable(flag) {
+				nodes = append(nodes, node)
+				break
+			}
+		}
+	}
+	return nodes
+}
+
+// configurableFlags maps each command name to the flag names that can be set in config.
+func configurableFlags(app *kong.Application) map[string][]string {
+	flags := make(map[string][]string)
+	for _, node := range configurableCommands(app) {
+		for _, flag := range node.Flags {
+			if configurable(flag) {
+				flags[node.Name] = append(flags[node.Name], flag.Name)
+			}
+		}
+	}
+	return flags
+}
+
+// tableSource returns the file that set table.key, or "default".
+func tableSource(cfg *config.Config, table, key string) string {
+	if source := cfg.TableSource(table, key); source != "" {
+		return source
+	}
+	return "default"
+}
+
+// configurable reports whether flag can be set in config. Flags tagged
+// config:"-" only take their value from the command line or environment,
+// e.g. hook flags that override the spawned review's own config.
+func configurable(flag *kong.Flag) bool {
+	return !flag.Hidden && flag.Tag.Get("config") != "-"
+}
+
+func envSet(envs []string) bool {
+	for _, env := range envs {
+		if _, ok := os.LookupEnv(env); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func joinLabels(actions []markdown.ActionType) string {
+	names := make([]string, len(actions))
+	for i, a := range actions {
+		names[i] = string(a)
+	}
+	return strings.Join(names, ",")
+}
+
+// displayValue quotes empty values so that table columns stay aligned.
+func displayValue(v string) string {
+	if v == "" {
+		return `""`
+	}
+	return v
+}
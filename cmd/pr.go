@@ -3,8 +3,11 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"net/url"
 	"os"
 	"slices"
+	"strings"
+	"sync"
 
 	tea "github.com/charmbracelet/bubbletea"
 	ghclient "github.com/koh-sh/commd/internal/github"
@@ -16,19 +19,38 @@ import (
 func (p *PRCmd) Run() error {
 	ctx := context.Background()
 
-	// Parse PR URL
-	ref, err := ghclient.ParsePRURL(p.URL)
+	// Resolve the PR reference
+	resolver := &ghclient.PRResolver{
+		Hosts: p.hosts(),
+		FindPR: func(ctx context.Context, repo *ghclient.PRRef, branch string) (int, error) {
+			client, err := p.githubClient(repo.Host)
+			if err != nil {
+				return 0, err
+			}
+			return client.FindPRForBranch(ctx, repo, branch)
+		},
+	}
+	ref, err := resolver.Resolve(ctx, p.Ref)
 	if err != nil {
 		return err
 	}
+	if p.Ref == "" {
+		fmt.Fprintf(os.Stderr, "Found PR %s/%s#%d for the current branch.\n", ref.Owner, ref.Repo, ref.Number)
+	}
 
-	// Create or reuse GitHub client
-	client := p.client
-	if client == nil {
-		client, err = ghclient.NewClient()
-		if err != nil {
-			return err
-		}
+	policy, err := submitPolicy(p.RequireViewed, p.RequireNoBlocking, p.MinViewed)
+	if err != nil {
+		return err
+	}
+
+	theme, err := resolveTheme(p.Theme, p.themes, p.NoColor)
+	if err != nil {
+		return err
+	}
+
+	client, err := p.githubClient(ref.Host)
+	if err != nil {
+		return err
 	}
 
 	// List changed .md files
@@ -41,12 +63,12 @@ func (p *PRCmd) Run() error {
 		return nil
 	}
 
-	// Build path list and patch map
+	// Build path list and file map
 	paths := make([]string, len(mdFiles))
-	patches := make(map[string]string)
+	files := make(map[string]ghclient.PRFile)
 	for i, f := range mdFiles {
 		paths[i] = f.Path
-		patches[f.Path] = f.Patch
+		files[f.Path] = f
 	}
 
 	// Select files to review
@@ -73,14 +95,39 @@ func (p *PRCmd) Run() error {
 		selectedPaths = result.SelectedFiles
 	}
 
-	// Resolve head SHA once for all file fetches
-	headSHA, err := client.GetHeadSHA(ctx, ref)
+	// Resolve the head and base SHAs once for all file fetches
+	headSHA, baseSHA, err := client.GetCommitSHAs(ctx, ref)
 	if err != nil {
 		return err
 	}
 
+	// Existing review threads are shown read-only; the review works without them
+	threads, err := client.ListReviewThreads(ctx, ref)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+
+	// Resume the user's pending review: its comments are loaded into the TUI
+	// and submitting finalizes it
+	pending, err := client.FindPendingReview(ctx, ref, threads)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	if pending != nil {
+		fmt.Fprintf(os.Stderr, "Resuming your pending review on PR #%d (%d comment(s), %d reply(ies)).\n",
+			ref.Number, len(pending.Comments), len(pending.Replies))
+		p.pending = &ghclient.PendingReview{ID: pending.ID, NodeID: pending.NodeID, Body: pending.Body}
+	}
+
+	// GitHub refuses approvals and change requests from the PR author
+	p.ownPR, err = client.ViewerIsAuthor(ctx, ref)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+
 	// Review each file
 	var results []ghclient.FileReviewResult
+	viewed, sections := 0, 0
 
 	for i, path := range selectedPaths {
 		fmt.Fprintf(os.Stderr, "Fetching %s (%d/%d)...\n", path, i+1, len(selectedPaths))
@@ -99,28 +146,41 @@ func (p *PRCmd) Run() error {
 
 		// Parse diff for this file
 		var diffData *tui.DiffData
-		if patch := patches[path]; patch != "" {
-			if diffInfo := ghclient.ParsePatch(patch); diffInfo != nil {
-				lineMap, sideMap, typeMap := diffInfo.LineSideMap()
-				diffData = &tui.DiffData{
-					DisplayLines: diffInfo.FormatDiffLines(),
-					LineMap:      lineMap,
-					SideMap:      sideMap,
-					TypeMap:      typeMap,
-				}
-			}
+		var baseErr error
+		diffInfo := ghclient.ParsePatch(files[path].Patch)
+		if diffInfo != nil {
+			head := fileLines(source)
+			// The base file is only needed to expand context below the last hunk
+			base := sync.OnceValue(func() []string {
+				lines, err := fetchBaseLines(ctx, client, ref, files[path], baseSHA)
+				baseErr = err
+				return lines
+			})
+			diffData = newExpandableDiffData(diffInfo, head, base)
+			diffData.FullFile = newDiffData(diffInfo.FullFile(head))
 		}
 
+		preloaded, loaded := pendingComments(pending, path, doc)
+		prThreads, loadedReplies := fileThreads(threads, path)
 		app := tui.NewApp(doc, tui.AppOptions{
-			Theme:    p.Theme,
-			FilePath: path,
-			PRMode:   true,
-			Diff:     diffData,
+			Theme:     theme,
+			LeftRatio: p.LeftRatio,
+			KeyMap:    p.keyMap,
+			FilePath:  path,
+			PRMode:    true,
+			Diff:      diffData,
+			Comments:  preloaded,
+			Threads:   prThreads,
+			Policy:    policy,
 		})
 		finalModel, err := runTea(app, p.teaOpts)
 		if err != nil {
 			return fmt.Errorf("running TUI for %s: %w", path, err)
 		}
+		if baseErr != nil {
+			// Not fatal: context was expanded from the head side only
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", baseErr)
+		}
 
 		reviewApp, ok := finalModel.(*tui.App)
 		if !ok {
@@ -131,11 +191,26 @@ func (p *PRCmd) Run() error {
 
 		// Submitted or Approved = done with this file, Cancelled = skipped
 		if appResult.Status == markdown.StatusSubmitted || appResult.Status == markdown.StatusApproved {
-			results = append(results, ghclient.FileReviewResult{
+			fr := ghclient.FileReviewResult{
 				Path:   path,
 				Doc:    doc,
 				Review: appResult.Review,
-			})
+				Diff:   diffInfo,
+			}
+			for _, r := range appResult.Replies {
+				fr.Replies = append(fr.Replies, ghclient.ThreadReply{ThreadID: r.ThreadID, Body: r.Body})
+			}
+			for _, r := range appResult.Resolutions {
+				fr.Resolutions = append(fr.Resolutions, ghclient.ThreadResolution{ThreadID: r.ThreadID, Resolve: r.Resolve})
+			}
+			results = append(results, fr)
+			viewed += appResult.Viewed
+			sections += appResult.Sections
+			// Only the pending comments of reviewed files are replaced on save
+			if p.pending != nil {
+				p.pending.Comments = append(p.pending.Comments, loaded...)
+				p.pending.Replies = append(p.pending.Replies, pendingReplies(pending, loadedReplies)...)
+			}
 		}
 	}
 
@@ -143,11 +218,217 @@ func (p *PRCmd) Run() error {
 	if len(results) == 0 {
 		return nil
 	}
-	return p.showFinalDialog(ctx, client, ref, results)
+	blocked := approvalBlocked(policy, results, viewed, sections)
+	moved, ok, err := p.placeComments(results)
+	if err != nil || !ok {
+		return err
+	}
+	return p.showFinalDialog(ctx, client, ref, results, blocked, moved)
+}
+
+// approvalBlocked lists why policy refuses to approve the reviewed files,
+// of whose sections viewed were marked viewed (nil = allowed).
+func approvalBlocked(policy markdown.Policy, results []ghclient.FileReviewResult, viewed, sections int) []string {
+	if !policy.Enabled() {
+		return nil
+	}
+	var comments []markdown.ReviewComment
+	for _, fr := range results {
+		if fr.Review != nil {
+			comments = append(comments, fr.Review.Comments...)
+		}
+	}
+	return policy.Check(comments, viewed, sections, true).Failures()
+}
+
+// placeComments checks the comments against the diffs before submitting and,
+// if any are outside them, asks whether to move them to the nearest line in
+// the diff or into the review comment, and reports each comment it moved.
+// Returns the comments for the review comment, and false if the user cancelled.
+func (p *PRCmd) placeComments(results []ghclient.FileReviewResult) ([]ghclient.PRReviewComment, bool, error) {
+	misplaced := ghclient.FindMisplacedComments(results)
+	if len(misplaced) == 0 {
+		return nil, true, nil
+	}
+	lines := make([]string, len(misplaced))
+	canRemap := false
+	for i, m := range misplaced {
+		if m.Nearest > 0 {
+			lines[i] = fmt.Sprintf("%s %s: nearest line in the diff is L%d", m.Path, m.Comment.LineRef(), m.Nearest)
+			canRemap = true
+		} else {
+			lines[i] = fmt.Sprintf("%s %s: no line to move to", m.Path, m.Comment.LineRef())
+		}
+	}
+
+	finalModel, err := runTea(tui.NewMisplacedDialog(lines, canRemap), p.teaOpts)
+	if err != nil {
+		return nil, false, fmt.Errorf("running comment placement dialog: %w", err)
+	}
+	md, ok := finalModel.(*tui.MisplacedDialog)
+	if !ok {
+		return nil, false, fmt.Errorf("unexpected model type: %T", finalModel)
+	}
+	action := md.Result()
+	if action == tui.MisplacedCancel {
+		fmt.Fprintln(os.Stderr, "Review cancelled.")
+		return nil, false, nil
+	}
+
+	toNearest := action == tui.MisplacedNearest
+	for _, m := range misplaced {
+		if toNearest && m.Nearest > 0 {
+			fmt.Fprintf(os.Stderr, "Moved comment on %s %s to L%d.\n", m.Path, m.Comment.LineRef(), m.Nearest)
+		} else {
+			fmt.Fprintf(os.Stderr, "Moved comment on %s %s to the review comment.\n", m.Path, m.Comment.LineRef())
+		}
+	}
+	return ghclient.PlaceComments(results, misplaced, toNearest), true, nil
+}
+
+// newDiffData converts a diff for display in the TUI.
+func newDiffData(d *ghclient.DiffInfo) *tui.DiffData {
+	lineMap, sideMap, typeMap := d.LineSideMap()
+	return &tui.DiffData{
+		DisplayLines: d.FormatDiffLines(),
+		LineMap:      lineMap,
+		SideMap:      sideMap,
+		TypeMap:      typeMap,
+		Outside:      d.OutsideMap(),
+	}
+}
+
+// diffContextStep is how many lines of context one expansion adds, as on GitHub.
+const diffContextStep = 20
+
+// newExpandableDiffData converts a diff for display in the TUI, with context
+// that can be expanded from the file's head and base lines (see
+// ghclient.DiffInfo.Expand). base is called on every expansion and should
+// cache its result, e.g. with sync.OnceValue, as it may fetch the base file.
+func newExpandableDiffData(d *ghclient.DiffInfo, head []string, base func() []string) *tui.DiffData {
+	data := newDiffData(d)
+	data.Expand = func(i int, down bool) *tui.DiffData {
+		expanded := d.Expand(i, down, diffContextStep, head, base())
+		if expanded == nil {
+			return nil
+		}
+		return newExpandableDiffData(expanded, head, base)
+	}
+	return data
+}
+
+// fetchBaseLines returns the lines of f on the base side of the PR, or nil
+// for files added in the PR.
+func fetchBaseLines(ctx context.Context, client *ghclient.Client, ref *ghclient.PRRef, f ghclient.PRFile, baseSHA string) ([]string, error) {
+	if f.BasePath == "" || baseSHA == "" {
+		return nil, nil
+	}
+	source, err := client.FetchFileContent(ctx, ref, f.BasePath, baseSHA)
+	if err != nil {
+		return nil, err
+	}
+	return fileLines(source), nil
+}
+
+// fileLines splits file content into lines, without the empty line after
+// the final newline.
+func fileLines(source []byte) []string {
+	return strings.Split(strings.TrimSuffix(string(source), "\n"), "\n")
+}
+
+// fileThreads converts the review threads on path for display in the TUI.
+// Comments of the user's pending review are not shown: new threads are
+// loaded as comments (see pendingComments) and replies become the thread's
+// queued reply. Returns the IDs of the threads with a pending reply.
+func fileThreads(threads []ghclient.ReviewThread, path string) ([]*tui.ReviewThread, []string) {
+	var out []*tui.ReviewThread
+	var replied []string
+	for _, t := range threads {
+		if t.Path != path || len(t.Comments) > 0 && t.Comments[0].Pending {
+			continue
+		}
+		rt := &tui.ReviewThread{
+			ID:       t.ID,
+			Line:     t.Line,
+			Side:     t.Side,
+			Resolved: t.IsResolved,
+			Outdated: t.IsOutdated,
+		}
+		var replies []string
+		for _, c := range t.Comments {
+			if c.Pending {
+				replies = append(replies, c.Body)
+				continue
+			}
+			rt.Comments = append(rt.Comments, tui.ThreadComment{Author: c.Author, Body: c.Body, CreatedAt: c.CreatedAt})
+		}
+		if len(replies) > 0 {
+			rt.Reply = strings.Join(replies, "\n\n")
+			replied = append(replied, t.ID)
+		}
+		out = append(out, rt)
+	}
+	return out, replied
+}
+
+// pendingComments converts the comments of the pending review on path into
+// review comments on doc. Also returns the pending comments it converted;
+// the others (e.g. on outdated lines) are left untouched in the review.
+func pendingComments(pending *ghclient.PendingReview, path string, doc *markdown.Document) ([]markdown.ReviewComment, []ghclient.PendingComment) {
+	if pending == nil {
+		return nil, nil
+	}
+	var comments []markdown.ReviewComment
+	var loaded []ghclient.PendingComment
+	for _, pc := range pending.Comments {
+		if pc.Path != path {
+			continue
+		}
+		if c, ok := ghclient.UnmapComment(pc.PRReviewComment, doc); ok {
+			comments = append(comments, c)
+			loaded = append(loaded, pc)
+		}
+	}
+	return comments, loaded
+}
+
+// pendingReplies returns the pending replies on the given threads.
+func pendingReplies(pending *ghclient.PendingReview, threadIDs []string) []ghclient.PendingComment {
+	var out []ghclient.PendingComment
+	for _, r := range pending.Replies {
+		if slices.Contains(threadIDs, r.ThreadID) {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// githubClient returns the GitHub client for host, creating it on first use.
+func (p *PRCmd) githubClient(host string) (*ghclient.Client, error) {
+	if p.client == nil {
+		client, err := ghclient.NewClient(host, p.APIURL)
+		if err != nil {
+			return nil, err
+		}
+		p.client = client
+	}
+	return p.client, nil
+}
+
+// hosts returns the GitHub Enterprise Server hosts accepted in PR URLs:
+// --host, plus the host of --api-url when it is not github.com's.
+func (p *PRCmd) hosts() []string {
+	hosts := slices.Clone(p.Hosts)
+	if u, err := url.Parse(p.APIURL); err == nil && u.Host != "" && u.Host != "api.github.com" {
+		hosts = append(hosts, u.Host)
+	}
+	return hosts
 }
 
 // showFinalDialog shows the post-review dialog after all files have been reviewed.
-func (p *PRCmd) showFinalDialog(ctx context.Context, client *ghclient.Client, ref *ghclient.PRRef, results []ghclient.FileReviewResult) error {
+// approvalBlocked lists why the submit policy refuses approval (nil = allowed);
+// moved are comments moved to the review comment, which is pre-filled with them.
+func (p *PRCmd) showFinalDialog(ctx context.Context, client *ghclient.Client, ref *ghclient.PRRef, results []ghclient.FileReviewResult, approvalBlocked []string, moved []ghclient.PRReviewComment) error {
 	// Build summary lines
 	var summary []string
 	totalComments := 0
@@ -161,14 +442,36 @@ func (p *PRCmd) showFinalDialog(ctx context.Context, client *ghclient.Client, re
 			summary = append(summary, fmt.Sprintf("%s: %d comment(s)", r.Path, count))
 		}
 	}
-	hasComments := totalComments > 0
-	if hasComments {
+	if totalComments > 0 {
 		summary = append(summary, fmt.Sprintf("Total: %d comment(s)", totalComments))
 	} else {
 		summary = append(summary, "No comments")
 	}
+	threadSummary := threadChangeSummary(results)
+	summary = append(summary, threadSummary...)
+	// Replies and resolutions are posted with a comment review too
+	if len(moved) > 0 {
+		summary = append(summary, fmt.Sprintf("Moved to the review comment: %d comment(s)", len(moved)))
+	}
+	hasComments := totalComments > 0 || len(threadSummary) > 0 || len(moved) > 0
+	replies, _ := threadChanges(results)
 
 	dialog := tui.NewReviewDialog(summary, hasComments)
+	dialog.BlockApproval(approvalBlocked)
+	if totalComments == 0 && len(moved) == 0 && len(replies) == 0 {
+		// Only thread resolutions: GitHub refuses to request changes without a body.
+		dialog.RequireChangesBody()
+	}
+	if p.ownPR {
+		dialog.HideVerdicts()
+	} else {
+		dialog.SuggestRequestChanges(requestChangesReasons(results))
+	}
+	body := ""
+	if p.pending != nil {
+		body = p.pending.Body
+	}
+	dialog.SetBody(ghclient.FoldFileComments(body, moved))
 	finalModel, err := runTea(dialog, p.teaOpts)
 	if err != nil {
 		return fmt.Errorf("running review dialog: %w", err)
@@ -184,53 +487,216 @@ func (p *PRCmd) showFinalDialog(ctx context.Context, client *ghclient.Client, re
 		return p.submitReview(ctx, client, ref, results, "APPROVE", result.Body)
 	case tui.ReviewActionComment:
 		return p.submitReview(ctx, client, ref, results, "COMMENT", result.Body)
+	case tui.ReviewActionRequestChanges:
+		return p.submitReview(ctx, client, ref, results, "REQUEST_CHANGES", result.Body)
+	case tui.ReviewActionPending:
+		return p.savePendingReview(ctx, client, ref, results, result.Body)
 	default:
 		fmt.Fprintln(os.Stderr, "Review cancelled.")
 		return nil
 	}
 }
 
-func (p *PRCmd) submitReview(ctx context.Context, client *ghclient.Client, ref *ghclient.PRRef, results []ghclient.FileReviewResult, event, body string) error {
-	// Warn about overview comments (filtered by BuildPRReview/MapComment, not supported as inline PR comments)
+// requestChangesReasons returns why the review should request changes: the
+// number of blocking and issue comments per file, or nil if there are none.
+func requestChangesReasons(results []ghclient.FileReviewResult) []string {
+	var reasons []string
 	for _, r := range results {
 		if r.Review == nil {
 			continue
 		}
+		blocking, issues := 0, 0
 		for _, c := range r.Review.Comments {
-			if c.SectionID == markdown.OverviewSectionID {
-				fmt.Fprintf(os.Stderr, "Warning: overview comment on %s skipped (not supported as inline PR comment)\n", r.Path)
+			switch {
+			case c.Decoration == markdown.DecorationBlocking:
+				blocking++
+			case c.Action == markdown.ActionIssue:
+				issues++
 			}
 		}
+		var parts []string
+		if blocking > 0 {
+			parts = append(parts, fmt.Sprintf("%d blocking comment(s)", blocking))
+		}
+		if issues > 0 {
+			parts = append(parts, fmt.Sprintf("%d issue(s)", issues))
+		}
+		if len(parts) > 0 {
+			reasons = append(reasons, fmt.Sprintf("%s: %s", r.Path, strings.Join(parts, ", ")))
+		}
 	}
+	return reasons
+}
 
-	review := ghclient.BuildPRReview(results, event, body)
-
-	// Check if any comments remain after filtering
-	if len(review.Comments) == 0 && body == "" && event == "COMMENT" {
-		fmt.Fprintln(os.Stderr, "No comments to submit (all were overview-level).")
-		return nil
+// threadChangeSummary returns the review dialog lines for the replies and
+// resolutions queued on existing threads, or nil if there are none.
+func threadChangeSummary(results []ghclient.FileReviewResult) []string {
+	var replies, resolutions []string
+	for _, r := range results {
+		if n := len(r.Replies); n > 0 {
+			replies = append(replies, fmt.Sprintf("  %s: %d reply(ies)", r.Path, n))
+		}
+		resolve, unresolve := 0, 0
+		for _, res := range r.Resolutions {
+			if res.Resolve {
+				resolve++
+			} else {
+				unresolve++
+			}
+		}
+		var parts []string
+		if resolve > 0 {
+			parts = append(parts, fmt.Sprintf("%d to resolve", resolve))
+		}
+		if unresolve > 0 {
+			parts = append(parts, fmt.Sprintf("%d to unresolve", unresolve))
+		}
+		if len(parts) > 0 {
+			resolutions = append(resolutions, fmt.Sprintf("  %s: %s", r.Path, strings.Join(parts, ", ")))
+		}
 	}
+	var summary []string
+	if len(replies) > 0 {
+		summary = append(summary, "", "Replies to existing threads:")
+		summary = append(summary, replies...)
+	}
+	if len(resolutions) > 0 {
+		summary = append(summary, "", "Thread resolutions:")
+		summary = append(summary, resolutions...)
+	}
+	return summary
+}
+
+func (p *PRCmd) submitReview(ctx context.Context, client *ghclient.Client, ref *ghclient.PRRef, results []ghclient.FileReviewResult, event, body string) error {
+	sub := p.buildSubmission(results, event, body)
+	_, resolutions := threadChanges(results)
 
-	if err := client.SubmitReview(ctx, ref, review); err != nil {
-		// Fallback: print review to stderr to prevent data loss
-		fmt.Fprintf(os.Stderr, "Review content:\n")
-		for _, r := range results {
-			if r.Review != nil {
-				output := markdown.FormatReview(r.Review, r.Doc, r.Path)
-				fmt.Fprint(os.Stderr, output)
+	// Check if anything remains to post
+	if len(sub.Review.Comments) == 0 && len(sub.FileComments) == 0 && len(sub.Replies) == 0 &&
+		sub.Review.GetBody() == "" && event == "COMMENT" {
+		switch {
+		case p.pending != nil:
+			// Every comment of the resumed pending review was removed:
+			// GitHub refuses to submit it empty, so it is deleted instead.
+			if err := client.DiscardPendingReview(ctx, ref, p.pending); err != nil {
+				return err
 			}
+			fmt.Fprintf(os.Stderr, "Pending review on PR #%d discarded: no comments left.\n", ref.Number)
+		case len(resolutions) == 0:
+			fmt.Fprintln(os.Stderr, "No comments to submit.")
+			return nil
 		}
+		resolveThreads(ctx, client, resolutions)
+		return nil
+	}
+
+	folded, err := client.SubmitReviewWith(ctx, ref, p.pending, sub)
+	if err != nil {
+		printReviewFallback(results)
 		return err
 	}
+	warnFolded(folded)
 
-	if event == "APPROVE" {
+	switch event {
+	case "APPROVE":
 		fmt.Fprintf(os.Stderr, "PR #%d approved.\n", ref.Number)
-	} else {
+	case "REQUEST_CHANGES":
+		fmt.Fprintf(os.Stderr, "Changes requested on PR #%d.\n", ref.Number)
+	default:
 		fmt.Fprintf(os.Stderr, "Review submitted to PR #%d.\n", ref.Number)
 	}
+	resolveThreads(ctx, client, resolutions)
 	return nil
 }
 
+// savePendingReview saves the review as a pending review without submitting
+// it, updating the pending review being resumed if there is one.
+func (p *PRCmd) savePendingReview(ctx context.Context, client *ghclient.Client, ref *ghclient.PRRef, results []ghclient.FileReviewResult, body string) error {
+	sub := p.buildSubmission(results, "COMMENT", body)
+	if _, resolutions := threadChanges(results); len(resolutions) > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: %d thread resolution(s) not saved (pending reviews cannot hold them)\n", len(resolutions))
+	}
+
+	folded, err := client.SavePendingReview(ctx, ref, p.pending, sub)
+	if err != nil {
+		printReviewFallback(results)
+		return err
+	}
+	warnFolded(folded)
+	fmt.Fprintf(os.Stderr, "Pending review saved on PR #%d. Run commd pr again to continue it.\n", ref.Number)
+	return nil
+}
+
+// buildSubmission builds the review to post. Overview comments become
+// file-level comments, or part of the review body with --overview-comments body.
+func (p *PRCmd) buildSubmission(results []ghclient.FileReviewResult, event, body string) *ghclient.Submission {
+	fileComments := ghclient.FileComments(results)
+	if p.OverviewComments == "body" {
+		body = ghclient.FoldFileComments(body, fileComments)
+		fileComments = nil
+	}
+	replies, _ := threadChanges(results)
+	return &ghclient.Submission{
+		Review:       ghclient.BuildPRReview(results, event, body),
+		FileComments: fileComments,
+		Replies:      replies,
+	}
+}
+
+// warnFolded reports overview comments that went into the review body
+// because the server refused them as file-level comments.
+func warnFolded(folded int) {
+	if folded > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: %d overview comment(s) added to the review body (file-level comments are not supported by this server)\n", folded)
+	}
+}
+
+// threadChanges collects the replies and resolutions queued on existing threads.
+func threadChanges(results []ghclient.FileReviewResult) ([]ghclient.ThreadReply, []ghclient.ThreadResolution) {
+	var replies []ghclient.ThreadReply
+	var resolutions []ghclient.ThreadResolution
+	for _, r := range results {
+		replies = append(replies, r.Replies...)
+		resolutions = append(resolutions, r.Resolutions...)
+	}
+	return replies, resolutions
+}
+
+// printReviewFallback prints the review to stderr so it is not lost when
+// posting it fails.
+func printReviewFallback(results []ghclient.FileReviewResult) {
+	fmt.Fprintf(os.Stderr, "Review content:\n")
+	for _, r := range results {
+		if r.Review != nil {
+			output := markdown.FormatReview(r.Review, r.Doc, r.Path)
+			fmt.Fprint(os.Stderr, output)
+		}
+	}
+}
+
+// resolveThreads applies the queued thread resolutions after the review is
+// posted. Failures are reported as warnings: the review itself is already in.
+func resolveThreads(ctx context.Context, client *ghclient.Client, resolutions []ghclient.ThreadResolution) {
+	resolved, unresolved := 0, 0
+	for _, r := range resolutions {
+		if err := client.ResolveThread(ctx, r); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			continue
+		}
+		if r.Resolve {
+			resolved++
+		} else {
+			unresolved++
+		}
+	}
+	if resolved > 0 {
+		fmt.Fprintf(os.Stderr, "Resolved %d thread(s).\n", resolved)
+	}
+	if unresolved > 0 {
+		fmt.Fprintf(os.Stderr, "Unresolved %d thread(s).\n", unresolved)
+	}
+}
+
 // runTea creates and runs a Bubble Tea program with alt screen and optional extra options.
 func runTea(model tea.Model, extraOpts []tea.ProgramOption) (tea.Model, error) {
 	opts := append([]tea.ProgramOption{tea.WithAltScreen()}, extraOpts...)
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"text/template"
 
 	"github.com/atotto/clipboard"
 	"github.com/koh-sh/commd/internal/markdown"
@@ -45,6 +46,44 @@ func writeReviewOutput(output, mode, outputPath string) error {
 	return nil
 }
 
+// loadResumedReview reads a review file written by FormatReview and returns its
+// comments and verdicts mapped onto doc. Line comments are re-anchored to the
+// lines they quote or suggest to change (see markdown.ReanchorComments);
+// those whose lines are gone are flagged as orphaned. Comments whose section
+// no longer exists are reported on stderr and dropped.
+func loadResumedReview(path string, doc *markdown.Document) (*markdown.ReviewResult, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading review file: %w", err)
+	}
+	result, orphans, err := markdown.ParseReview(data, doc)
+	if err != nil {
+		return nil, fmt.Errorf("parsing review file %s: %w", path, err)
+	}
+	for _, c := range orphans {
+		target := c.SectionID
+		if ref := c.FormatLineRef(); ref != "" {
+			target = ref
+		}
+		fmt.Fprintf(os.Stderr, "commd: warning: dropped comment on %s (no longer in document): [%s] %s\n", target, c.FormatLabel(), c.Body)
+	}
+	result.Comments = markdown.ReanchorComments(result.Comments, doc)
+	return result, nil
+}
+
+// submitPolicy builds the submit policy from the policy flags.
+func submitPolicy(requireViewed, requireNoBlocking bool, minViewed int) (markdown.Policy, error) {
+	policy := markdown.Policy{
+		RequireViewed: requireViewed,
+		NoBlocking:    requireNoBlocking,
+		MinViewed:     minViewed,
+	}
+	if err := policy.Validate(); err != nil {
+		return markdown.Policy{}, fmt.Errorf("invalid submit policy: %w", err)
+	}
+	return policy, nil
+}
+
 // Run executes the review subcommand.
 func (r *ReviewCmd) Run() error {
 	// Read file
@@ -59,11 +98,44 @@ func (r *ReviewCmd) Run() error {
 		return fmt.Errorf("parsing file: %w", err)
 	}
 
+	policy, err := submitPolicy(r.RequireViewed, r.RequireNoBlocking, r.MinViewed)
+	if err != nil {
+		return err
+	}
+
+	// Load comments and verdicts from a previous review
+	resumed := &markdown.ReviewResult{}
+	if r.Resume != "" {
+		resumed, err = loadResumedReview(r.Resume, p)
+		if err != nil {
+			return err
+		}
+	}
+
+	theme, err := resolveTheme(r.Theme, r.themes, r.NoColor)
+	if err != nil {
+		return err
+	}
+
+	// Parse the output template up front so mistakes surface before the review
+	var tmpl *template.Template
+	if r.Template != "" {
+		if tmpl, err = markdown.ParseReviewTemplate(r.Template); err != nil {
+			return err
+		}
+	}
+
 	// Create and run TUI
 	app := tui.NewApp(p, tui.AppOptions{
-		Theme:       r.Theme,
+		Theme:       theme,
+		LeftRatio:   r.LeftRatio,
+		KeyMap:      r.keyMap,
 		FilePath:    r.File,
 		TrackViewed: r.TrackViewed,
+		Comments:    resumed.Comments,
+		Verdicts:    resumed.Verdicts,
+		SaveDrafts:  r.Drafts,
+		Policy:      policy,
 	})
 	finalModel, err := runTea(app, r.teaOpts)
 	if err != nil {
@@ -75,6 +147,10 @@ func (r *ReviewCmd) Run() error {
 		return fmt.Errorf("unexpected model type: %T", finalModel)
 	}
 
+	for _, w := range app.Warnings() {
+		fmt.Fprintf(os.Stderr, "commd: warning: %s\n", w)
+	}
+
 	// Save viewed state if tracking is enabled
 	if r.TrackViewed {
 		if vs := app.ViewedState(); vs != nil {
@@ -88,8 +164,11 @@ func (r *ReviewCmd) Run() error {
 	result := app.Result()
 
 	// Output review if submitted
+	if result.Status == markdown.StatusSubmitted && result.Review != nil && r.Output == "annotate" {
+		return r.writeAnnotated(string(source), p, result.Review.Comments)
+	}
 	if result.Status == markdown.StatusSubmitted && result.Review != nil {
-		output := markdown.FormatReview(result.Review, p, r.File)
+		output := r.formatReview(tmpl, result.Review, p)
 		if output == "" {
 			return nil
 		}
@@ -103,3 +182,36 @@ func (r *ReviewCmd) Run() error {
 
 	return nil
 }
+
+// formatReview renders the submitted review in the --format format.
+func (r *ReviewCmd) formatReview(tmpl *template.Template, review *markdown.ReviewResult, doc *markdown.Document) string {
+	if r.Format == "json" {
+		return markdown.FormatReviewJSON(review, doc, r.File)
+	}
+	opts := markdown.FormatOptions{Quote: r.Quote, Order: r.Order}
+	output, err := markdown.RenderReview(tmpl, review, doc, r.File, opts)
+	if err != nil {
+		// Fall back to the built-in format so the comments are not lost
+		fmt.Fprintf(os.Stderr, "commd: warning: %v; using the built-in format\n", err)
+		output = markdown.FormatReviewWith(review, doc, r.File, opts)
+	}
+	return output
+}
+
+// writeAnnotated writes a copy of the reviewed document with the comments
+// inserted as annotations, to --output-path or <name>.annotated.md.
+func (r *ReviewCmd) writeAnnotated(source string, doc *markdown.Document, comments []markdown.ReviewComment) error {
+	if len(comments) == 0 {
+		return nil
+	}
+	path := r.OutputPath
+	if path == "" {
+		path = markdown.AnnotatedPath(r.File)
+	}
+	annotated := markdown.Annotate(source, doc, comments, r.Annotate)
+	if err := os.WriteFile(path, []byte(annotated), 0o644); err != nil {
+		return fmt.Errorf("writing annotated copy: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "Annotated copy written to %s\n", path)
+	return nil
+}
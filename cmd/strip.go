@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/koh-sh/commd/internal/markdown"
+)
+
+// Run executes the strip subcommand.
+func (s *StripCmd) Run() error {
+	source, err := os.ReadFile(s.File)
+	if err != nil {
+		return fmt.Errorf("reading file: %w", err)
+	}
+	stripped := markdown.Strip(string(source))
+	if !s.Write {
+		fmt.Print(stripped)
+		return nil
+	}
+	if err := os.WriteFile(s.File, []byte(stripped), 0o644); err != nil {
+		return fmt.Errorf("writing file: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "Annotations removed from %s\n", s.File)
+	return nil
+}
@@ -4,15 +4,20 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/koh-sh/commd/internal/cclocate"
+	"github.com/koh-sh/commd/internal/markdown"
 	"github.com/koh-sh/commd/internal/pane"
 )
 
 // RunConfig holds configuration for the hook runner.
 type RunConfig struct {
-	Spawner pane.PaneSpawner
-	Theme   string
+	Spawner   pane.PaneSpawner
+	Theme     string // color theme ("" = review default)
+	LeftRatio int    // section list width in percent (0 = review default)
+	NoColor   bool   // pass --no-color; the spawned pane may not inherit NO_COLOR
 }
 
 // Run executes the hook orchestration flow.
@@ -59,15 +64,26 @@ func Run(input *Input, cfg RunConfig) (int, error) {
 		executable = "commd"
 	}
 
-	// Build review args
+	// Build review args. Display settings are only passed when set on the
+	// hook; otherwise the review resolves them from its own config. The
+	// format is always Markdown so that rejectedSteps can read the review.
 	args := []string{
 		"review",
 		"--output", "file",
 		"--output-path", reviewPath,
-		"--theme", cfg.Theme,
+		"--format", "markdown",
 		"--track-viewed",
-		planFile,
 	}
+	if cfg.Theme != "" {
+		args = append(args, "--theme", cfg.Theme)
+	}
+	if cfg.LeftRatio != 0 {
+		args = append(args, "--left-ratio", strconv.Itoa(cfg.LeftRatio))
+	}
+	if cfg.NoColor {
+		args = append(args, "--no-color")
+	}
+	args = append(args, planFile)
 
 	// Spawn review in pane
 	ctx := context.Background()
@@ -92,8 +108,48 @@ func Run(input *Input, cfg RunConfig) (int, error) {
 	}
 	if review := string(reviewBytes); review != "" {
 		fmt.Fprint(os.Stderr, review)
+		fmt.Fprint(os.Stderr, formatRejectedSteps(rejectedSteps(planFile, reviewBytes)))
 		return 2, nil
 	}
 
 	return 0, nil
 }
+
+// rejectedSteps returns the headings ("S3: Title") of the plan sections the
+// review rejects, in plan order. Reviews that cannot be read back (e.g. from
+// a custom template) have none.
+func rejectedSteps(planFile string, review []byte) []string {
+	source, err := os.ReadFile(planFile)
+	if err != nil {
+		return nil
+	}
+	doc, err := markdown.Parse(source)
+	if err != nil {
+		return nil
+	}
+	result, _, err := markdown.ParseReview(review, doc)
+	if err != nil {
+		return nil
+	}
+	var steps []string
+	for _, v := range markdown.SectionVerdicts(result, doc) {
+		if v.Verdict == markdown.VerdictReject {
+			steps = append(steps, v.Heading)
+		}
+	}
+	return steps
+}
+
+// formatRejectedSteps lists rejected steps after the review so Claude does not
+// miss them. Returns "" if there are none.
+func formatRejectedSteps(steps []string) string {
+	if len(steps) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString("\nRejected steps (do not carry these out as written; revise or drop them):\n")
+	for _, s := range steps {
+		fmt.Fprintf(&sb, "- %s\n", s)
+	}
+	return sb.String()
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/koh-sh/commd/internal/cclocate"
@@ -235,6 +236,59 @@ func TestRunFileNotFound(t *testing.T) {
 	}
 }
 
+func TestRunReviewArgs(t *testing.T) {
+	_, planFile, cwd := setupPlanEnv(t)
+	input := &Input{
+		HookInput:      cclocate.HookInput{CWD: cwd},
+		PermissionMode: "plan",
+		ToolInput:      &ToolInput{FilePath: planFile},
+	}
+
+	tests := []struct {
+		name    string
+		cfg     RunConfig
+		want    []string
+		notWant []string
+	}{
+		{
+			name:    "unset settings are left to the review config",
+			want:    []string{"--format markdown"},
+			notWant: []string{"--theme", "--left-ratio", "--no-color"},
+		},
+		{
+			name: "hook settings are passed",
+			cfg:  RunConfig{Theme: "light", LeftRatio: 40, NoColor: true},
+			want: []string{"--format markdown", "--theme light", "--left-ratio 40", "--no-color"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			tt.cfg.Spawner = &mockSpawner{
+				available: true,
+				name:      "mock",
+				spawnFunc: func(cmd string, args []string) error {
+					got = strings.Join(args, " ")
+					return nil
+				},
+			}
+			if _, err := Run(input, tt.cfg); err != nil {
+				t.Fatal(err)
+			}
+			for _, w := range tt.want {
+				if !strings.Contains(got, w) {
+					t.Errorf("args %q missing %q", got, w)
+				}
+			}
+			for _, w := range tt.notWant {
+				if strings.Contains(got, w) {
+					t.Errorf("args %q should not contain %q", got, w)
+				}
+			}
+		})
+	}
+}
+
 func TestRunSpawnFailure(t *testing.T) {
 	_, planFile, cwd := setupPlanEnv(t)
 
@@ -294,3 +348,32 @@ func TestRunReviewFileRemoved(t *testing.T) {
 		t.Errorf("exit code = %d, want 0", code)
 	}
 }
+
+func TestRejectedSteps(t *testing.T) {
+	planFile := filepath.Join(t.TempDir(), "plan.md")
+	plan := "# Plan\n\n## Setup\n\nInstall.\n\n## Migrate\n\nRun migrations.\n\n## Deploy\n\nShip it.\n"
+	if err := os.WriteFile(planFile, []byte(plan), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	review := "# Review\n\nPlease review and address the following comments on: plan.md\n\n" +
+		"## Verdicts\n- `S1: Setup` approve\n- `S3: Deploy` reject\n- `S2: Migrate` reject\n"
+
+	steps := rejectedSteps(planFile, []byte(review))
+	if len(steps) != 2 || steps[0] != "S2: Migrate" || steps[1] != "S3: Deploy" {
+		t.Fatalf("rejectedSteps() = %q, want S2 then S3", steps)
+	}
+	want := "\nRejected steps (do not carry these out as written; revise or drop them):\n- S2: Migrate\n- S3: Deploy\n"
+	if got := formatRejectedSteps(steps); got != want {
+		t.Errorf("formatRejectedSteps() = %q, want %q", got, want)
+	}
+
+	if got := rejectedSteps(planFile, []byte(`{"verdicts":[{"id":"S2","verdict":"reject"}]}`)); got != nil {
+		t.Errorf("JSON review: rejectedSteps() = %q, want nil", got)
+	}
+	if got := rejectedSteps(planFile, []byte("custom template output")); got != nil {
+		t.Errorf("unparsable review: rejectedSteps() = %q, want nil", got)
+	}
+	if got := formatRejectedSteps(nil); got != "" {
+		t.Errorf("formatRejectedSteps(nil) = %q, want empty", got)
+	}
+}
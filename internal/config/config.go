@@ -0,0 +1,382 @@
+// Package config loads commd settings from the user-level and repo-level config files.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/koh-sh/commd/internal/markdown"
+	"github.com/pelletier/go-toml/v2"
+)
+
+// RepoFileName is the name of the repo-level config file.
+const RepoFileName = ".commd.toml"
+
+// Config is the merged contents of the commd config files.
+//
+// Besides the [labels] table, a config file holds flag defaults: top-level keys
+// apply to every command and a table named after a command (e.g. [review])
+// applies to that command only. Keys are flag names; snake_case and camelCase
+// spellings are accepted as well.
+type Config struct {
+	Labels Labels           `toml:"labels"`
+	Keys   Keys             `toml:"-"`
+	Themes map[string]Theme `toml:"themes"`
+
+	files []file // loaded files, lowest precedence first
+}
+
+// file is the raw contents of one config file with keys normalized to flag names.
+type file struct {
+	path   string
+	values map[string]any
+}
+
+// Labels configures the comment label set. Unset fields keep the built-in values.
+type Labels struct {
+	Actions     []string          `toml:"actions"`     // action labels in cycle order
+	Default     string            `toml:"default"`     // label preselected for new comments
+	Decorations []string          `toml:"decorations"` // allowed decorations in cycle order
+	Colors      map[string]string `toml:"colors"`      // label name -> colour
+}
+
+// Keys configures TUI key bindings.
+type Keys struct {
+	Preset   string              // "vim" (default) or "emacs"
+	Bindings map[string][]string // binding name -> keys, applied on top of the preset
+}
+
+// Theme defines a custom colour theme under [themes.<name>].
+type Theme struct {
+	Base         string            `toml:"base"`          // built-in theme to start from (default "dark")
+	GlamourStyle string            `toml:"glamour_style"` // path to a glamour JSON style file
+	Colors       map[string]string `toml:"colors"`        // colour name (e.g. "diff-added") -> colour
+}
+
+// UserPath returns the path of the user-level config file:
+// $XDG_CONFIG_HOME/commd/config.toml, falling back to ~/.config/commd/config.toml.
+// Returns "" if neither location can be determined.
+func UserPath() string {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "commd", "config.toml")
+}
+
+// RepoPath returns the path of the nearest .commd.toml found by walking up
+// from dir, stopping at the repository root (a directory containing .git).
+// Returns "" if none is found.
+func RepoPath(dir string) string {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+	for {
+		p := filepath.Join(dir, RepoFileName)
+		if _, err := os.Stat(p); err == nil {
+			return p
+		}
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return ""
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// Load reads the user-level config and the repo-level config for dir.
+// Settings in the repo-level file override those in the user-level file.
+// Missing files are not an error.
+func Load(dir string) (*Config, error) {
+	cfg := &Config{}
+	for _, path := range []string{UserPath(), RepoPath(dir)} {
+		if path == "" {
+			continue
+		}
+		fc, err := LoadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if fc != nil {
+			cfg.merge(fc)
+		}
+	}
+	return cfg, nil
+}
+
+// LoadFile reads a single config file. Returns nil, nil if the file does not exist.
+func LoadFile(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading config: %w", err)
+	}
+	var cfg Config
+	if err := toml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parsing config %s: %w", path, err)
+	}
+	var raw map[string]any
+	if err := toml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("parsing config %s: %w", path, err)
+	}
+	values := normalizeKeys(raw)
+	if cfg.Keys, err = parseKeys(values["keys"]); err != nil {
+		return nil, fmt.Errorf("parsing config %s: %w", path, err)
+	}
+	for name, t := range cfg.Themes {
+		cfg.Themes[name] = t.normalize(filepath.Dir(path))
+	}
+	cfg.files = []file{{path: path, values: values}}
+	return &cfg, nil
+}
+
+// merge overlays the fields set in o onto c.
+func (c *Config) merge(o *Config) {
+	if o.Labels.Actions != nil {
+		c.Labels.Actions = o.Labels.Actions
+	}
+	if o.Labels.Default != "" {
+		c.Labels.Default = o.Labels.Default
+	}
+	if o.Labels.Decorations != nil {
+		c.Labels.Decorations = o.Labels.Decorations
+	}
+	if o.Labels.Colors != nil {
+		c.Labels.Colors = o.Labels.Colors
+	}
+	if o.Keys.Preset != "" {
+		c.Keys.Preset = o.Keys.Preset
+	}
+	for name, keys := range o.Keys.Bindings {
+		if c.Keys.Bindings == nil {
+			c.Keys.Bindings = make(map[string][]string)
+		}
+		c.Keys.Bindings[name] = keys
+	}
+	for name, t := range o.Themes {
+		if c.Themes == nil {
+			c.Themes = make(map[string]Theme)
+		}
+		c.Themes[name] = t
+	}
+	c.files = append(c.files, o.files...)
+}
+
+// normalize converts colour names to kebab-case and resolves a relative
+// glamour style path against dir, the directory of the defining file.
+func (t Theme) normalize(dir string) Theme {
+	if t.GlamourStyle != "" && !filepath.IsAbs(t.GlamourStyle) {
+		t.GlamourStyle = filepath.Join(dir, t.GlamourStyle)
+	}
+	if t.Colors != nil {
+		colors := make(map[string]string, len(t.Colors))
+		for k, v := range t.Colors {
+			colors[flagName(k)] = v
+		}
+		t.Colors = colors
+	}
+	return t
+}
+
+// parseKeys reads the [keys] table. Each binding is a key string or an array of key strings.
+func parseKeys(v any) (Keys, error) {
+	var keys Keys
+	if v == nil {
+		return keys, nil
+	}
+	table, ok := v.(map[string]any)
+	if !ok {
+		return keys, errors.New("keys must be a table")
+	}
+	for name, val := range table {
+		if name == "preset" {
+			preset, ok := val.(string)
+			if !ok {
+				return keys, errors.New("keys.preset must be a string")
+			}
+			keys.Preset = preset
+			continue
+		}
+		var list []string
+		switch val := val.(type) {
+		case string:
+			list = []string{val}
+		case []any:
+			for _, item := range val {
+				s, ok := item.(string)
+				if !ok {
+					return keys, fmt.Errorf("keys.%s must be a string or an array of strings", name)
+				}
+				list = append(list, s)
+			}
+		default:
+			return keys, fmt.Errorf("keys.%s must be a string or an array of strings", name)
+		}
+		if keys.Bindings == nil {
+			keys.Bindings = make(map[string][]string)
+		}
+		keys.Bindings[name] = list
+	}
+	return keys, nil
+}
+
+// Lookup returns the configured default for the flag named key of command,
+// and the path of the file it came from. A [command] table entry takes
+// precedence over a top-level key in the same file, and later files take
+// precedence over earlier ones.
+func (c *Config) Lookup(command, key string) (value any, source string, ok bool) {
+	for i := len(c.files) - 1; i >= 0; i-- {
+		f := c.files[i]
+		if table, isTable := f.values[command].(map[string]any); isTable {
+			if v, found := table[key]; found {
+				return v, f.path, true
+			}
+		}
+		if v, found := f.values[key]; found {
+			if _, isTable := v.(map[string]any); !isTable {
+				return v, f.path, true
+			}
+		}
+	}
+	return nil, "", false
+}
+
+// TableSource returns the path of the file that set key in the given table
+// (e.g. "labels"), or "" if the key is not configured.
+func (c *Config) TableSource(table, key string) string {
+	for i := len(c.files) - 1; i >= 0; i-- {
+		if table, ok := c.files[i].values[table].(map[string]any); ok {
+			if _, found := table[key]; found {
+				return c.files[i].path
+			}
+		}
+	}
+	return ""
+}
+
+// UnknownKeys returns the config keys that do not name a known flag, formatted
+// as "path: key". flags maps each command name to its flag names.
+func (c *Config) UnknownKeys(flags map[string][]string) []string {
+	var unknown []string
+	for _, f := range c.files {
+		for _, key := range sortedKeys(f.values) {
+			v := f.values[key]
+			if key == "labels" || key == "keys" || key == "themes" {
+				continue
+			}
+			if table, isTable := v.(map[string]any); isTable {
+				names, isCommand := flags[key]
+				if !isCommand {
+					unknown = append(unknown, fmt.Sprintf("%s: [%s]", f.path, key))
+					continue
+				}
+				for _, k := range sortedKeys(table) {
+					if !slices.Contains(names, k) {
+						unknown = append(unknown, fmt.Sprintf("%s: %s.%s", f.path, key, k))
+					}
+				}
+				continue
+			}
+			known := false
+			for _, names := range flags {
+				if slices.Contains(names, key) {
+					known = true
+					break
+				}
+			}
+			if !known {
+				unknown = append(unknown, fmt.Sprintf("%s: %s", f.path, key))
+			}
+		}
+	}
+	return unknown
+}
+
+// normalizeKeys converts the keys of raw and of its nested tables to kebab-case flag names.
+func normalizeKeys(raw map[string]any) map[string]any {
+	out := make(map[string]any, len(raw))
+	for k, v := range raw {
+		if table, ok := v.(map[string]any); ok && k != "labels" && k != "themes" {
+			v = normalizeKeys(table)
+		}
+		out[flagName(k)] = v
+	}
+	return out
+}
+
+// flagName converts a snake_case or camelCase key to the kebab-case flag name.
+func flagName(key string) string {
+	var sb strings.Builder
+	for i, r := range key {
+		switch {
+		case r == '_':
+			sb.WriteByte('-')
+		case unicode.IsUpper(r):
+			if i > 0 {
+				sb.WriteByte('-')
+			}
+			sb.WriteRune(unicode.ToLower(r))
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+func sortedKeys(m map[string]any) []string {
+	return slices.Sorted(maps.Keys(m))
+}
+
+// LabelSet builds the comment label set, starting from the built-in
+// Conventional Comments labels and applying the configured overrides.
+func (l Labels) LabelSet() (markdown.LabelSet, error) {
+	ls := markdown.DefaultLabels()
+	if l.Actions != nil {
+		ls.Actions = make([]markdown.ActionType, len(l.Actions))
+		for i, a := range l.Actions {
+			ls.Actions[i] = markdown.ActionType(a)
+		}
+		if l.Default == "" && len(ls.Actions) > 0 && !ls.HasAction(ls.Default) {
+			ls.Default = ls.Actions[0]
+		}
+	}
+	if l.Default != "" {
+		ls.Default = markdown.ActionType(l.Default)
+	}
+	if l.Decorations != nil {
+		ls.Decorations = []markdown.Decoration{markdown.DecorationNone}
+		for _, d := range l.Decorations {
+			if d == "" {
+				continue
+			}
+			ls.Decorations = append(ls.Decorations, markdown.Decoration(d))
+		}
+	}
+	if l.Colors != nil {
+		ls.Colors = make(map[markdown.ActionType]string, len(l.Colors))
+		for a, color := range l.Colors {
+			ls.Colors[markdown.ActionType(a)] = color
+		}
+	}
+	if err := ls.Validate(); err != nil {
+		return markdown.LabelSet{}, fmt.Errorf("invalid label config: %w", err)
+	}
+	return ls, nil
+}
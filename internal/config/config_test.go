@@ -0,0 +1,338 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/koh-sh/commd/internal/markdown"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUserPath(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", "/xdg")
+	if got := UserPath(); got != filepath.Join("/xdg", "commd", "config.toml") {
+		t.Errorf("UserPath() = %q", got)
+	}
+
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("HOME", "/home/u")
+	if got := UserPath(); got != filepath.Join("/home/u", ".config", "commd", "config.toml") {
+		t.Errorf("UserPath() fallback = %q", got)
+	}
+}
+
+func TestRepoPath(t *testing.T) {
+	root := t.TempDir()
+	sub := filepath.Join(root, "a", "b")
+	if err := os.MkdirAll(filepath.Join(root, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := RepoPath(sub); got != "" {
+		t.Errorf("RepoPath() without file = %q, want empty", got)
+	}
+
+	writeFile(t, filepath.Join(root, RepoFileName), "")
+	if got := RepoPath(sub); got != filepath.Join(root, RepoFileName) {
+		t.Errorf("RepoPath() = %q, want repo root file", got)
+	}
+}
+
+func TestLoadLayering(t *testing.T) {
+	xdg := t.TempDir()
+	repo := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", xdg)
+	writeFile(t, filepath.Join(repo, ".git", "HEAD"), "")
+
+	writeFile(t, filepath.Join(xdg, "commd", "config.toml"), `
+[labels]
+default = "note"
+decorations = ["non-blocking", "blocking"]
+
+[labels.colors]
+issue = "196"
+`)
+	writeFile(t, filepath.Join(repo, RepoFileName), `
+[labels]
+actions = ["issue", "note", "security", "perf", "decision"]
+decorations = ["blocking", "ux", "legal"]
+`)
+
+	cfg, err := Load(repo)
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	ls, err := cfg.Labels.LabelSet()
+	if err != nil {
+		t.Fatalf("LabelSet() error: %v", err)
+	}
+
+	wantActions := []markdown.ActionType{"issue", "note", "security", "perf", "decision"}
+	if !slices.Equal(ls.Actions, wantActions) {
+		t.Errorf("Actions = %v, want %v", ls.Actions, wantActions)
+	}
+	if ls.Default != "note" {
+		t.Errorf("Default = %s, want note (from user config)", ls.Default)
+	}
+	wantDecos := []markdown.Decoration{markdown.DecorationNone, "blocking", "ux", "legal"}
+	if !slices.Equal(ls.Decorations, wantDecos) {
+		t.Errorf("Decorations = %v, want %v", ls.Decorations, wantDecos)
+	}
+	if ls.Color("issue") != "196" {
+		t.Errorf("Color(issue) = %q, want 196", ls.Color("issue"))
+	}
+}
+
+func TestLoadNoFiles(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	repo := t.TempDir()
+	writeFile(t, filepath.Join(repo, ".git", "HEAD"), "")
+
+	cfg, err := Load(repo)
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	ls, err := cfg.Labels.LabelSet()
+	if err != nil {
+		t.Fatalf("LabelSet() error: %v", err)
+	}
+	if !slices.Equal(ls.Actions, markdown.ActionLabels) || ls.Default != markdown.DefaultAction {
+		t.Errorf("LabelSet() = %+v, want built-in labels", ls)
+	}
+}
+
+func TestLabelSetDefaultFallsBackToFirstAction(t *testing.T) {
+	ls, err := Labels{Actions: []string{"security", "perf"}}.LabelSet()
+	if err != nil {
+		t.Fatalf("LabelSet() error: %v", err)
+	}
+	if ls.Default != "security" {
+		t.Errorf("Default = %s, want security", ls.Default)
+	}
+}
+
+func TestLoadFileErrors(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "bad.toml")
+	writeFile(t, path, "[labels\n")
+	if _, err := LoadFile(path); err == nil || !strings.Contains(err.Error(), "parsing config") {
+		t.Errorf("LoadFile() error = %v, want parsing error", err)
+	}
+
+	if _, err := (Labels{Actions: []string{"issue"}, Default: "note"}).LabelSet(); err == nil {
+		t.Error("LabelSet() with unknown default should fail")
+	}
+}
+
+func TestLookup(t *testing.T) {
+	xdg := t.TempDir()
+	repo := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", xdg)
+	writeFile(t, filepath.Join(repo, ".git", "HEAD"), "")
+	userPath := filepath.Join(xdg, "commd", "config.toml")
+	repoPath := filepath.Join(repo, RepoFileName)
+
+	writeFile(t, userPath, `
+theme = "light"
+left_ratio = 35
+
+[review]
+output = "stdout"
+`)
+	writeFile(t, repoPath, `
+[review]
+leftRatio = 45
+
+[pr]
+theme = "dark"
+`)
+
+	cfg, err := Load(repo)
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+
+	tests := []struct {
+		command, key string
+		want         any
+		wantSource   string
+	}{
+		{"review", "theme", "light", userPath},
+		{"review", "output", "stdout", userPath},
+		{"review", "left-ratio", int64(45), repoPath},
+		{"pr", "left-ratio", int64(35), userPath},
+		{"pr", "theme", "dark", repoPath},
+		{"cchook", "spawner", nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.command+"."+tt.key, func(t *testing.T) {
+			got, source, ok := cfg.Lookup(tt.command, tt.key)
+			if ok != (tt.want != nil) || got != tt.want || source != tt.wantSource {
+				t.Errorf("Lookup() = %v, %q, %v; want %v, %q", got, source, ok, tt.want, tt.wantSource)
+			}
+		})
+	}
+}
+
+func TestUnknownKeys(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.toml")
+	writeFile(t, path, `
+theme = "dark"
+colour = "red"
+
+[review]
+output = "file"
+spawner = "tmux"
+
+[reviews]
+theme = "light"
+
+[labels]
+actions = ["issue"]
+`)
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile() error: %v", err)
+	}
+
+	got := cfg.UnknownKeys(map[string][]string{
+		"review": {"output", "theme"},
+		"cchook": {"spawner", "theme"},
+	})
+	want := []string{
+		path + ": colour",
+		path + ": review.spawner",
+		path + ": [reviews]",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("UnknownKeys() = %v, want %v", got, want)
+	}
+}
+
+func TestFlagName(t *testing.T) {
+	tests := map[string]string{
+		"theme":        "theme",
+		"left-ratio":   "left-ratio",
+		"left_ratio":   "left-ratio",
+		"leftRatio":    "left-ratio",
+		"track_viewed": "track-viewed",
+	}
+	for in, want := range tests {
+		if got := flagName(in); got != want {
+			t.Errorf("flagName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestLoadKeys(t *testing.T) {
+	xdg := t.TempDir()
+	repo := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", xdg)
+	writeFile(t, filepath.Join(repo, ".git", "HEAD"), "")
+
+	writeFile(t, filepath.Join(xdg, "commd", "config.toml"), `
+[keys]
+preset = "emacs"
+submit = "S"
+commentList = ["ctrl+l", "L"]
+`)
+	writeFile(t, filepath.Join(repo, RepoFileName), `
+[keys]
+submit = "ctrl+x"
+`)
+
+	cfg, err := Load(repo)
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if cfg.Keys.Preset != "emacs" {
+		t.Errorf("Preset = %q, want emacs", cfg.Keys.Preset)
+	}
+	want := map[string][]string{
+		"submit":       {"ctrl+x"},
+		"comment-list": {"ctrl+l", "L"},
+	}
+	if len(cfg.Keys.Bindings) != len(want) {
+		t.Errorf("Bindings = %v, want %v", cfg.Keys.Bindings, want)
+	}
+	for name, keys := range want {
+		if !slices.Equal(cfg.Keys.Bindings[name], keys) {
+			t.Errorf("Bindings[%s] = %v, want %v", name, cfg.Keys.Bindings[name], keys)
+		}
+	}
+	if got := cfg.TableSource("keys", "submit"); got != filepath.Join(repo, RepoFileName) {
+		t.Errorf("TableSource(keys, submit) = %q, want repo config", got)
+	}
+}
+
+func TestLoadKeysInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	writeFile(t, path, "[keys]\nsubmit = 1\n")
+	if _, err := LoadFile(path); err == nil || !strings.Contains(err.Error(), "keys.submit") {
+		t.Errorf("LoadFile() error = %v, want keys.submit error", err)
+	}
+}
+
+func TestLoadThemes(t *testing.T) {
+	xdg := t.TempDir()
+	repo := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", xdg)
+	writeFile(t, filepath.Join(repo, ".git", "HEAD"), "")
+
+	writeFile(t, filepath.Join(xdg, "commd", "config.toml"), `
+[themes.myTheme]
+base = "light"
+glamour_style = "styles/glamour.json"
+
+[themes.myTheme.colors]
+diff_added = "#00ff00"
+commentBoxBorder = "99"
+
+[themes.other]
+base = "dark"
+`)
+	writeFile(t, filepath.Join(repo, RepoFileName), `
+[themes.other]
+base = "solarized"
+`)
+
+	cfg, err := Load(repo)
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	mine, ok := cfg.Themes["myTheme"]
+	if !ok {
+		t.Fatalf("Themes = %v, want myTheme (theme names are not normalized)", cfg.Themes)
+	}
+	if mine.Base != "light" {
+		t.Errorf("Base = %q, want light", mine.Base)
+	}
+	if want := filepath.Join(xdg, "commd", "styles", "glamour.json"); mine.GlamourStyle != want {
+		t.Errorf("GlamourStyle = %q, want %q (relative to the config file)", mine.GlamourStyle, want)
+	}
+	if mine.Colors["diff-added"] != "#00ff00" || mine.Colors["comment-box-border"] != "99" {
+		t.Errorf("Colors = %v, want kebab-case names", mine.Colors)
+	}
+	if cfg.Themes["other"].Base != "solarized" {
+		t.Errorf("other.Base = %q, want repo config to override", cfg.Themes["other"].Base)
+	}
+	if unknown := cfg.UnknownKeys(nil); len(unknown) != 0 {
+		t.Errorf("UnknownKeys() = %v, want themes table to be ignored", unknown)
+	}
+}
@@ -17,19 +17,36 @@ type Client struct {
 	inner *gh.Client
 }
 
-// NewClient creates a GitHub client using available authentication.
-// Priority: GITHUB_TOKEN env var > gh auth token command.
-func NewClient() (*Client, error) {
-	token, err := resolveToken()
+// NewClient creates a GitHub client for host using available authentication.
+// An empty host means github.com; for other hosts the client talks to the
+// GitHub Enterprise Server API at https://{host}/api/v3/. See resolveToken for
+// where the token comes from. apiURL, if set, overrides the API base URL.
+func NewClient(host, apiURL string) (*Client, error) {
+	if host == "" {
+		host = DefaultHost
+	}
+	token, err := resolveToken(host)
 	if err != nil {
 		return nil, err
 	}
+	return newClient(nil, host, apiURL, token)
+}
 
-	client := gh.NewClient(nil).WithAuthToken(token)
-	if baseURL := os.Getenv("COMMD_GITHUB_API_URL"); baseURL != "" {
-		parsed, err := client.BaseURL.Parse(baseURL)
+// newClient creates a Client for host authenticated with token.
+func newClient(httpClient *http.Client, host, apiURL, token string) (*Client, error) {
+	client := gh.NewClient(httpClient).WithAuthToken(token)
+	if host != DefaultHost {
+		base := "https://" + host + "/"
+		enterprise, err := client.WithEnterpriseURLs(base, base)
+		if err != nil {
+			return nil, fmt.Errorf("invalid GitHub Enterprise Server host %q: %w", host, err)
+		}
+		client = enterprise
+	}
+	if apiURL != "" {
+		parsed, err := client.BaseURL.Parse(apiURL)
 		if err != nil {
-			return nil, fmt.Errorf("invalid COMMD_GITHUB_API_URL %q: %w", baseURL, err)
+			return nil, fmt.Errorf("invalid GitHub API URL %q: %w", apiURL, err)
 		}
 		client.BaseURL = parsed
 	}
@@ -45,13 +62,23 @@ func NewClientWithHTTP(httpClient *http.Client, baseURL string) *Client {
 	return &Client{inner: client}
 }
 
-// resolveToken returns a GitHub token from environment or gh CLI.
-func resolveToken() (string, error) {
-	if token := strings.TrimSpace(os.Getenv("GITHUB_TOKEN")); token != "" {
-		return token, nil
+// resolveToken returns a token for host, like the gh CLI does.
+// github.com: GITHUB_TOKEN env var > gh auth token.
+// Other hosts: GH_ENTERPRISE_TOKEN > GITHUB_ENTERPRISE_TOKEN > gh auth token --hostname host.
+func resolveToken(host string) (string, error) {
+	envVars := []string{"GITHUB_TOKEN"}
+	args := []string{"auth", "token"}
+	if host != DefaultHost {
+		envVars = []string{"GH_ENTERPRISE_TOKEN", "GITHUB_ENTERPRISE_TOKEN"}
+		args = append(args, "--hostname", host)
+	}
+	for _, name := range envVars {
+		if token := strings.TrimSpace(os.Getenv(name)); token != "" {
+			return token, nil
+		}
 	}
 
-	out, err := exec.Command("gh", "auth", "token").Output()
+	out, err := exec.Command("gh", args...).Output()
 	if err == nil {
 		token := strings.TrimSpace(string(out))
 		if token != "" {
@@ -59,22 +86,58 @@ func resolveToken() (string, error) {
 		}
 	}
 
+	if host != DefaultHost {
+		return "", fmt.Errorf("GitHub token for %s not found. Set GH_ENTERPRISE_TOKEN or run 'gh auth login --hostname %s'", host, host)
+	}
 	return "", fmt.Errorf("GitHub token not found. Set GITHUB_TOKEN or run 'gh auth login'")
 }
 
 // GetHeadSHA returns the head commit SHA for a pull request.
 func (c *Client) GetHeadSHA(ctx context.Context, ref *PRRef) (string, error) {
+	head, _, err := c.GetCommitSHAs(ctx, ref)
+	return head, err
+}
+
+// GetCommitSHAs returns the head and base commit SHAs for a pull request.
+func (c *Client) GetCommitSHAs(ctx context.Context, ref *PRRef) (head, base string, err error) {
 	pr, _, err := c.inner.PullRequests.Get(ctx, ref.Owner, ref.Repo, ref.Number)
 	if err != nil {
-		return "", fmt.Errorf("getting PR: %w", err)
+		return "", "", fmt.Errorf("getting PR: %w", err)
 	}
 
-	sha := pr.GetHead().GetSHA()
-	// Defensive fallback: use ref name if SHA is empty.
-	if sha == "" {
-		sha = pr.GetHead().GetRef()
+	head = pr.GetHead().GetSHA()
+	base = pr.GetBase().GetSHA()
+	// Defensive fallback: use ref names if SHAs are empty.
+	if head == "" {
+		head = pr.GetHead().GetRef()
+	}
+	if base == "" {
+		base = pr.GetBase().GetRef()
+	}
+	return head, base, nil
+}
+
+const viewerDidAuthorQuery = `query($owner: String!, $repo: String!, $number: Int!) {
+  repository(owner: $owner, name: $repo) {
+    pullRequest(number: $number) { viewerDidAuthor }
+  }
+}`
+
+// ViewerIsAuthor reports whether the authenticated user opened the pull
+// request. GitHub refuses approvals and change requests from the author.
+func (c *Client) ViewerIsAuthor(ctx context.Context, ref *PRRef) (bool, error) {
+	var data struct {
+		Repository struct {
+			PullRequest struct {
+				ViewerDidAuthor bool
+			}
+		}
+	}
+	vars := map[string]any{"owner": ref.Owner, "repo": ref.Repo, "number": ref.Number}
+	if err := c.graphQL(ctx, viewerDidAuthorQuery, vars, &data); err != nil {
+		return false, fmt.Errorf("checking PR author: %w", err)
 	}
-	return sha, nil
+	return data.Repository.PullRequest.ViewerDidAuthor, nil
 }
 
 // ListMDFiles returns all .md files changed/added in a PR with their patches.
@@ -105,9 +168,17 @@ func (c *Client) ListMDFiles(ctx context.Context, ref *PRRef) ([]PRFile, error)
 		if filepath.Ext(name) != ".md" {
 			continue
 		}
+		basePath := name
+		switch f.GetStatus() {
+		case "added":
+			basePath = ""
+		case "renamed":
+			basePath = f.GetPreviousFilename()
+		}
 		files = append(files, PRFile{
-			Path:  name,
-			Patch: f.GetPatch(),
+			Path:     name,
+			BasePath: basePath,
+			Patch:    f.GetPatch(),
 		})
 	}
 
@@ -134,3 +205,50 @@ func (c *Client) FetchFileContent(ctx context.Context, ref *PRRef, path, headSHA
 
 	return []byte(decoded), nil
 }
+
+// FindPRForBranch returns the number of the open pull request in repo whose
+// head branch is branch. PRs from the same repository are preferred over PRs
+// from forks. A branch name alone does not tell forks apart, so if PRs from
+// several forks use it, an error listing them is returned.
+func (c *Client) FindPRForBranch(ctx context.Context, repo *PRRef, branch string) (int, error) {
+	prs, _, err := c.inner.PullRequests.List(ctx, repo.Owner, repo.Repo, &gh.PullRequestListOptions{
+		State:       "open",
+		Head:        repo.Owner + ":" + branch,
+		ListOptions: gh.ListOptions{PerPage: 1},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("listing PRs: %w", err)
+	}
+	if len(prs) > 0 {
+		return prs[0].GetNumber(), nil
+	}
+
+	// Fall back to PRs from forks.
+	prs, _, err = c.inner.PullRequests.List(ctx, repo.Owner, repo.Repo, &gh.PullRequestListOptions{
+		State:       "open",
+		Sort:        "updated",
+		Direction:   "desc",
+		ListOptions: gh.ListOptions{PerPage: 100},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("listing PRs: %w", err)
+	}
+	var matches []*gh.PullRequest
+	for _, pr := range prs {
+		if pr.GetHead().GetRef() == branch {
+			matches = append(matches, pr)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return 0, fmt.Errorf("no open PR found for branch %q in %s/%s", branch, repo.Owner, repo.Repo)
+	case 1:
+		return matches[0].GetNumber(), nil
+	}
+	found := make([]string, len(matches))
+	for i, pr := range matches {
+		found[i] = fmt.Sprintf("#%d (%s)", pr.GetNumber(), pr.GetHead().GetRepo().GetFullName())
+	}
+	return 0, fmt.Errorf("ambiguous branch %q: open PRs from several forks in %s/%s use it: %s; pass the PR number",
+		branch, repo.Owner, repo.Repo, strings.Join(found, ", "))
+}
@@ -6,6 +6,8 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"strings"
 	"testing"
 )
 
@@ -22,6 +24,7 @@ func TestListMDFiles(t *testing.T) {
 		name      string
 		files     []map[string]string
 		wantPaths []string
+		wantBase  []string
 		wantErr   bool
 	}{
 		{
@@ -33,6 +36,7 @@ func TestListMDFiles(t *testing.T) {
 				{"filename": "old.md", "status": "removed"},
 			},
 			wantPaths: []string{"README.md", "docs/guide.md"},
+			wantBase:  []string{"README.md", ""},
 		},
 		{
 			name:      "no md files returns empty",
@@ -42,9 +46,10 @@ func TestListMDFiles(t *testing.T) {
 		{
 			name: "renamed md file included",
 			files: []map[string]string{
-				{"filename": "new-name.md", "status": "renamed"},
+				{"filename": "new-name.md", "status": "renamed", "previous_filename": "old-name.md"},
 			},
 			wantPaths: []string{"new-name.md"},
+			wantBase:  []string{"old-name.md"},
 		},
 	}
 
@@ -78,6 +83,9 @@ func TestListMDFiles(t *testing.T) {
 				if got[i].Path != want {
 					t.Errorf("file[%d].Path = %q, want %q", i, got[i].Path, want)
 				}
+				if got[i].BasePath != tt.wantBase[i] {
+					t.Errorf("file[%d].BasePath = %q, want %q", i, got[i].BasePath, tt.wantBase[i])
+				}
 			}
 		})
 	}
@@ -209,33 +217,74 @@ func TestGetHeadSHA(t *testing.T) {
 	}
 }
 
+func TestGetCommitSHAs(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/owner/repo/pulls/1", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(t, w, map[string]any{
+			"head": map[string]any{"sha": "abc123", "ref": "feature"},
+			"base": map[string]any{"sha": "", "ref": "main"},
+		})
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	client := NewClientWithHTTP(srv.Client(), srv.URL+"/")
+	head, base, err := client.GetCommitSHAs(context.Background(), &PRRef{Owner: "owner", Repo: "repo", Number: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if head != "abc123" || base != "main" {
+		t.Errorf("GetCommitSHAs() = %q, %q, want abc123, main", head, base)
+	}
+}
+
 func TestResolveToken(t *testing.T) {
 	tests := []struct {
 		name    string
-		envVar  string
+		host    string
+		env     map[string]string
+		want    string
 		wantErr bool
 	}{
 		{
-			name:   "from GITHUB_TOKEN env var",
-			envVar: "test-token-123",
+			name: "from GITHUB_TOKEN env var",
+			host: DefaultHost,
+			env:  map[string]string{"GITHUB_TOKEN": "test-token-123"},
+			want: "test-token-123",
 		},
 		{
 			name:    "no token available",
-			envVar:  "",
+			host:    DefaultHost,
+			wantErr: true,
+		},
+		{
+			name: "enterprise host from GH_ENTERPRISE_TOKEN",
+			host: "github.example.com",
+			env:  map[string]string{"GITHUB_TOKEN": "dotcom", "GH_ENTERPRISE_TOKEN": "ghes", "GITHUB_ENTERPRISE_TOKEN": "other"},
+			want: "ghes",
+		},
+		{
+			name: "enterprise host from GITHUB_ENTERPRISE_TOKEN",
+			host: "github.example.com",
+			env:  map[string]string{"GITHUB_ENTERPRISE_TOKEN": "ghes"},
+			want: "ghes",
+		},
+		{
+			name:    "enterprise host ignores GITHUB_TOKEN",
+			host:    "github.example.com",
+			env:     map[string]string{"GITHUB_TOKEN": "dotcom"},
 			wantErr: true,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if tt.envVar != "" {
-				t.Setenv("GITHUB_TOKEN", tt.envVar)
-			} else {
-				t.Setenv("GITHUB_TOKEN", "")
-				t.Setenv("PATH", "")
+			for _, name := range []string{"GITHUB_TOKEN", "GH_ENTERPRISE_TOKEN", "GITHUB_ENTERPRISE_TOKEN"} {
+				t.Setenv(name, tt.env[name])
 			}
+			t.Setenv("PATH", "") // no gh CLI fallback
 
-			got, err := resolveToken()
+			got, err := resolveToken(tt.host)
 			if tt.wantErr {
 				if err == nil {
 					t.Fatal("expected error, got nil")
@@ -245,8 +294,8 @@ func TestResolveToken(t *testing.T) {
 			if err != nil {
 				t.Fatalf("unexpected error: %v", err)
 			}
-			if got != tt.envVar {
-				t.Errorf("got %q, want %q", got, tt.envVar)
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
 			}
 		})
 	}
@@ -254,7 +303,7 @@ func TestResolveToken(t *testing.T) {
 
 func TestNewClient(t *testing.T) {
 	t.Setenv("GITHUB_TOKEN", "test-token")
-	client, err := NewClient()
+	client, err := NewClient("", "")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -275,9 +324,7 @@ func TestNewClientWithCustomBaseURL(t *testing.T) {
 	t.Cleanup(srv.Close)
 
 	t.Setenv("GITHUB_TOKEN", "test-token")
-	t.Setenv("COMMD_GITHUB_API_URL", srv.URL+"/")
-
-	client, err := NewClient()
+	client, err := NewClient("", srv.URL+"/")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -292,3 +339,198 @@ func TestNewClientWithCustomBaseURL(t *testing.T) {
 		t.Errorf("got SHA %q, want %q", sha, "abc123")
 	}
 }
+
+func TestNewClientEnterpriseHost(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v3/repos/owner/repo/pulls/1", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer ghes-token" {
+			t.Errorf("Authorization = %q, want the enterprise token", got)
+		}
+		writeJSON(t, w, map[string]any{
+			"head": map[string]any{"sha": "abc123", "ref": "feature"},
+		})
+	})
+	srv := httptest.NewTLSServer(mux)
+	t.Cleanup(srv.Close)
+
+	host := strings.TrimPrefix(srv.URL, "https://")
+	client, err := newClient(srv.Client(), host, "", "ghes-token")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, want := client.inner.UploadURL.String(), "https://"+host+"/api/uploads/"; got != want {
+		t.Errorf("UploadURL = %q, want %q", got, want)
+	}
+
+	ref := &PRRef{Host: host, Owner: "owner", Repo: "repo", Number: 1}
+	sha, err := client.GetHeadSHA(context.Background(), ref)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sha != "abc123" {
+		t.Errorf("got SHA %q, want %q", sha, "abc123")
+	}
+}
+
+func TestFindPRForBranch(t *testing.T) {
+	tests := []struct {
+		name    string
+		branch  string
+		want    int
+		wantErr bool
+		wantMsg string
+	}{
+		{name: "same repository", branch: "feature", want: 3},
+		{name: "fork", branch: "fork-branch", want: 5},
+		{name: "no PR", branch: "other", wantErr: true},
+		{name: "several forks", branch: "patch-1", wantErr: true, wantMsg: "#6 (alice/repo), #7 (bob/repo)"},
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/owner/repo/pulls", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("state") != "open" {
+			t.Errorf("state = %q, want open", r.URL.Query().Get("state"))
+		}
+		switch r.URL.Query().Get("head") {
+		case "owner:feature":
+			writeJSON(t, w, []map[string]any{{"number": 3, "head": map[string]any{"ref": "feature"}}})
+		case "":
+			writeJSON(t, w, []map[string]any{
+				{"number": 4, "head": map[string]any{"ref": "main"}},
+				{"number": 5, "head": map[string]any{"ref": "fork-branch"}},
+				{"number": 6, "head": map[string]any{"ref": "patch-1", "repo": map[string]any{"full_name": "alice/repo"}}},
+				{"number": 7, "head": map[string]any{"ref": "patch-1", "repo": map[string]any{"full_name": "bob/repo"}}},
+			})
+		default:
+			writeJSON(t, w, []map[string]any{})
+		}
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	client := NewClientWithHTTP(srv.Client(), srv.URL+"/")
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := client.FindPRForBranch(context.Background(), &PRRef{Owner: "owner", Repo: "repo"}, tt.branch)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("got PR #%d, want error", got)
+				}
+				if tt.wantMsg != "" && !strings.Contains(err.Error(), tt.wantMsg) {
+					t.Errorf("error = %v, want %q", err, tt.wantMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got PR #%d, want #%d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListReviewThreads(t *testing.T) {
+	pages := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /graphql", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query     string
+			Variables map[string]any
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decoding request: %v", err)
+		}
+		if req.Variables["owner"] != "owner" || req.Variables["number"] != float64(1) {
+			t.Errorf("variables = %v", req.Variables)
+		}
+		pages++
+		thread := map[string]any{
+			"id": "T1", "isResolved": true, "isOutdated": false, "path": "README.md", "line": 5, "diffSide": "RIGHT",
+			"comments": map[string]any{"nodes": []any{map[string]any{
+				"databaseId": 11, "author": map[string]any{"login": "alice"}, "body": "Typo", "createdAt": "2026-01-02T03:04:05Z",
+			}}},
+		}
+		pageInfo := map[string]any{"hasNextPage": true, "endCursor": "c1"}
+		if req.Variables["after"] == "c1" {
+			thread = map[string]any{"id": "T2", "isOutdated": true, "path": "docs/a.md", "line": nil, "diffSide": "LEFT"}
+			pageInfo = map[string]any{"hasNextPage": false}
+		}
+		writeJSON(t, w, map[string]any{"data": map[string]any{"repository": map[string]any{"pullRequest": map[string]any{
+			"reviewThreads": map[string]any{"pageInfo": pageInfo, "nodes": []any{thread}},
+		}}}})
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	client := NewClientWithHTTP(srv.Client(), srv.URL+"/")
+	threads, err := client.ListReviewThreads(context.Background(), &PRRef{Owner: "owner", Repo: "repo", Number: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pages != 2 || len(threads) != 2 {
+		t.Fatalf("got %d threads in %d pages, want 2 in 2", len(threads), pages)
+	}
+	if th := threads[0]; th.ID != "T1" || !th.IsResolved || th.Line != 5 || th.Side != SideRight ||
+		len(th.Comments) != 1 || th.Comments[0].ID != 11 || th.Comments[0].Author != "alice" || th.Comments[0].CreatedAt.Year() != 2026 {
+		t.Errorf("thread = %+v", th)
+	}
+	if th := threads[1]; th.ID != "T2" || !th.IsOutdated || th.Line != 0 || th.Path != "docs/a.md" {
+		t.Errorf("outdated thread = %+v", th)
+	}
+}
+
+func TestListReviewThreadsError(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /graphql", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(t, w, map[string]any{"errors": []any{map[string]any{"message": "Could not resolve to a PullRequest"}}})
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	client := NewClientWithHTTP(srv.Client(), srv.URL+"/")
+	_, err := client.ListReviewThreads(context.Background(), &PRRef{Owner: "owner", Repo: "repo", Number: 1})
+	if err == nil || !strings.Contains(err.Error(), "Could not resolve") {
+		t.Errorf("err = %v, want the GraphQL error", err)
+	}
+}
+
+func TestViewerIsAuthor(t *testing.T) {
+	for _, want := range []bool{true, false} {
+		t.Run(strconv.FormatBool(want), func(t *testing.T) {
+			mux := http.NewServeMux()
+			mux.HandleFunc("POST /graphql", func(w http.ResponseWriter, _ *http.Request) {
+				writeJSON(t, w, map[string]any{"data": map[string]any{"repository": map[string]any{
+					"pullRequest": map[string]any{"viewerDidAuthor": want},
+				}}})
+			})
+			srv := httptest.NewServer(mux)
+			t.Cleanup(srv.Close)
+
+			client := NewClientWithHTTP(srv.Client(), srv.URL+"/")
+			got, err := client.ViewerIsAuthor(context.Background(), &PRRef{Owner: "owner", Repo: "repo", Number: 1})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != want {
+				t.Errorf("ViewerIsAuthor() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestGraphQLURL(t *testing.T) {
+	tests := []struct {
+		base string
+		want string
+	}{
+		{"https://api.github.com/", "https://api.github.com/graphql"},
+		{"https://github.example.com/api/v3/", "https://github.example.com/api/graphql"},
+		{"http://127.0.0.1:8080/", "http://127.0.0.1:8080/graphql"},
+	}
+	for _, tt := range tests {
+		if got := NewClientWithHTTP(nil, tt.base).graphQLURL(); got != tt.want {
+			t.Errorf("graphQLURL() for %s = %q, want %q", tt.base, got, tt.want)
+		}
+	}
+}
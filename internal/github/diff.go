@@ -2,6 +2,7 @@ package github
 
 import (
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
 )
@@ -27,6 +28,7 @@ type DiffLine struct {
 	Content string // line content without the +/-/space prefix
 	NewLine int    // 1-based line number in the new file (0 for removed lines)
 	OldLine int    // 1-based line number in the old file (0 for added lines)
+	Outside bool   // not part of the patch: shown for context, commented on at file level
 }
 
 // DiffInfo contains parsed diff data for a PR file.
@@ -123,6 +125,171 @@ func (d *DiffInfo) LineSideMap() (lineMap []int, sideMap []string, typeMap []byt
 	return
 }
 
+// OutsideMap maps each display index to whether the line is outside the
+// patch (see DiffLine.Outside).
+func (d *DiffInfo) OutsideMap() []bool {
+	outside := make([]bool, len(d.Lines))
+	for i, dl := range d.Lines {
+		outside[i] = dl.Outside
+	}
+	return outside
+}
+
+// FullFile lays the diff over source, the file's content on the head side,
+// for a full-file view: every line of source in order, with the removed
+// lines inline before the line that replaced them. Lines the patch does not
+// cover are context lines marked Outside.
+func (d *DiffInfo) FullFile(source []string) *DiffInfo {
+	inPatch := make(map[int]DiffLine)
+	removed := make(map[int][]DiffLine) // keyed by the new line they precede
+	delta := 0                          // new line - old line, so far
+	for _, dl := range d.Lines {
+		switch dl.Type {
+		case DiffRemoved:
+			removed[dl.OldLine+delta] = append(removed[dl.OldLine+delta], dl)
+			delta--
+		case DiffAdded:
+			inPatch[dl.NewLine] = dl
+			delta++
+		default:
+			inPatch[dl.NewLine] = dl
+		}
+	}
+
+	full := &DiffInfo{}
+	delta = 0
+	for n := 1; n <= len(source)+1; n++ {
+		full.Lines = append(full.Lines, removed[n]...)
+		delta -= len(removed[n])
+		if n > len(source) {
+			break
+		}
+		if dl, ok := inPatch[n]; ok {
+			full.Lines = append(full.Lines, dl)
+			if dl.Type == DiffAdded {
+				delta++
+			}
+			continue
+		}
+		full.Lines = append(full.Lines, DiffLine{Type: DiffContext, Content: source[n-1], NewLine: n, OldLine: n - delta, Outside: true})
+	}
+	return full
+}
+
+// Gap is a run of unchanged lines that the diff leaves out: before the
+// first hunk, between two hunks or after the last one.
+type Gap struct {
+	Index   int // display index of the line that follows the gap
+	NewLine int // first line of the gap in the new file
+	OldLine int // first line of the gap in the old file
+	Count   int // number of lines
+}
+
+// Gaps returns the gaps of the diff, given the file's content on the head
+// side and on the base side (nil = unknown). Unchanged lines are the same on
+// both sides: the content bounds the gap after the last hunk.
+func (d *DiffInfo) Gaps(head, base []string) []Gap {
+	var gaps []Gap
+	next, delta := 1, 0 // next new line, new line - old line
+	for i, dl := range d.Lines {
+		start := dl.NewLine
+		if dl.Type == DiffRemoved {
+			start = dl.OldLine + delta
+		}
+		if end := min(start, len(head)+1); end > next {
+			gaps = append(gaps, Gap{Index: i, NewLine: next, OldLine: next - delta, Count: end - next})
+		}
+		switch dl.Type {
+		case DiffRemoved:
+			delta--
+		case DiffAdded:
+			delta++
+			next = dl.NewLine + 1
+		default:
+			next = dl.NewLine + 1
+		}
+	}
+	end := len(head)
+	if base != nil {
+		end = min(end, len(base)+delta)
+	}
+	if end >= next {
+		gaps = append(gaps, Gap{Index: len(d.Lines), NewLine: next, OldLine: next - delta, Count: end - next + 1})
+	}
+	return gaps
+}
+
+// Expand returns a copy of the diff with up to n lines of a gap filled in
+// from head, as context lines marked Outside. With down, the gap below
+// display index i is filled from its top; otherwise the gap above i is filled
+// from its bottom, so that the lines join the hunk at i. Returns nil if there
+// is no such gap.
+func (d *DiffInfo) Expand(i int, down bool, n int, head, base []string) *DiffInfo {
+	var gap *Gap
+	gaps := d.Gaps(head, base)
+	for k := range gaps {
+		if down && gaps[k].Index > i {
+			gap = &gaps[k]
+			break
+		}
+		if !down && gaps[k].Index <= i {
+			gap = &gaps[k]
+		}
+	}
+	if gap == nil {
+		return nil
+	}
+
+	n = min(n, gap.Count)
+	first := gap.NewLine
+	if !down {
+		first += gap.Count - n
+	}
+	delta := gap.NewLine - gap.OldLine
+	lines := make([]DiffLine, n)
+	for k := range lines {
+		line := first + k
+		lines[k] = DiffLine{Type: DiffContext, Content: head[line-1], NewLine: line, OldLine: line - delta, Outside: true}
+	}
+	return &DiffInfo{Lines: slices.Concat(d.Lines[:gap.Index], lines, d.Lines[gap.Index:])}
+}
+
+// Commentable reports whether a review comment can be placed on line on the
+// given side: GitHub only accepts lines inside the diff hunks. Removed lines
+// are on the left side, added lines on the right and context lines on both.
+func (d *DiffInfo) Commentable(line int, side string) bool {
+	for _, dl := range d.Lines {
+		if side == SideLeft && dl.Type != DiffAdded && dl.OldLine == line ||
+			side != SideLeft && dl.Type != DiffRemoved && dl.NewLine == line {
+			return true
+		}
+	}
+	return false
+}
+
+// NearestCommentable returns the right-side line nearest to line that is
+// commentable and accepted by keep, preferring the later line on a tie, or
+// 0 if there is none.
+func (d *DiffInfo) NearestCommentable(line int, keep func(int) bool) int {
+	best := 0
+	for _, dl := range d.Lines {
+		if dl.Type == DiffRemoved || !keep(dl.NewLine) {
+			continue
+		}
+		if best == 0 || abs(dl.NewLine-line) <= abs(best-line) {
+			best = dl.NewLine
+		}
+	}
+	return best
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
 // parseHunkHeader parses "@@ -old,count +new,count @@" and returns (newStart, oldStart).
 func parseHunkHeader(line string) (int, int) {
 	newStart := 1
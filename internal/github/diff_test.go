@@ -1,6 +1,8 @@
 package github
 
 import (
+	"fmt"
+	"slices"
 	"testing"
 )
 
@@ -176,3 +178,197 @@ func TestLineSideMap(t *testing.T) {
 		}
 	}
 }
+
+func TestCommentable(t *testing.T) {
+	// new lines 10-12 and 20; old line 11 removed
+	info := ParsePatch("@@ -10,3 +10,3 @@\n ctx\n-old\n+new\n ctx\n@@ -20,1 +20,1 @@\n ctx")
+	tests := []struct {
+		line int
+		side string
+		want bool
+	}{
+		{10, SideRight, true},
+		{11, SideRight, true},
+		{12, SideRight, true},
+		{13, SideRight, false},
+		{20, SideRight, true},
+		{11, SideLeft, true},
+		{10, SideLeft, true},
+		{15, SideLeft, false},
+	}
+	for _, tt := range tests {
+		if got := info.Commentable(tt.line, tt.side); got != tt.want {
+			t.Errorf("Commentable(%d, %s) = %v, want %v", tt.line, tt.side, got, tt.want)
+		}
+	}
+}
+
+func TestNearestCommentable(t *testing.T) {
+	info := ParsePatch("@@ -10,2 +10,2 @@\n ctx\n+new\n@@ -20,1 +20,1 @@\n ctx")
+	all := func(int) bool { return true }
+	tests := []struct {
+		name string
+		line int
+		keep func(int) bool
+		want int
+	}{
+		{"before the diff", 3, all, 10},
+		{"between hunks, closer to the second", 16, all, 20},
+		{"tie prefers the later line", 15, func(l int) bool { return l != 11 }, 20},
+		{"restricted", 30, func(l int) bool { return l < 15 }, 11},
+		{"none accepted", 30, func(int) bool { return false }, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := info.NearestCommentable(tt.line, tt.keep); got != tt.want {
+				t.Errorf("NearestCommentable(%d) = %d, want %d", tt.line, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFullFile(t *testing.T) {
+	// old: a b c d e f, new: a B c d e f g; the patch covers lines 1-3 only
+	info := ParsePatch("@@ -1,3 +1,3 @@\n a\n-b\n+B\n c")
+	source := []string{"a", "B", "c", "d", "e", "f", "g"}
+	full := info.FullFile(source)
+
+	want := []DiffLine{
+		{Type: DiffContext, Content: "a", NewLine: 1, OldLine: 1},
+		{Type: DiffRemoved, Content: "b", OldLine: 2},
+		{Type: DiffAdded, Content: "B", NewLine: 2},
+		{Type: DiffContext, Content: "c", NewLine: 3, OldLine: 3},
+		{Type: DiffContext, Content: "d", NewLine: 4, OldLine: 4, Outside: true},
+		{Type: DiffContext, Content: "e", NewLine: 5, OldLine: 5, Outside: true},
+		{Type: DiffContext, Content: "f", NewLine: 6, OldLine: 6, Outside: true},
+		{Type: DiffContext, Content: "g", NewLine: 7, OldLine: 7, Outside: true},
+	}
+	if !slices.Equal(full.Lines, want) {
+		t.Errorf("FullFile() =\n%+v\nwant\n%+v", full.Lines, want)
+	}
+	if outside := full.OutsideMap(); !slices.Equal(outside, []bool{false, false, false, false, true, true, true, true}) {
+		t.Errorf("OutsideMap() = %v", outside)
+	}
+}
+
+func TestFullFileOffsets(t *testing.T) {
+	// Line 2 added, old lines 5-6 removed at the end of the file
+	info := ParsePatch("@@ -1,1 +1,2 @@\n a\n+x\n@@ -4,3 +5,1 @@\n d\n-e\n-f")
+	source := []string{"a", "x", "b", "c", "d"}
+	full := info.FullFile(source)
+
+	var got []string
+	for _, dl := range full.Lines {
+		got = append(got, fmt.Sprintf("%c%s %d/%d %v", dl.Type, dl.Content, dl.NewLine, dl.OldLine, dl.Outside))
+	}
+	want := []string{
+		" a 1/1 false",
+		"+x 2/0 false",
+		" b 3/2 true",
+		" c 4/3 true",
+		" d 5/4 false",
+		"-e 0/5 false",
+		"-f 0/6 false",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("FullFile() = %q, want %q", got, want)
+	}
+}
+
+// gapTestDiff returns a diff with a line added at 6 and old line 21 changed,
+// in a file of 30 lines on the base side and 31 on the head side.
+func gapTestDiff() (*DiffInfo, []string, []string) {
+	info := ParsePatch("@@ -5,3 +5,4 @@\n l5\n+x\n l6\n l7\n@@ -20,2 +21,2 @@\n l20\n-l21\n+L21")
+	var base, head []string
+	for n := 1; n <= 30; n++ {
+		base = append(base, fmt.Sprintf("l%d", n))
+	}
+	head = slices.Concat(base[:5], []string{"x"}, base[5:20], []string{"L21"}, base[21:])
+	return info, head, base
+}
+
+func TestGaps(t *testing.T) {
+	info, head, base := gapTestDiff()
+	tests := []struct {
+		name string
+		base []string
+		want []Gap
+	}{
+		{"with base", base, []Gap{
+			{Index: 0, NewLine: 1, OldLine: 1, Count: 4},
+			{Index: 4, NewLine: 9, OldLine: 8, Count: 12},
+			{Index: 7, NewLine: 23, OldLine: 22, Count: 9},
+		}},
+		{"shorter base bounds the last gap", base[:25], []Gap{
+			{Index: 0, NewLine: 1, OldLine: 1, Count: 4},
+			{Index: 4, NewLine: 9, OldLine: 8, Count: 12},
+			{Index: 7, NewLine: 23, OldLine: 22, Count: 4},
+		}},
+		{"no base", nil, []Gap{
+			{Index: 0, NewLine: 1, OldLine: 1, Count: 4},
+			{Index: 4, NewLine: 9, OldLine: 8, Count: 12},
+			{Index: 7, NewLine: 23, OldLine: 22, Count: 9},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := info.Gaps(head, tt.base); !slices.Equal(got, tt.want) {
+				t.Errorf("Gaps() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpand(t *testing.T) {
+	info, head, base := gapTestDiff()
+	tests := []struct {
+		name  string
+		index int
+		down  bool
+		want  []string // inserted lines as "content new/old"
+		at    int      // display index of the first inserted line
+	}{
+		{"above the first hunk", 0, false, []string{"l1 1/1", "l2 2/2", "l3 3/3", "l4 4/4"}, 0},
+		{"below the first hunk", 0, true, []string{"l8 9/8", "l9 10/9", "l10 11/10", "l11 12/11", "l12 13/12"}, 4},
+		{"above the second hunk", 5, false, []string{"l15 16/15", "l16 17/16", "l17 18/17", "l18 19/18", "l19 20/19"}, 4},
+		{"below the last hunk", 6, true, []string{"l22 23/22", "l23 24/23", "l24 25/24", "l25 26/25", "l26 27/26"}, 7},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expanded := info.Expand(tt.index, tt.down, 5, head, base)
+			if expanded == nil {
+				t.Fatal("Expand() = nil")
+			}
+			var got []string
+			for _, dl := range expanded.Lines[tt.at : tt.at+len(tt.want)] {
+				if !dl.Outside || dl.Type != DiffContext {
+					t.Errorf("inserted line %+v should be an outside context line", dl)
+				}
+				got = append(got, fmt.Sprintf("%s %d/%d", dl.Content, dl.NewLine, dl.OldLine))
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("inserted %q, want %q", got, tt.want)
+			}
+			if len(expanded.Lines) != len(info.Lines)+len(tt.want) {
+				t.Errorf("got %d lines, want %d", len(expanded.Lines), len(info.Lines)+len(tt.want))
+			}
+		})
+	}
+}
+
+func TestExpandAll(t *testing.T) {
+	info, head, base := gapTestDiff()
+	for expanded := info.Expand(0, false, 5, head, base); expanded != nil; expanded = info.Expand(0, true, 5, head, base) {
+		info = expanded
+	}
+	if gaps := info.Gaps(head, base); gaps != nil {
+		t.Errorf("Gaps() = %+v after expanding everything, want none", gaps)
+	}
+	// Each head line once, plus the removed line
+	if len(info.Lines) != len(head)+1 {
+		t.Errorf("got %d lines, want %d", len(info.Lines), len(head)+1)
+	}
+	if info.Expand(0, false, 5, head, base) != nil {
+		t.Error("Expand() above the first line should find no gap")
+	}
+}
@@ -0,0 +1,267 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	gh "github.com/google/go-github/v84/github"
+)
+
+// PendingReview is the user's pending (draft) review on a pull request:
+// saved on GitHub, visible only to its author until submitted.
+type PendingReview struct {
+	ID       int64  // REST review ID, used to submit the review
+	NodeID   string // GraphQL node ID, used to add comments
+	Body     string
+	Comments []PendingComment // comments starting a new thread
+	Replies  []PendingComment // replies to existing threads
+}
+
+// PendingComment is a comment of a PendingReview.
+type PendingComment struct {
+	PRReviewComment
+	ID       int64  // REST comment ID, used to delete the comment
+	ThreadID string // thread the comment belongs to
+}
+
+const pendingReviewQuery = `query($owner: String!, $repo: String!, $number: Int!) {
+  repository(owner: $owner, name: $repo) {
+    pullRequest(number: $number) {
+      reviews(states: PENDING, first: 1) {
+        nodes { id databaseId body }
+      }
+    }
+  }
+}`
+
+// FindPendingReview returns the user's pending review on a pull request, or
+// nil if there is none. Its comments are taken from threads, as returned by
+// ListReviewThreads.
+func (c *Client) FindPendingReview(ctx context.Context, ref *PRRef, threads []ReviewThread) (*PendingReview, error) {
+	var data struct {
+		Repository struct {
+			PullRequest struct {
+				Reviews struct {
+					Nodes []struct {
+						ID         string
+						DatabaseID int64
+						Body       string
+					}
+				}
+			}
+		}
+	}
+	vars := map[string]any{"owner": ref.Owner, "repo": ref.Repo, "number": ref.Number}
+	if err := c.graphQL(ctx, pendingReviewQuery, vars, &data); err != nil {
+		return nil, fmt.Errorf("finding pending review: %w", err)
+	}
+	nodes := data.Repository.PullRequest.Reviews.Nodes
+	if len(nodes) == 0 {
+		return nil, nil
+	}
+
+	pending := &PendingReview{ID: nodes[0].DatabaseID, NodeID: nodes[0].ID, Body: nodes[0].Body}
+	for _, t := range threads {
+		for i, cm := range t.Comments {
+			if !cm.Pending {
+				continue
+			}
+			pc := PendingComment{
+				PRReviewComment: PRReviewComment{Path: t.Path, Body: cm.Body, Line: t.Line, StartLine: t.StartLine, Side: t.Side, File: t.IsFile},
+				ID:              cm.ID,
+				ThreadID:        t.ID,
+			}
+			if i == 0 {
+				pending.Comments = append(pending.Comments, pc)
+			} else {
+				pending.Replies = append(pending.Replies, pc)
+			}
+		}
+	}
+	return pending, nil
+}
+
+const addThreadMutation = `mutation($review: ID!, $path: String!, $body: String!, $line: Int, $side: DiffSide, $startLine: Int, $startSide: DiffSide, $subjectType: PullRequestReviewThreadSubjectType) {
+  addPullRequestReviewThread(input: {pullRequestReviewId: $review, path: $path, body: $body, line: $line, side: $side, startLine: $startLine, startSide: $startSide, subjectType: $subjectType}) {
+    thread { id }
+  }
+}`
+
+// SavePendingReview saves sub as a pending review without submitting it
+// (sub.Review.Event is ignored). If pending is nil a new pending review is
+// created; otherwise pending is updated to hold sub's comments, replies and
+// body, and its comments that are no longer wanted are deleted. File-level
+// comments the server does not accept are folded into the review body (see
+// FoldFileComments); returns how many were.
+func (c *Client) SavePendingReview(ctx context.Context, ref *PRRef, pending *PendingReview, sub *Submission) (int, error) {
+	_, _, folded, err := c.savePending(ctx, ref, pending, sub)
+	return folded, err
+}
+
+// DiscardPendingReview deletes pending together with its comments and replies.
+func (c *Client) DiscardPendingReview(ctx context.Context, ref *PRRef, pending *PendingReview) error {
+	if _, _, err := c.inner.PullRequests.DeletePendingReview(ctx, ref.Owner, ref.Repo, ref.Number, pending.ID); err != nil {
+		return fmt.Errorf("discarding pending review: %w", err)
+	}
+	return nil
+}
+
+// savePending implements SavePendingReview. Also returns the review's REST ID
+// and its body after folding.
+func (c *Client) savePending(ctx context.Context, ref *PRRef, pending *PendingReview, sub *Submission) (id int64, body string, folded int, err error) {
+	want := append(reviewComments(sub.Review), sub.FileComments...)
+	if pending == nil {
+		draft := *sub.Review
+		draft.Event = nil
+		created, _, cerr := c.inner.PullRequests.CreateReview(ctx, ref.Owner, ref.Repo, ref.Number, &draft)
+		if cerr != nil {
+			return 0, "", 0, fmt.Errorf("saving pending review: %w", cerr)
+		}
+		// The line comments were created with the review
+		pending = &PendingReview{ID: created.GetID(), NodeID: created.GetNodeID(), Body: sub.Review.GetBody()}
+		want = sub.FileComments
+		defer func() {
+			// Do not leave a half-saved review behind: GitHub allows only one pending review per user
+			if err != nil {
+				if _, _, derr := c.inner.PullRequests.DeletePendingReview(ctx, ref.Owner, ref.Repo, ref.Number, pending.ID); derr != nil {
+					err = errors.Join(err, fmt.Errorf("discarding pending review: %w", derr))
+				}
+			}
+		}()
+	}
+
+	unsupported, err := c.syncPendingComments(ctx, ref, pending, want)
+	if err != nil {
+		return 0, "", 0, err
+	}
+	if err := c.syncPendingReplies(ctx, ref, pending, sub.Replies); err != nil {
+		return 0, "", 0, err
+	}
+	body = FoldFileComments(sub.Review.GetBody(), unsupported)
+	if body != pending.Body {
+		if _, _, err := c.inner.PullRequests.UpdateReview(ctx, ref.Owner, ref.Repo, ref.Number, pending.ID, body); err != nil {
+			return 0, "", 0, fmt.Errorf("updating pending review: %w", err)
+		}
+	}
+	return pending.ID, body, len(unsupported), nil
+}
+
+// reviewComments returns the line comments of a review request.
+func reviewComments(review *gh.PullRequestReviewRequest) []PRReviewComment {
+	comments := make([]PRReviewComment, len(review.Comments))
+	for i, dc := range review.Comments {
+		comments[i] = PRReviewComment{
+			Path:      dc.GetPath(),
+			Body:      dc.GetBody(),
+			Line:      dc.GetLine(),
+			StartLine: dc.GetStartLine(),
+			Side:      dc.GetSide(),
+		}
+	}
+	return comments
+}
+
+// syncPendingComments adds the comments that pending lacks and deletes its
+// comments that are not in comments. Unchanged comments are kept as they are.
+// Once the server turns out not to support file-level comments, the remaining
+// ones are not added but returned, to be folded into the review body.
+func (c *Client) syncPendingComments(ctx context.Context, ref *PRRef, pending *PendingReview, comments []PRReviewComment) ([]PRReviewComment, error) {
+	kept := make([]bool, len(pending.Comments))
+	var unsupported []PRReviewComment
+	for _, want := range comments {
+		if i := unmatched(pending.Comments, kept, func(pc PendingComment) bool { return pc.PRReviewComment == want }); i >= 0 {
+			kept[i] = true
+			continue
+		}
+		if want.File && len(unsupported) > 0 {
+			unsupported = append(unsupported, want)
+			continue
+		}
+		vars := map[string]any{
+			"review": pending.NodeID,
+			"path":   want.Path,
+			"body":   want.Body,
+		}
+		if want.File {
+			vars["subjectType"] = "FILE"
+		} else {
+			vars["line"] = want.Line
+			vars["side"] = want.Side
+			if want.StartLine > 0 {
+				vars["startLine"] = want.StartLine
+				vars["startSide"] = want.Side
+			}
+		}
+		if err := c.graphQL(ctx, addThreadMutation, vars, &struct{}{}); err != nil {
+			if want.File && fileCommentsUnsupported(err) {
+				unsupported = append(unsupported, want)
+				continue
+			}
+			return nil, fmt.Errorf("adding comment to pending review: %w", err)
+		}
+	}
+	return unsupported, c.deleteUnkept(ctx, ref, pending.Comments, kept)
+}
+
+// fileCommentsUnsupported reports whether err is the schema error of a server
+// without file-level comments, which does not know the subjectType argument
+// of addPullRequestReviewThread or its PullRequestReviewThreadSubjectType type.
+func fileCommentsUnsupported(err error) bool {
+	var gqlErrs graphQLErrors
+	if !errors.As(err, &gqlErrs) {
+		return false
+	}
+	for _, e := range gqlErrs {
+		switch e.Extensions.Code {
+		case "argumentNotAccepted":
+			if e.Extensions.ArgumentName == "subjectType" {
+				return true
+			}
+		case "variableRequiresValidType":
+			if e.Extensions.TypeName == "PullRequestReviewThreadSubjectType" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// syncPendingReplies is syncPendingComments for replies to existing threads.
+func (c *Client) syncPendingReplies(ctx context.Context, ref *PRRef, pending *PendingReview, replies []ThreadReply) error {
+	kept := make([]bool, len(pending.Replies))
+	for _, r := range replies {
+		if i := unmatched(pending.Replies, kept, func(pc PendingComment) bool { return pc.ThreadID == r.ThreadID && pc.Body == r.Body }); i >= 0 {
+			kept[i] = true
+			continue
+		}
+		if err := c.addThreadReply(ctx, pending.NodeID, r); err != nil {
+			return err
+		}
+	}
+	return c.deleteUnkept(ctx, ref, pending.Replies, kept)
+}
+
+// unmatched returns the index of the first comment that is not kept yet and
+// satisfies match, or -1.
+func unmatched(comments []PendingComment, kept []bool, match func(PendingComment) bool) int {
+	for i, pc := range comments {
+		if !kept[i] && match(pc) {
+			return i
+		}
+	}
+	return -1
+}
+
+// deleteUnkept deletes the comments that are not kept.
+func (c *Client) deleteUnkept(ctx context.Context, ref *PRRef, comments []PendingComment, kept []bool) error {
+	for i, pc := range comments {
+		if kept[i] {
+			continue
+		}
+		if _, err := c.inner.PullRequests.DeleteComment(ctx, ref.Owner, ref.Repo, pc.ID); err != nil {
+			return fmt.Errorf("removing comment from pending review: %w", err)
+		}
+	}
+	return nil
+}
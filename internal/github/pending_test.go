@@ -0,0 +1,305 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	gh "github.com/google/go-github/v84/github"
+)
+
+func TestFindPendingReview(t *testing.T) {
+	tests := []struct {
+		name    string
+		reviews []any
+		want    *PendingReview
+	}{
+		{
+			name: "no pending review",
+		},
+		{
+			name:    "pending review with a new thread and a reply",
+			reviews: []any{map[string]any{"id": "PRR_7", "databaseId": 7, "body": "Draft"}},
+			want: &PendingReview{
+				ID: 7, NodeID: "PRR_7", Body: "Draft",
+				Comments: []PendingComment{{
+					PRReviewComment: PRReviewComment{Path: "a.md", Body: "New", Line: 9, StartLine: 8, Side: SideRight},
+					ID:              3,
+					ThreadID:        "T2",
+				}},
+				Replies: []PendingComment{{
+					PRReviewComment: PRReviewComment{Path: "a.md", Body: "Agreed", Line: 5, Side: SideLeft},
+					ID:              2,
+					ThreadID:        "T1",
+				}},
+			},
+		},
+	}
+
+	threads := []ReviewThread{
+		{ID: "T1", Path: "a.md", Line: 5, Side: SideLeft, Comments: []ThreadComment{
+			{ID: 1, Body: "Why?"},
+			{ID: 2, Body: "Agreed", Pending: true},
+		}},
+		{ID: "T2", Path: "a.md", Line: 9, StartLine: 8, Side: SideRight, Comments: []ThreadComment{
+			{ID: 3, Body: "New", Pending: true},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			mux.HandleFunc("POST /graphql", func(w http.ResponseWriter, _ *http.Request) {
+				writeJSON(t, w, map[string]any{"data": map[string]any{"repository": map[string]any{"pullRequest": map[string]any{
+					"reviews": map[string]any{"nodes": tt.reviews},
+				}}}})
+			})
+			srv := httptest.NewServer(mux)
+			t.Cleanup(srv.Close)
+
+			client := NewClientWithHTTP(srv.Client(), srv.URL+"/")
+			got, err := client.FindPendingReview(context.Background(), &PRRef{Owner: "owner", Repo: "repo", Number: 1}, threads)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.want == nil {
+				if got != nil {
+					t.Errorf("FindPendingReview() = %+v, want nil", got)
+				}
+				return
+			}
+			if got == nil || got.ID != tt.want.ID || got.NodeID != tt.want.NodeID || got.Body != tt.want.Body ||
+				!slices.Equal(got.Comments, tt.want.Comments) || !slices.Equal(got.Replies, tt.want.Replies) {
+				t.Errorf("FindPendingReview() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// pendingTestServer records the API calls made to update pending review 7.
+// File-level comments fail with the GraphQL error fileError unless it is nil,
+// e.g. the schema error of an older GitHub Enterprise Server without them.
+func pendingTestServer(t *testing.T, calls *[]string, fileError map[string]any) *httptest.Server {
+	t.Helper()
+	record := func(format string, args ...any) { *calls = append(*calls, fmt.Sprintf(format, args...)) }
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /graphql", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query     string
+			Variables map[string]any
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decoding request: %v", err)
+		}
+		if req.Variables["review"] != "PRR_7" {
+			t.Errorf("review = %v, want PRR_7", req.Variables["review"])
+		}
+		switch {
+		case strings.Contains(req.Query, "addPullRequestReviewThreadReply"):
+			record("reply %v: %v", req.Variables["thread"], req.Variables["body"])
+		case req.Variables["subjectType"] == "FILE":
+			record("add %v: %v", req.Variables["path"], req.Variables["body"])
+			if fileError != nil {
+				writeJSON(t, w, map[string]any{"errors": []any{fileError}})
+				return
+			}
+		default:
+			record("add %v:%v: %v", req.Variables["path"], req.Variables["line"], req.Variables["body"])
+		}
+		writeJSON(t, w, map[string]any{"data": map[string]any{}})
+	})
+	mux.HandleFunc("DELETE /repos/owner/repo/pulls/comments/{id}", func(w http.ResponseWriter, r *http.Request) {
+		record("delete %s", r.PathValue("id"))
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("PUT /repos/owner/repo/pulls/1/reviews/7", func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decoding request: %v", err)
+		}
+		record("body %v", req["body"])
+		writeJSON(t, w, map[string]any{"id": 7})
+	})
+	mux.HandleFunc("POST /repos/owner/repo/pulls/1/reviews/7/events", func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decoding request: %v", err)
+		}
+		record("submit %v", req["event"])
+		writeJSON(t, w, map[string]any{"id": 7})
+	})
+	mux.HandleFunc("POST /repos/owner/repo/pulls/1/reviews", func(w http.ResponseWriter, _ *http.Request) {
+		t.Error("a pending review is being resumed; no new review should be created")
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func testPendingReview() *PendingReview {
+	return &PendingReview{
+		ID: 7, NodeID: "PRR_7", Body: "Draft",
+		Comments: []PendingComment{
+			{ID: 1, PRReviewComment: PRReviewComment{Path: "a.md", Body: "keep", Line: 3, Side: SideRight}},
+			{ID: 2, PRReviewComment: PRReviewComment{Path: "a.md", Body: "old", Line: 4, Side: SideRight}},
+		},
+		Replies: []PendingComment{
+			{ID: 5, ThreadID: "T1", PRReviewComment: PRReviewComment{Body: "same"}},
+			{ID: 6, ThreadID: "T2", PRReviewComment: PRReviewComment{Body: "gone"}},
+		},
+	}
+}
+
+func TestSavePendingReviewUpdatesExisting(t *testing.T) {
+	var calls []string
+	srv := pendingTestServer(t, &calls, nil)
+	client := NewClientWithHTTP(srv.Client(), srv.URL+"/")
+	ref := &PRRef{Owner: "owner", Repo: "repo", Number: 1}
+
+	review := &gh.PullRequestReviewRequest{
+		Event: new("COMMENT"),
+		Body:  new("Final"),
+		Comments: []*gh.DraftReviewComment{
+			{Path: new("a.md"), Body: new("keep"), Line: new(3), Side: new(SideRight)},
+			{Path: new("a.md"), Body: new("new"), Line: new(6), Side: new(SideRight)},
+		},
+	}
+	replies := []ThreadReply{{ThreadID: "T1", Body: "same"}, {ThreadID: "T3", Body: "added"}}
+
+	folded, err := client.SavePendingReview(context.Background(), ref, testPendingReview(), &Submission{Review: review, Replies: replies})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if folded != 0 {
+		t.Errorf("folded = %d, want 0", folded)
+	}
+	want := []string{"add a.md:6: new", "delete 2", "reply T3: added", "delete 6", "body Final"}
+	if !slices.Equal(calls, want) {
+		t.Errorf("calls = %q, want %q", calls, want)
+	}
+}
+
+func TestSubmitReviewWithFinalizesPending(t *testing.T) {
+	var calls []string
+	srv := pendingTestServer(t, &calls, nil)
+	client := NewClientWithHTTP(srv.Client(), srv.URL+"/")
+	ref := &PRRef{Owner: "owner", Repo: "repo", Number: 1}
+
+	pending := testPendingReview()
+	review := &gh.PullRequestReviewRequest{
+		Event: new("APPROVE"),
+		Body:  new("Draft"),
+		Comments: []*gh.DraftReviewComment{
+			{Path: new("a.md"), Body: new("keep"), Line: new(3), Side: new(SideRight)},
+			{Path: new("a.md"), Body: new("old"), Line: new(4), Side: new(SideRight)},
+		},
+	}
+	replies := []ThreadReply{{ThreadID: "T1", Body: "same"}, {ThreadID: "T2", Body: "gone"}}
+
+	if _, err := client.SubmitReviewWith(context.Background(), ref, pending, &Submission{Review: review, Replies: replies}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"submit APPROVE"}; !slices.Equal(calls, want) {
+		t.Errorf("calls = %q, want %q", calls, want)
+	}
+}
+
+func TestSavePendingReviewFileComments(t *testing.T) {
+	tests := []struct {
+		name       string
+		fileError  map[string]any
+		wantErr    bool
+		wantFolded int
+		wantCalls  []string
+	}{
+		{
+			name:      "added as file-level comments",
+			wantCalls: []string{"add a.md: one", "add b.md: two", "delete 1", "delete 2", "delete 5", "delete 6"},
+		},
+		{
+			name: "folded into the body when the argument is unknown",
+			fileError: map[string]any{
+				"message": "InputObject 'AddPullRequestReviewThreadInput' doesn't accept argument 'subjectType'",
+				"path":    []any{"mutation", "addPullRequestReviewThread", "input", "subjectType"},
+				"extensions": map[string]any{
+					"code":         "argumentNotAccepted",
+					"name":         "AddPullRequestReviewThreadInput",
+					"typeName":     "InputObject",
+					"argumentName": "subjectType",
+				},
+			},
+			wantFolded: 2,
+			wantCalls:  []string{"add a.md: one", "delete 1", "delete 2", "delete 5", "delete 6", "body Draft\n\n### a.md\n\none\n\n### b.md\n\ntwo"},
+		},
+		{
+			name: "folded into the body when the type is unknown",
+			fileError: map[string]any{
+				"message": "PullRequestReviewThreadSubjectType isn't a defined input type (on $subjectType)",
+				"path":    []any{"mutation"},
+				"extensions": map[string]any{
+					"code":         "variableRequiresValidType",
+					"typeName":     "PullRequestReviewThreadSubjectType",
+					"variableName": "subjectType",
+				},
+			},
+			wantFolded: 2,
+			wantCalls:  []string{"add a.md: one", "delete 1", "delete 2", "delete 5", "delete 6", "body Draft\n\n### a.md\n\none\n\n### b.md\n\ntwo"},
+		},
+		{
+			name: "other errors are returned",
+			fileError: map[string]any{
+				"type":    "NOT_FOUND",
+				"path":    []any{"addPullRequestReviewThread"},
+				"message": "Could not resolve to a node with the global id of 'PRR_7'",
+			},
+			wantErr:   true,
+			wantCalls: []string{"add a.md: one"},
+		},
+		{
+			name: "errors that only mention subjectType are returned",
+			fileError: map[string]any{
+				"type":    "UNPROCESSABLE",
+				"path":    []any{"addPullRequestReviewThread"},
+				"message": "subjectType FILE is not allowed on a deleted file",
+			},
+			wantErr:   true,
+			wantCalls: []string{"add a.md: one"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls []string
+			srv := pendingTestServer(t, &calls, tt.fileError)
+			client := NewClientWithHTTP(srv.Client(), srv.URL+"/")
+			ref := &PRRef{Owner: "owner", Repo: "repo", Number: 1}
+
+			sub := &Submission{
+				Review: &gh.PullRequestReviewRequest{Body: new("Draft")},
+				FileComments: []PRReviewComment{
+					{Path: "a.md", Body: "one", File: true},
+					{Path: "b.md", Body: "two", File: true},
+				},
+			}
+			folded, err := client.SavePendingReview(context.Background(), ref, testPendingReview(), sub)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error")
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if folded != tt.wantFolded {
+				t.Errorf("folded = %d, want %d", folded, tt.wantFolded)
+			}
+			if !slices.Equal(calls, tt.wantCalls) {
+				t.Errorf("calls = %q, want %q", calls, tt.wantCalls)
+			}
+		})
+	}
+}
@@ -3,12 +3,17 @@ package github
 import (
 	"fmt"
 	"net/url"
+	"slices"
 	"strconv"
 	"strings"
 )
 
+// DefaultHost is the host of pull requests on github.com.
+const DefaultHost = "github.com"
+
 // PRRef identifies a GitHub pull request.
 type PRRef struct {
+	Host   string // "github.com" or a GitHub Enterprise Server host
 	Owner  string
 	Repo   string
 	Number int
@@ -16,13 +21,15 @@ type PRRef struct {
 
 // PRFile represents a Markdown file changed in a PR.
 type PRFile struct {
-	Path  string // file path in the repo
-	Patch string // unified diff patch
+	Path     string // file path in the repo
+	BasePath string // file path on the base side ("" = added in the PR)
+	Patch    string // unified diff patch
 }
 
 // ParsePRURL parses a GitHub PR URL into its components.
-// Accepts: https://github.com/{owner}/{repo}/pull/{number}
-func ParsePRURL(rawURL string) (*PRRef, error) {
+// Accepts: https://{host}/{owner}/{repo}/pull/{number}, where host is
+// github.com or one of the GitHub Enterprise Server hosts in hosts.
+func ParsePRURL(rawURL string, hosts ...string) (*PRRef, error) {
 	u, err := url.Parse(rawURL)
 	if err != nil {
 		return nil, fmt.Errorf("invalid PR URL: %w", err)
@@ -31,14 +38,18 @@ func ParsePRURL(rawURL string) (*PRRef, error) {
 	if u.Scheme != "https" {
 		return nil, fmt.Errorf("invalid PR URL: expected https scheme, got %q", u.Scheme)
 	}
-	if u.Host != "github.com" {
-		return nil, fmt.Errorf("invalid PR URL: expected github.com host, got %q", u.Host)
+	host := strings.ToLower(u.Host)
+	if !isGitHubHost(host, hosts) {
+		if len(hosts) == 0 {
+			return nil, fmt.Errorf("invalid PR URL: expected github.com host, got %q (add GitHub Enterprise Server hosts with --host)", u.Host)
+		}
+		return nil, fmt.Errorf("invalid PR URL: expected github.com or %s host, got %q", strings.Join(hosts, ", "), u.Host)
 	}
 
 	// Path: /{owner}/{repo}/pull/{number}
 	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
 	if len(parts) != 4 || parts[2] != "pull" {
-		return nil, fmt.Errorf("invalid PR URL: expected https://github.com/{owner}/{repo}/pull/{number}")
+		return nil, fmt.Errorf("invalid PR URL: expected https://%s/{owner}/{repo}/pull/{number}", host)
 	}
 
 	number, err := strconv.Atoi(parts[3])
@@ -47,8 +58,15 @@ func ParsePRURL(rawURL string) (*PRRef, error) {
 	}
 
 	return &PRRef{
+		Host:   host,
 		Owner:  parts[0],
 		Repo:   parts[1],
 		Number: number,
 	}, nil
 }
+
+// isGitHubHost reports whether host is github.com or one of the GitHub
+// Enterprise Server hosts in hosts.
+func isGitHubHost(host string, hosts []string) bool {
+	return host == DefaultHost || slices.ContainsFunc(hosts, func(h string) bool { return strings.EqualFold(h, host) })
+}
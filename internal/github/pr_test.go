@@ -8,23 +8,48 @@ func TestParsePRURL(t *testing.T) {
 	tests := []struct {
 		name    string
 		url     string
+		hosts   []string
 		want    *PRRef
 		wantErr bool
 	}{
 		{
 			name: "valid URL",
 			url:  "https://github.com/owner/repo/pull/123",
-			want: &PRRef{Owner: "owner", Repo: "repo", Number: 123},
+			want: &PRRef{Host: "github.com", Owner: "owner", Repo: "repo", Number: 123},
+		},
+		{
+			name:  "enterprise host",
+			url:   "https://github.example.com/owner/repo/pull/7",
+			hosts: []string{"github.example.com"},
+			want:  &PRRef{Host: "github.example.com", Owner: "owner", Repo: "repo", Number: 7},
+		},
+		{
+			name:  "enterprise host is case-insensitive",
+			url:   "https://GitHub.Example.com/owner/repo/pull/7",
+			hosts: []string{"github.example.com"},
+			want:  &PRRef{Host: "github.example.com", Owner: "owner", Repo: "repo", Number: 7},
+		},
+		{
+			name:  "github.com accepted alongside enterprise hosts",
+			url:   "https://github.com/owner/repo/pull/8",
+			hosts: []string{"github.example.com"},
+			want:  &PRRef{Host: "github.com", Owner: "owner", Repo: "repo", Number: 8},
+		},
+		{
+			name:    "unconfigured enterprise host",
+			url:     "https://github.other.com/owner/repo/pull/7",
+			hosts:   []string{"github.example.com"},
+			wantErr: true,
 		},
 		{
 			name: "valid URL with trailing slash",
 			url:  "https://github.com/owner/repo/pull/456/",
-			want: &PRRef{Owner: "owner", Repo: "repo", Number: 456},
+			want: &PRRef{Host: "github.com", Owner: "owner", Repo: "repo", Number: 456},
 		},
 		{
 			name: "valid URL with org containing hyphens",
 			url:  "https://github.com/my-org/my-repo/pull/1",
-			want: &PRRef{Owner: "my-org", Repo: "my-repo", Number: 1},
+			want: &PRRef{Host: "github.com", Owner: "my-org", Repo: "my-repo", Number: 1},
 		},
 		{
 			name:    "http scheme rejected",
@@ -70,7 +95,7 @@ func TestParsePRURL(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := ParsePRURL(tt.url)
+			got, err := ParsePRURL(tt.url, tt.hosts...)
 			if tt.wantErr {
 				if err == nil {
 					t.Errorf("ParsePRURL(%q) = %+v, want error", tt.url, got)
@@ -80,7 +105,7 @@ func TestParsePRURL(t *testing.T) {
 			if err != nil {
 				t.Fatalf("ParsePRURL(%q) error: %v", tt.url, err)
 			}
-			if got.Owner != tt.want.Owner || got.Repo != tt.want.Repo || got.Number != tt.want.Number {
+			if *got != *tt.want {
 				t.Errorf("ParsePRURL(%q) = %+v, want %+v", tt.url, got, tt.want)
 			}
 		})
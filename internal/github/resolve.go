@@ -0,0 +1,140 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	repoPRRe    = regexp.MustCompile(`^([\w.-]+)/([\w.-]+)#(\d+)$`)       // owner/repo#123
+	numberPRRe  = regexp.MustCompile(`^#?(\d+)$`)                         // #123 or 123
+	scpRemoteRe = regexp.MustCompile(`^(?:[\w.-]+@)?([\w.-]+):([^/].*)$`) // git@host:owner/repo.git
+)
+
+// LocalRepo is the GitHub repository checked out in the working directory.
+type LocalRepo struct {
+	Host   string // host of the origin remote
+	Owner  string
+	Repo   string
+	Branch string // current branch ("" when HEAD is detached)
+}
+
+// PRResolver resolves the pull request references accepted by commd pr:
+//
+//   - a PR URL (see ParsePRURL)
+//   - owner/repo#123
+//   - #123 or 123, in the repository of the origin remote
+//   - "", the open PR of the current branch
+type PRResolver struct {
+	Hosts []string // GitHub Enterprise Server hosts accepted besides github.com
+
+	// Local returns the repository in the working directory (default: LocalGitRepo).
+	Local func(hosts []string) (*LocalRepo, error)
+
+	// FindPR returns the number of the open PR in repo (Number is unset) whose
+	// head is branch. Required for an empty reference.
+	FindPR func(ctx context.Context, repo *PRRef, branch string) (int, error)
+}
+
+// Resolve resolves ref to a pull request.
+func (r *PRResolver) Resolve(ctx context.Context, ref string) (*PRRef, error) {
+	ref = strings.TrimSpace(ref)
+	switch {
+	case strings.Contains(ref, "://"):
+		return ParsePRURL(ref, r.Hosts...)
+
+	case repoPRRe.MatchString(ref):
+		m := repoPRRe.FindStringSubmatch(ref)
+		number, _ := strconv.Atoi(m[3])
+		// Use the host of the current repository, like gh does.
+		host := DefaultHost
+		if local, err := r.local(); err == nil {
+			host = local.Host
+		}
+		return &PRRef{Host: host, Owner: m[1], Repo: m[2], Number: number}, nil
+
+	case numberPRRe.MatchString(ref):
+		local, err := r.local()
+		if err != nil {
+			return nil, fmt.Errorf("resolving PR %s: %w", ref, err)
+		}
+		number, _ := strconv.Atoi(numberPRRe.FindStringSubmatch(ref)[1])
+		return &PRRef{Host: local.Host, Owner: local.Owner, Repo: local.Repo, Number: number}, nil
+
+	case ref == "":
+		local, err := r.local()
+		if err != nil {
+			return nil, fmt.Errorf("finding the PR of the current branch: %w", err)
+		}
+		if local.Branch == "" {
+			return nil, fmt.Errorf("finding the PR of the current branch: HEAD is detached")
+		}
+		if r.FindPR == nil {
+			return nil, fmt.Errorf("finding the PR of the current branch: no GitHub client")
+		}
+		pr := &PRRef{Host: local.Host, Owner: local.Owner, Repo: local.Repo}
+		number, err := r.FindPR(ctx, pr, local.Branch)
+		if err != nil {
+			return nil, err
+		}
+		pr.Number = number
+		return pr, nil
+	}
+	return nil, fmt.Errorf("invalid PR reference %q: expected a PR URL, owner/repo#123, #123 or 123", ref)
+}
+
+func (r *PRResolver) local() (*LocalRepo, error) {
+	if r.Local != nil {
+		return r.Local(r.Hosts)
+	}
+	return LocalGitRepo(r.Hosts)
+}
+
+// LocalGitRepo returns the repository of the origin remote of the git
+// repository in the working directory, and its current branch.
+func LocalGitRepo(hosts []string) (*LocalRepo, error) {
+	out, err := exec.Command("git", "remote", "get-url", "origin").Output()
+	if err != nil {
+		return nil, fmt.Errorf("reading the origin remote: %w", err)
+	}
+	repo, err := ParseRemoteURL(strings.TrimSpace(string(out)), hosts...)
+	if err != nil {
+		return nil, err
+	}
+	out, err = exec.Command("git", "branch", "--show-current").Output()
+	if err != nil {
+		return nil, fmt.Errorf("reading the current branch: %w", err)
+	}
+	repo.Branch = strings.TrimSpace(string(out))
+	return repo, nil
+}
+
+// ParseRemoteURL parses a git remote URL pointing at github.com or one of hosts.
+// Accepts https://host/owner/repo(.git), ssh://git@host/owner/repo.git and
+// git@host:owner/repo.git.
+func ParseRemoteURL(remote string, hosts ...string) (*LocalRepo, error) {
+	var host, path string
+	if m := scpRemoteRe.FindStringSubmatch(remote); m != nil && !strings.Contains(remote, "://") {
+		host, path = m[1], m[2]
+	} else {
+		u, err := url.Parse(remote)
+		if err != nil {
+			return nil, fmt.Errorf("invalid remote URL %q: %w", remote, err)
+		}
+		host, path = u.Hostname(), u.Path
+	}
+	host = strings.ToLower(host)
+	if !isGitHubHost(host, hosts) {
+		return nil, fmt.Errorf("remote %q is not on github.com or a configured GitHub Enterprise Server host", remote)
+	}
+	parts := strings.Split(strings.Trim(strings.TrimSuffix(path, ".git"), "/"), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("invalid remote URL %q: expected {owner}/{repo}", remote)
+	}
+	return &LocalRepo{Host: host, Owner: parts[0], Repo: parts[1]}, nil
+}
@@ -0,0 +1,132 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestPRResolverResolve(t *testing.T) {
+	origin := &LocalRepo{Host: "github.com", Owner: "me", Repo: "plans", Branch: "feature"}
+	tests := []struct {
+		name    string
+		ref     string
+		local   *LocalRepo // nil = not in a git repository
+		want    *PRRef
+		wantErr bool
+	}{
+		{
+			name: "URL",
+			ref:  "https://github.com/owner/repo/pull/123",
+			want: &PRRef{Host: "github.com", Owner: "owner", Repo: "repo", Number: 123},
+		},
+		{
+			name: "owner/repo#number",
+			ref:  "owner/repo#42",
+			want: &PRRef{Host: "github.com", Owner: "owner", Repo: "repo", Number: 42},
+		},
+		{
+			name:  "owner/repo#number uses the origin host",
+			ref:   "owner/repo#42",
+			local: &LocalRepo{Host: "github.example.com", Owner: "me", Repo: "plans"},
+			want:  &PRRef{Host: "github.example.com", Owner: "owner", Repo: "repo", Number: 42},
+		},
+		{
+			name:  "#number",
+			ref:   "#7",
+			local: origin,
+			want:  &PRRef{Host: "github.com", Owner: "me", Repo: "plans", Number: 7},
+		},
+		{
+			name:  "bare number",
+			ref:   "7",
+			local: origin,
+			want:  &PRRef{Host: "github.com", Owner: "me", Repo: "plans", Number: 7},
+		},
+		{
+			name:    "number outside a git repository",
+			ref:     "7",
+			wantErr: true,
+		},
+		{
+			name:  "current branch",
+			local: origin,
+			want:  &PRRef{Host: "github.com", Owner: "me", Repo: "plans", Number: 99},
+		},
+		{
+			name:    "detached HEAD",
+			local:   &LocalRepo{Host: "github.com", Owner: "me", Repo: "plans"},
+			wantErr: true,
+		},
+		{
+			name:    "not a reference",
+			ref:     "not-a-url",
+			local:   origin,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &PRResolver{
+				Hosts: []string{"github.example.com"},
+				Local: func([]string) (*LocalRepo, error) {
+					if tt.local == nil {
+						return nil, errors.New("not a git repository")
+					}
+					return tt.local, nil
+				},
+				FindPR: func(_ context.Context, repo *PRRef, branch string) (int, error) {
+					if repo.Owner != "me" || repo.Repo != "plans" || branch != "feature" {
+						t.Errorf("FindPR(%+v, %q)", repo, branch)
+					}
+					return 99, nil
+				},
+			}
+			got, err := r.Resolve(context.Background(), tt.ref)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Resolve(%q) = %+v, want error", tt.ref, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Resolve(%q) error: %v", tt.ref, err)
+			}
+			if *got != *tt.want {
+				t.Errorf("Resolve(%q) = %+v, want %+v", tt.ref, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRemoteURL(t *testing.T) {
+	tests := []struct {
+		remote  string
+		want    *LocalRepo
+		wantErr bool
+	}{
+		{remote: "https://github.com/owner/repo.git", want: &LocalRepo{Host: "github.com", Owner: "owner", Repo: "repo"}},
+		{remote: "https://github.com/owner/repo", want: &LocalRepo{Host: "github.com", Owner: "owner", Repo: "repo"}},
+		{remote: "git@github.com:owner/repo.git", want: &LocalRepo{Host: "github.com", Owner: "owner", Repo: "repo"}},
+		{remote: "ssh://git@github.example.com:2222/owner/repo.git", want: &LocalRepo{Host: "github.example.com", Owner: "owner", Repo: "repo"}},
+		{remote: "git@gitlab.com:owner/repo.git", wantErr: true},
+		{remote: "https://github.com/owner", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.remote, func(t *testing.T) {
+			got, err := ParseRemoteURL(tt.remote, "github.example.com")
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseRemoteURL(%q) = %+v, want error", tt.remote, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseRemoteURL(%q) error: %v", tt.remote, err)
+			}
+			if *got != *tt.want {
+				t.Errorf("ParseRemoteURL(%q) = %+v, want %+v", tt.remote, got, tt.want)
+			}
+		})
+	}
+}
@@ -3,6 +3,9 @@ package github
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"slices"
+	"strings"
 
 	gh "github.com/google/go-github/v84/github"
 	"github.com/koh-sh/commd/internal/markdown"
@@ -13,6 +16,11 @@ type FileReviewResult struct {
 	Path   string
 	Doc    *markdown.Document
 	Review *markdown.ReviewResult
+	Diff   *DiffInfo // the file's diff; nil = comment lines are not checked
+
+	// Changes to existing review threads on the file
+	Replies     []ThreadReply
+	Resolutions []ThreadResolution
 }
 
 // PRReviewComment represents a single inline comment on a PR.
@@ -22,17 +30,31 @@ type PRReviewComment struct {
 	Line      int
 	StartLine int    // 0 = single line
 	Side      string // "RIGHT" or "LEFT"
+	File      bool   // file-level comment (subject_type file): Line, StartLine and Side are unset
+}
+
+// LineRef returns the comment's lines for display: "L12", "L12-L14", or
+// "old L12" on the left side. Empty for file-level comments.
+func (c PRReviewComment) LineRef() string {
+	ref := markdown.FormatLineRef(c.StartLine, c.Line)
+	if c.StartLine == 0 {
+		ref = markdown.FormatLineRef(c.Line, 0)
+	}
+	if c.Side == SideLeft && ref != "" {
+		ref = "old " + ref
+	}
+	return ref
 }
 
 // MapComment converts a commd ReviewComment to a GitHub PR review comment.
-// Overview (file-level) comments are skipped — not supported by the GitHub API.
+// Overview comments become file-level comments.
 func MapComment(c markdown.ReviewComment, path string, doc *markdown.Document) *PRReviewComment {
+	body := formatCommentBody(c)
+
 	if c.SectionID == markdown.OverviewSectionID {
-		return nil
+		return &PRReviewComment{Path: path, Body: body, File: true}
 	}
 
-	body := formatCommentBody(c)
-
 	side := SideRight
 	if c.Side != "" {
 		side = c.Side
@@ -67,7 +89,59 @@ func MapComment(c markdown.ReviewComment, path string, doc *markdown.Document) *
 	}
 }
 
+// commentLabelRe matches the "**[label (decoration)]** " prefix written by formatCommentBody.
+var commentLabelRe = regexp.MustCompile(`(?s)^\*\*\[([^\]\s()]+)(?: \(([^)\s]+)\))?\]\*\* ?(.*)$`)
+
+// UnmapComment converts a PR review comment written by commd back into a
+// ReviewComment on doc; it is the inverse of MapComment. A file-level comment
+// becomes an overview comment and a single-line comment on a section heading
+// a section-level comment. Comments without a label get the default label.
+// Returns false for line comments that have lost their line (outdated).
+func UnmapComment(rc PRReviewComment, doc *markdown.Document) (markdown.ReviewComment, bool) {
+	if rc.Line == 0 && !rc.File {
+		return markdown.ReviewComment{}, false
+	}
+	c := markdown.ReviewComment{
+		Action: markdown.Labels.Default,
+		Body:   strings.TrimSpace(rc.Body),
+	}
+	if m := commentLabelRe.FindStringSubmatch(c.Body); m != nil &&
+		markdown.Labels.HasAction(markdown.ActionType(m[1])) && markdown.Labels.HasDecoration(markdown.Decoration(m[2])) {
+		c.Action = markdown.ActionType(m[1])
+		c.Decoration = markdown.Decoration(m[2])
+		c.Body = strings.TrimSpace(m[3])
+	}
+	if rc.File {
+		c.SectionID = markdown.OverviewSectionID
+		return c, true
+	}
+	if rc.Side == SideLeft {
+		c.Side = SideLeft
+	}
+
+	sectionID := doc.SectionIDAtLine(rc.Line)
+	if s := doc.FindSection(sectionID); s != nil && rc.StartLine == 0 && s.StartLine == rc.Line {
+		c.SectionID = sectionID
+		return c, true
+	}
+
+	c.StartLine = rc.Line
+	if rc.StartLine > 0 && rc.StartLine != rc.Line {
+		c.StartLine, c.EndLine = rc.StartLine, rc.Line
+	}
+	c.SectionID = doc.SectionIDAtLine(c.StartLine)
+	if c.Side != SideLeft {
+		if text, lines, ok := markdown.SplitSuggestion(c.Body); ok {
+			c.Body = text
+			c.Suggestion = &markdown.Suggestion{Replacement: lines}
+		}
+	}
+	return c, true
+}
+
 // BuildPRReview builds a GitHub PR review request from file review results.
+// File-level comments are not part of it (see FileComments): the REST API
+// only takes line comments when creating a review.
 func BuildPRReview(results []FileReviewResult, event, body string) *gh.PullRequestReviewRequest {
 	var comments []*gh.DraftReviewComment
 
@@ -77,7 +151,7 @@ func BuildPRReview(results []FileReviewResult, event, body string) *gh.PullReque
 		}
 		for _, c := range fr.Review.Comments {
 			mapped := MapComment(c, fr.Path, fr.Doc)
-			if mapped == nil {
+			if mapped == nil || mapped.File {
 				continue
 			}
 			dc := &gh.DraftReviewComment{
@@ -104,6 +178,129 @@ func BuildPRReview(results []FileReviewResult, event, body string) *gh.PullReque
 	return review
 }
 
+// MisplacedComment is a comment GitHub would refuse because its lines are
+// not in the file's diff; one misplaced comment fails the whole review.
+type MisplacedComment struct {
+	Path    string
+	Index   int             // in the file's Review.Comments
+	Comment PRReviewComment // as mapped by MapComment
+	Nearest int             // nearest commentable line in the same section (0 = none)
+}
+
+// FindMisplacedComments returns the line and section comments of results
+// whose lines are not all in the diff of their file. Files without a Diff are
+// not checked. Left-side comments and suggestions get no Nearest line: a
+// suggestion would replace the wrong lines.
+func FindMisplacedComments(results []FileReviewResult) []MisplacedComment {
+	var misplaced []MisplacedComment
+	for _, fr := range results {
+		if fr.Review == nil || fr.Diff == nil {
+			continue
+		}
+		for i, c := range fr.Review.Comments {
+			mapped := MapComment(c, fr.Path, fr.Doc)
+			if mapped == nil || mapped.File || inDiff(fr.Diff, mapped) {
+				continue
+			}
+			m := MisplacedComment{Path: fr.Path, Index: i, Comment: *mapped}
+			if mapped.Side != SideLeft && c.Suggestion == nil {
+				sectionID := fr.Doc.SectionIDAtLine(mapped.Line)
+				m.Nearest = fr.Diff.NearestCommentable(mapped.Line, func(line int) bool {
+					return fr.Doc.SectionIDAtLine(line) == sectionID
+				})
+			}
+			misplaced = append(misplaced, m)
+		}
+	}
+	return misplaced
+}
+
+// inDiff reports whether every line of the comment is commentable.
+func inDiff(diff *DiffInfo, c *PRReviewComment) bool {
+	start := c.StartLine
+	if start == 0 {
+		start = c.Line
+	}
+	for line := start; line <= c.Line; line++ {
+		if !diff.Commentable(line, c.Side) {
+			return false
+		}
+	}
+	return true
+}
+
+// PlaceComments fixes the misplaced comments of results. With toNearest,
+// comments that have a Nearest line become single-line comments on it.
+// The others are removed from results and returned as file-level comments
+// prefixed with their original lines, for the review body (see
+// FoldFileComments).
+func PlaceComments(results []FileReviewResult, misplaced []MisplacedComment, toNearest bool) []PRReviewComment {
+	var moved []PRReviewComment
+	removed := make(map[string][]int)
+	for _, m := range misplaced {
+		if toNearest && m.Nearest > 0 {
+			for _, fr := range results {
+				if fr.Path == m.Path {
+					c := &fr.Review.Comments[m.Index]
+					c.StartLine, c.EndLine = m.Nearest, 0
+				}
+			}
+			continue
+		}
+		moved = append(moved, PRReviewComment{Path: m.Path, Body: m.Comment.LineRef() + ": " + m.Comment.Body, File: true})
+		removed[m.Path] = append(removed[m.Path], m.Index)
+	}
+	for _, fr := range results {
+		indices := removed[fr.Path]
+		if len(indices) == 0 {
+			continue
+		}
+		var kept []markdown.ReviewComment
+		for i, c := range fr.Review.Comments {
+			if !slices.Contains(indices, i) {
+				kept = append(kept, c)
+			}
+		}
+		fr.Review.Comments = kept
+	}
+	return moved
+}
+
+// FileComments returns the file-level comments of the results, in order.
+func FileComments(results []FileReviewResult) []PRReviewComment {
+	var comments []PRReviewComment
+	for _, fr := range results {
+		if fr.Review == nil {
+			continue
+		}
+		for _, c := range fr.Review.Comments {
+			if mapped := MapComment(c, fr.Path, fr.Doc); mapped != nil && mapped.File {
+				comments = append(comments, *mapped)
+			}
+		}
+	}
+	return comments
+}
+
+// FoldFileComments appends file-level comments to a review body, grouped
+// under a heading per file, for servers that do not accept them as comments.
+func FoldFileComments(body string, comments []PRReviewComment) string {
+	var sb strings.Builder
+	sb.WriteString(body)
+	path := ""
+	for i, c := range comments {
+		if i == 0 || c.Path != path {
+			path = c.Path
+			if sb.Len() > 0 {
+				sb.WriteString("\n\n")
+			}
+			sb.WriteString("### " + path)
+		}
+		sb.WriteString("\n\n" + c.Body)
+	}
+	return sb.String()
+}
+
 // SubmitReview posts a PR review with inline comments.
 func (c *Client) SubmitReview(ctx context.Context, ref *PRRef, review *gh.PullRequestReviewRequest) error {
 	_, _, err := c.inner.PullRequests.CreateReview(ctx, ref.Owner, ref.Repo, ref.Number, review)
@@ -113,7 +310,43 @@ func (c *Client) SubmitReview(ctx context.Context, ref *PRRef, review *gh.PullRe
 	return nil
 }
 
+// Submission is a PR review with what a REST review request cannot hold.
+type Submission struct {
+	Review       *gh.PullRequestReviewRequest // line comments, body and event (see BuildPRReview)
+	FileComments []PRReviewComment            // file-level comments (see FileComments)
+	Replies      []ThreadReply                // replies to existing review threads
+}
+
+// SubmitReviewWith posts sub as a PR review. Reviews with file-level comments
+// or replies are saved as pending first (see SavePendingReview) and then
+// submitted; if pending is set, that pending review is updated and submitted
+// instead of creating a new one. Returns how many file-level comments were
+// folded into the review body because the server refused them.
+func (c *Client) SubmitReviewWith(ctx context.Context, ref *PRRef, pending *PendingReview, sub *Submission) (int, error) {
+	if pending == nil && len(sub.FileComments) == 0 && len(sub.Replies) == 0 {
+		return 0, c.SubmitReview(ctx, ref, sub.Review)
+	}
+
+	id, body, folded, err := c.savePending(ctx, ref, pending, sub)
+	if err != nil {
+		return 0, err
+	}
+	submit := &gh.PullRequestReviewRequest{Event: sub.Review.Event}
+	if body != "" {
+		submit.Body = new(body)
+	}
+	if _, _, err := c.inner.PullRequests.SubmitReview(ctx, ref.Owner, ref.Repo, ref.Number, id, submit); err != nil {
+		return 0, fmt.Errorf("submitting PR review: %w", err)
+	}
+	return folded, nil
+}
+
 // formatCommentBody formats a ReviewComment body for GitHub display.
+// A suggestion is appended as a ```suggestion block, which GitHub offers to commit.
 func formatCommentBody(c markdown.ReviewComment) string {
-	return fmt.Sprintf("**[%s]** %s", c.FormatLabel(), c.Body)
+	body := strings.TrimSpace(fmt.Sprintf("**[%s]** %s", c.FormatLabel(), c.Body))
+	if c.Suggestion != nil {
+		body += "\n\n" + markdown.SuggestionBlock(c.Suggestion.Replacement)
+	}
+	return body
 }
@@ -2,8 +2,12 @@ package github
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"slices"
+	"strings"
 	"testing"
 
 	gh "github.com/google/go-github/v84/github"
@@ -59,6 +63,24 @@ func TestMapComment(t *testing.T) {
 				Side:      SideRight,
 			},
 		},
+		{
+			name: "suggestion becomes a suggestion block",
+			comment: markdown.ReviewComment{
+				SectionID:  "S1",
+				Action:     markdown.ActionSuggestion,
+				StartLine:  5,
+				EndLine:    6,
+				Suggestion: &markdown.Suggestion{Original: []string{"a", "b"}, Replacement: []string{"a", "c"}},
+			},
+			path: "README.md",
+			want: &PRReviewComment{
+				Path:      "README.md",
+				Body:      "**[suggestion]**\n\n```suggestion\na\nc\n```",
+				Line:      6,
+				StartLine: 5,
+				Side:      SideRight,
+			},
+		},
 		{
 			name: "custom side LEFT is preserved",
 			comment: markdown.ReviewComment{
@@ -92,14 +114,18 @@ func TestMapComment(t *testing.T) {
 			},
 		},
 		{
-			name: "overview comment returns nil",
+			name: "overview comment becomes file-level",
 			comment: markdown.ReviewComment{
 				SectionID: markdown.OverviewSectionID,
 				Action:    markdown.ActionNote,
 				Body:      "General note",
 			},
 			path: "README.md",
-			want: nil,
+			want: &PRReviewComment{
+				Path: "README.md",
+				Body: "**[note]** General note",
+				File: true,
+			},
 		},
 		{
 			name: "unknown section returns nil",
@@ -140,6 +166,9 @@ func TestMapComment(t *testing.T) {
 			if got.Side != tt.want.Side {
 				t.Errorf("Side = %q, want %q", got.Side, tt.want.Side)
 			}
+			if got.File != tt.want.File {
+				t.Errorf("File = %v, want %v", got.File, tt.want.File)
+			}
 		})
 	}
 }
@@ -175,7 +204,7 @@ func TestBuildPRReview(t *testing.T) {
 			wantComments: 2,
 		},
 		{
-			name: "overview comments are skipped",
+			name: "file-level comments are left out",
 			results: []FileReviewResult{{
 				Path: "README.md",
 				Doc:  doc,
@@ -253,6 +282,65 @@ func TestBuildPRReview(t *testing.T) {
 	}
 }
 
+func TestFileComments(t *testing.T) {
+	doc := &markdown.Document{Sections: []*markdown.Section{{ID: "S1", Title: "Intro", StartLine: 1, EndLine: 5}}}
+	results := []FileReviewResult{
+		{Path: "a.md", Doc: doc, Review: &markdown.ReviewResult{Comments: []markdown.ReviewComment{
+			{SectionID: "S1", Action: markdown.ActionNote, Body: "Line", StartLine: 2},
+			{SectionID: markdown.OverviewSectionID, Action: markdown.ActionNote, Body: "One"},
+		}}},
+		{Path: "b.md", Doc: doc},
+		{Path: "c.md", Doc: doc, Review: &markdown.ReviewResult{Comments: []markdown.ReviewComment{
+			{SectionID: markdown.OverviewSectionID, Action: markdown.ActionIssue, Body: "Two"},
+		}}},
+	}
+	want := []PRReviewComment{
+		{Path: "a.md", Body: "**[note]** One", File: true},
+		{Path: "c.md", Body: "**[issue]** Two", File: true},
+	}
+	if got := FileComments(results); !slices.Equal(got, want) {
+		t.Errorf("FileComments() = %+v, want %+v", got, want)
+	}
+}
+
+func TestFoldFileComments(t *testing.T) {
+	comments := []PRReviewComment{
+		{Path: "a.md", Body: "One", File: true},
+		{Path: "a.md", Body: "Two", File: true},
+		{Path: "b.md", Body: "Three", File: true},
+	}
+	tests := []struct {
+		name     string
+		body     string
+		comments []PRReviewComment
+		want     string
+	}{
+		{
+			name: "no comments",
+			body: "LGTM",
+			want: "LGTM",
+		},
+		{
+			name:     "grouped per file after the body",
+			body:     "LGTM",
+			comments: comments,
+			want:     "LGTM\n\n### a.md\n\nOne\n\nTwo\n\n### b.md\n\nThree",
+		},
+		{
+			name:     "empty body",
+			comments: comments[2:],
+			want:     "### b.md\n\nThree",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FoldFileComments(tt.body, tt.comments); got != tt.want {
+				t.Errorf("FoldFileComments() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestSubmitReview(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -304,3 +392,263 @@ func TestSubmitReview(t *testing.T) {
 		})
 	}
 }
+
+func TestSubmitReviewWith(t *testing.T) {
+	tests := []struct {
+		name       string
+		replyError bool
+		wantCalls  []string
+		wantErr    bool
+	}{
+		{
+			name:      "pending review, replies, then submit",
+			wantCalls: []string{"create", "reply T1", "reply T2", "submit COMMENT"},
+		},
+		{
+			name:       "failed reply discards the pending review",
+			replyError: true,
+			wantCalls:  []string{"create", "reply T1", "delete"},
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls []string
+			mux := http.NewServeMux()
+			mux.HandleFunc("POST /repos/owner/repo/pulls/1/reviews", func(w http.ResponseWriter, r *http.Request) {
+				var req map[string]any
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+					t.Fatalf("decoding review: %v", err)
+				}
+				if _, ok := req["event"]; ok {
+					t.Errorf("pending review has event %v", req["event"])
+				}
+				calls = append(calls, "create")
+				writeJSON(t, w, map[string]any{"id": 7, "node_id": "PRR_7"})
+			})
+			mux.HandleFunc("POST /graphql", func(w http.ResponseWriter, r *http.Request) {
+				var req struct{ Variables map[string]any }
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+					t.Fatalf("decoding request: %v", err)
+				}
+				if req.Variables["review"] != "PRR_7" {
+					t.Errorf("reply review = %v, want PRR_7", req.Variables["review"])
+				}
+				calls = append(calls, fmt.Sprintf("reply %v", req.Variables["thread"]))
+				if tt.replyError {
+					writeJSON(t, w, map[string]any{"errors": []any{map[string]any{"message": "thread not found"}}})
+					return
+				}
+				writeJSON(t, w, map[string]any{"data": map[string]any{}})
+			})
+			mux.HandleFunc("POST /repos/owner/repo/pulls/1/reviews/7/events", func(w http.ResponseWriter, r *http.Request) {
+				var req map[string]any
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+					t.Fatalf("decoding submit: %v", err)
+				}
+				calls = append(calls, fmt.Sprintf("submit %v", req["event"]))
+				writeJSON(t, w, map[string]any{"id": 7})
+			})
+			mux.HandleFunc("DELETE /repos/owner/repo/pulls/1/reviews/7", func(w http.ResponseWriter, _ *http.Request) {
+				calls = append(calls, "delete")
+				writeJSON(t, w, map[string]any{"id": 7})
+			})
+			srv := httptest.NewServer(mux)
+			t.Cleanup(srv.Close)
+
+			client := NewClientWithHTTP(srv.Client(), srv.URL+"/")
+			ref := &PRRef{Owner: "owner", Repo: "repo", Number: 1}
+			review := &gh.PullRequestReviewRequest{Event: new("COMMENT")}
+			replies := []ThreadReply{{ThreadID: "T1", Body: "Done"}, {ThreadID: "T2", Body: "Fixed"}}
+
+			_, err := client.SubmitReviewWith(context.Background(), ref, nil, &Submission{Review: review, Replies: replies})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !slices.Equal(calls, tt.wantCalls) {
+				t.Errorf("calls = %v, want %v", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestResolveThread(t *testing.T) {
+	tests := []struct {
+		resolve  bool
+		mutation string
+	}{
+		{true, "resolveReviewThread"},
+		{false, "unresolveReviewThread"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mutation, func(t *testing.T) {
+			mux := http.NewServeMux()
+			mux.HandleFunc("POST /graphql", func(w http.ResponseWriter, r *http.Request) {
+				var req struct {
+					Query     string
+					Variables map[string]any
+				}
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+					t.Fatalf("decoding request: %v", err)
+				}
+				if !strings.Contains(req.Query, " "+tt.mutation+"(") || req.Variables["thread"] != "T1" {
+					t.Errorf("query = %q, variables = %v", req.Query, req.Variables)
+				}
+				writeJSON(t, w, map[string]any{"data": map[string]any{}})
+			})
+			srv := httptest.NewServer(mux)
+			t.Cleanup(srv.Close)
+
+			client := NewClientWithHTTP(srv.Client(), srv.URL+"/")
+			if err := client.ResolveThread(context.Background(), ThreadResolution{ThreadID: "T1", Resolve: tt.resolve}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestUnmapComment(t *testing.T) {
+	doc := &markdown.Document{
+		Sections: []*markdown.Section{
+			{ID: "S1", Title: "Intro", StartLine: 3, EndLine: 9},
+			{ID: "S2", Title: "Usage", StartLine: 10, EndLine: 20},
+		},
+	}
+
+	// Comments written by commd read back as they were
+	roundTrip := []markdown.ReviewComment{
+		{SectionID: "S1", Action: markdown.ActionIssue, Decoration: markdown.DecorationBlocking, Body: "Section"},
+		{SectionID: "S1", Action: markdown.ActionQuestion, Body: "Line", StartLine: 5},
+		{SectionID: "S2", Action: markdown.ActionNitpick, Body: "Range", StartLine: 11, EndLine: 13},
+		{SectionID: "S1", Action: markdown.ActionNote, Body: "Removed", StartLine: 6, Side: SideLeft},
+		{SectionID: markdown.OverviewSectionID, Action: markdown.ActionPraise, Body: "Whole file"},
+		{SectionID: "S2", Action: markdown.ActionSuggestion, Body: "Shorter", StartLine: 12,
+			Suggestion: &markdown.Suggestion{Replacement: []string{"new line"}}},
+	}
+	for _, want := range roundTrip {
+		t.Run(want.Body, func(t *testing.T) {
+			mapped := MapComment(want, "a.md", doc)
+			got, ok := UnmapComment(*mapped, doc)
+			if !ok {
+				t.Fatal("UnmapComment() = false")
+			}
+			if got.SectionID != want.SectionID || got.Action != want.Action || got.Decoration != want.Decoration ||
+				got.Body != want.Body || got.StartLine != want.StartLine || got.EndLine != want.EndLine || got.Side != want.Side {
+				t.Errorf("UnmapComment() = %+v, want %+v", got, want)
+			}
+			if (got.Suggestion == nil) != (want.Suggestion == nil) ||
+				got.Suggestion != nil && !slices.Equal(got.Suggestion.Replacement, want.Suggestion.Replacement) {
+				t.Errorf("Suggestion = %+v, want %+v", got.Suggestion, want.Suggestion)
+			}
+		})
+	}
+
+	t.Run("unlabeled comment gets the default label", func(t *testing.T) {
+		got, ok := UnmapComment(PRReviewComment{Path: "a.md", Body: "Written on GitHub", Line: 15, Side: SideRight}, doc)
+		if !ok || got.Action != markdown.Labels.Default || got.Body != "Written on GitHub" || got.SectionID != "S2" {
+			t.Errorf("UnmapComment() = %+v, %v", got, ok)
+		}
+	})
+
+	t.Run("comment without a line", func(t *testing.T) {
+		if _, ok := UnmapComment(PRReviewComment{Path: "a.md", Body: "outdated"}, doc); ok {
+			t.Error("UnmapComment() = true, want false")
+		}
+	})
+}
+
+func TestPRReviewCommentLineRef(t *testing.T) {
+	tests := []struct {
+		c    PRReviewComment
+		want string
+	}{
+		{PRReviewComment{Line: 12, Side: SideRight}, "L12"},
+		{PRReviewComment{Line: 14, StartLine: 12, Side: SideRight}, "L12-L14"},
+		{PRReviewComment{Line: 6, Side: SideLeft}, "old L6"},
+		{PRReviewComment{File: true}, ""},
+	}
+	for _, tt := range tests {
+		if got := tt.c.LineRef(); got != tt.want {
+			t.Errorf("LineRef(%+v) = %q, want %q", tt.c, got, tt.want)
+		}
+	}
+}
+
+func misplacedTestResults() []FileReviewResult {
+	doc := &markdown.Document{Sections: []*markdown.Section{
+		{ID: "S1", Title: "Intro", StartLine: 1, EndLine: 9},
+		{ID: "S2", Title: "Usage", StartLine: 10, EndLine: 20},
+	}}
+	return []FileReviewResult{{
+		Path: "a.md",
+		Doc:  doc,
+		// new lines 4-6 (in S1) and 12-13 (in S2)
+		Diff: ParsePatch("@@ -4,3 +4,3 @@\n a\n-b\n+B\n c\n@@ -12,2 +12,2 @@\n d\n e"),
+		Review: &markdown.ReviewResult{Comments: []markdown.ReviewComment{
+			{SectionID: "S1", Action: markdown.ActionNote, Body: "in diff", StartLine: 5},
+			{SectionID: "S1", Action: markdown.ActionIssue, Body: "section"},
+			{SectionID: "S2", Action: markdown.ActionNote, Body: "range", StartLine: 13, EndLine: 15},
+			{SectionID: "S2", Action: markdown.ActionSuggestion, Body: "suggest", StartLine: 18,
+				Suggestion: &markdown.Suggestion{Replacement: []string{"x"}}},
+			{SectionID: markdown.OverviewSectionID, Action: markdown.ActionNote, Body: "file"},
+		}},
+	}, {
+		Path: "b.md",
+		Doc:  doc,
+		Review: &markdown.ReviewResult{Comments: []markdown.ReviewComment{
+			{SectionID: "S1", Action: markdown.ActionNote, Body: "unchecked", StartLine: 2},
+		}},
+	}}
+}
+
+func TestFindMisplacedComments(t *testing.T) {
+	got := FindMisplacedComments(misplacedTestResults())
+	want := []struct {
+		index   int
+		ref     string
+		nearest int
+	}{
+		{1, "L1", 4},
+		{2, "L13-L15", 13},
+		{3, "L18", 0},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("FindMisplacedComments() = %+v, want %d comments", got, len(want))
+	}
+	for i, w := range want {
+		if got[i].Path != "a.md" || got[i].Index != w.index || got[i].Comment.LineRef() != w.ref || got[i].Nearest != w.nearest {
+			t.Errorf("misplaced[%d] = %+v, want index %d %s nearest %d", i, got[i], w.index, w.ref, w.nearest)
+		}
+	}
+}
+
+func TestPlaceComments(t *testing.T) {
+	t.Run("to nearest", func(t *testing.T) {
+		results := misplacedTestResults()
+		moved := PlaceComments(results, FindMisplacedComments(results), true)
+		want := []PRReviewComment{{Path: "a.md", Body: "L18: **[suggestion]** suggest\n\n```suggestion\nx\n```", File: true}}
+		if !slices.Equal(moved, want) {
+			t.Errorf("moved = %+v, want %+v", moved, want)
+		}
+		comments := results[0].Review.Comments
+		if len(comments) != 4 || comments[1].StartLine != 4 || comments[2].StartLine != 13 || comments[2].EndLine != 0 {
+			t.Errorf("comments = %+v", comments)
+		}
+		if m := FindMisplacedComments(results); len(m) != 0 {
+			t.Errorf("still misplaced: %+v", m)
+		}
+	})
+
+	t.Run("to body", func(t *testing.T) {
+		results := misplacedTestResults()
+		moved := PlaceComments(results, FindMisplacedComments(results), false)
+		if len(moved) != 3 || moved[0].Body != "L1: **[issue]** section" || moved[1].Body != "L13-L15: **[note]** range" {
+			t.Errorf("moved = %+v", moved)
+		}
+		comments := results[0].Review.Comments
+		if len(comments) != 2 || comments[0].Body != "in diff" || comments[1].Body != "file" {
+			t.Errorf("comments = %+v", comments)
+		}
+	})
+}
@@ -0,0 +1,220 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ReviewThread is an existing review thread on a pull request.
+type ReviewThread struct {
+	ID         string // GraphQL node ID, used to resolve the thread
+	Path       string
+	Line       int    // line in the current diff (0 = outdated or file-level)
+	StartLine  int    // first line of a multi-line thread (0 = single line)
+	Side       string // SideRight or SideLeft
+	IsResolved bool
+	IsOutdated bool
+	IsFile     bool // file-level thread, with no line
+	Comments   []ThreadComment
+}
+
+// ThreadComment is one comment in a ReviewThread.
+type ThreadComment struct {
+	ID        int64 // REST comment ID, used to reply to the thread
+	Author    string
+	Body      string
+	CreatedAt time.Time
+	Pending   bool // part of the user's pending review (see FindPendingReview)
+}
+
+// ThreadReply is a reply to an existing review thread, posted with a review.
+type ThreadReply struct {
+	ThreadID string // ReviewThread.ID
+	Body     string
+}
+
+// ThreadResolution resolves or unresolves an existing review thread.
+type ThreadResolution struct {
+	ThreadID string // ReviewThread.ID
+	Resolve  bool   // true = resolve, false = unresolve
+}
+
+const reviewThreadsQuery = `query($owner: String!, $repo: String!, $number: Int!, $after: String) {
+  repository(owner: $owner, name: $repo) {
+    pullRequest(number: $number) {
+      reviewThreads(first: 100, after: $after) {
+        pageInfo { hasNextPage endCursor }
+        nodes {
+          id isResolved isOutdated path line startLine diffSide subjectType
+          comments(first: 100) {
+            nodes { databaseId author { login } body createdAt state }
+          }
+        }
+      }
+    }
+  }
+}`
+
+// ListReviewThreads returns the review threads of a pull request, for all files.
+// Comments of the user's pending review are included and marked Pending.
+func (c *Client) ListReviewThreads(ctx context.Context, ref *PRRef) ([]ReviewThread, error) {
+	var threads []ReviewThread
+	vars := map[string]any{"owner": ref.Owner, "repo": ref.Repo, "number": ref.Number}
+	for {
+		var data struct {
+			Repository struct {
+				PullRequest struct {
+					ReviewThreads struct {
+						PageInfo struct {
+							HasNextPage bool
+							EndCursor   string
+						}
+						Nodes []struct {
+							ID          string
+							IsResolved  bool
+							IsOutdated  bool
+							Path        string
+							Line        int
+							StartLine   int
+							DiffSide    string
+							SubjectType string
+							Comments    struct {
+								Nodes []struct {
+									DatabaseID int64
+									Author     struct{ Login string }
+									Body       string
+									CreatedAt  time.Time
+									State      string
+								}
+							}
+						}
+					}
+				}
+			}
+		}
+		if err := c.graphQL(ctx, reviewThreadsQuery, vars, &data); err != nil {
+			return nil, fmt.Errorf("listing review threads: %w", err)
+		}
+		page := data.Repository.PullRequest.ReviewThreads
+		for _, n := range page.Nodes {
+			t := ReviewThread{
+				ID:         n.ID,
+				Path:       n.Path,
+				Line:       n.Line,
+				StartLine:  n.StartLine,
+				Side:       n.DiffSide,
+				IsResolved: n.IsResolved,
+				IsOutdated: n.IsOutdated,
+				IsFile:     n.SubjectType == "FILE",
+			}
+			for _, cm := range n.Comments.Nodes {
+				t.Comments = append(t.Comments, ThreadComment{
+					ID:        cm.DatabaseID,
+					Author:    cm.Author.Login,
+					Body:      cm.Body,
+					CreatedAt: cm.CreatedAt,
+					Pending:   cm.State == "PENDING",
+				})
+			}
+			threads = append(threads, t)
+		}
+		if !page.PageInfo.HasNextPage {
+			return threads, nil
+		}
+		vars["after"] = page.PageInfo.EndCursor
+	}
+}
+
+const addThreadReplyMutation = `mutation($review: ID!, $thread: ID!, $body: String!) {
+  addPullRequestReviewThreadReply(input: {pullRequestReviewId: $review, pullRequestReviewThreadId: $thread, body: $body}) {
+    comment { id }
+  }
+}`
+
+// addThreadReply adds a reply to a thread as part of the pending review with
+// node ID reviewID.
+func (c *Client) addThreadReply(ctx context.Context, reviewID string, reply ThreadReply) error {
+	vars := map[string]any{"review": reviewID, "thread": reply.ThreadID, "body": reply.Body}
+	if err := c.graphQL(ctx, addThreadReplyMutation, vars, &struct{}{}); err != nil {
+		return fmt.Errorf("replying to review thread: %w", err)
+	}
+	return nil
+}
+
+const (
+	resolveThreadMutation = `mutation($thread: ID!) {
+  resolveReviewThread(input: {threadId: $thread}) { thread { id } }
+}`
+	unresolveThreadMutation = `mutation($thread: ID!) {
+  unresolveReviewThread(input: {threadId: $thread}) { thread { id } }
+}`
+)
+
+// ResolveThread resolves or unresolves a review thread.
+func (c *Client) ResolveThread(ctx context.Context, r ThreadResolution) error {
+	mutation, verb := unresolveThreadMutation, "unresolving"
+	if r.Resolve {
+		mutation, verb = resolveThreadMutation, "resolving"
+	}
+	if err := c.graphQL(ctx, mutation, map[string]any{"thread": r.ThreadID}, &struct{}{}); err != nil {
+		return fmt.Errorf("%s review thread: %w", verb, err)
+	}
+	return nil
+}
+
+// graphQLError is an entry of the errors list of a GraphQL response.
+// Schema validation errors carry a code and the offending names in extensions.
+type graphQLError struct {
+	Message    string
+	Extensions struct {
+		Code         string
+		TypeName     string `json:"typeName"`
+		ArgumentName string `json:"argumentName"`
+	}
+}
+
+// graphQLErrors is the error returned for a GraphQL response with errors.
+type graphQLErrors []graphQLError
+
+func (e graphQLErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, ge := range e {
+		msgs[i] = ge.Message
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// graphQL runs a GraphQL query and decodes its data into out.
+// Errors in the response are returned as graphQLErrors.
+func (c *Client) graphQL(ctx context.Context, query string, vars map[string]any, out any) error {
+	req, err := c.inner.NewRequest("POST", c.graphQLURL(), map[string]any{"query": query, "variables": vars})
+	if err != nil {
+		return err
+	}
+	var resp struct {
+		Data   json.RawMessage
+		Errors graphQLErrors
+	}
+	if _, err := c.inner.Do(ctx, req, &resp); err != nil {
+		return err
+	}
+	if len(resp.Errors) > 0 {
+		return resp.Errors
+	}
+	return json.Unmarshal(resp.Data, out)
+}
+
+// graphQLURL returns the GraphQL endpoint for the REST base URL:
+// https://api.github.com/graphql, or https://{host}/api/graphql on GitHub
+// Enterprise Server.
+func (c *Client) graphQLURL() string {
+	base := *c.inner.BaseURL
+	if strings.HasSuffix(base.Path, "/api/v3/") {
+		base.Path = strings.TrimSuffix(base.Path, "v3/") + "graphql"
+		return base.String()
+	}
+	return base.String() + "graphql"
+}
@@ -0,0 +1,147 @@
+package markdown
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// AnchorContext is the number of context lines stored on each side of a
+// commented range, matching the default context of unified diffs.
+const AnchorContext = 3
+
+// LineAnchor fingerprints the lines a comment refers to, so the comment can be
+// relocated after the document changes.
+type LineAnchor struct {
+	Hash   string   `json:"hash"`             // truncated SHA-256 of the commented lines
+	Before []string `json:"before,omitempty"` // up to AnchorContext lines preceding the range
+	After  []string `json:"after,omitempty"`  // up to AnchorContext lines following the range
+}
+
+// NewLineAnchor fingerprints lines[startLine-1 : endLine] (1-based, inclusive;
+// endLine 0 means a single line). Returns nil if the range is out of bounds.
+func NewLineAnchor(lines []string, startLine, endLine int) *LineAnchor {
+	lo, hi := lineRangeIndex(startLine, endLine)
+	if lo < 0 || hi > len(lines) {
+		return nil
+	}
+	return &LineAnchor{
+		Hash:   linesHash(lines[lo:hi]),
+		Before: slices.Clone(lines[max(lo-AnchorContext, 0):lo]),
+		After:  slices.Clone(lines[hi:min(hi+AnchorContext, len(lines))]),
+	}
+}
+
+// Locate finds the new 1-based start line of an anchored range of the given
+// length in lines, the way patch applies a hunk at an offset: the nearest
+// position where the fingerprint matches is preferred, first with full
+// context, then ignoring progressively more outer context lines.
+// Returns false if the commented lines no longer exist.
+func (a *LineAnchor) Locate(lines []string, startLine, length int) (int, bool) {
+	lo := startLine - 1
+	for fuzz := 0; fuzz <= AnchorContext; fuzz++ {
+		for d := 0; d <= len(lines); d++ {
+			if a.matchesAt(lines, lo+d, length, fuzz) {
+				return lo + d + 1, true
+			}
+			if d > 0 && a.matchesAt(lines, lo-d, length, fuzz) {
+				return lo - d + 1, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// matchesAt reports whether the anchor matches lines at 0-based index lo,
+// ignoring up to fuzz outer context lines on each side.
+func (a *LineAnchor) matchesAt(lines []string, lo, length, fuzz int) bool {
+	hi := lo + length
+	if lo < 0 || hi > len(lines) || linesHash(lines[lo:hi]) != a.Hash {
+		return false
+	}
+	before := a.Before[min(fuzz, len(a.Before)):]
+	if lo-len(before) < 0 || !slices.Equal(lines[lo-len(before):lo], before) {
+		return false
+	}
+	after := a.After[:max(len(a.After)-fuzz, 0)]
+	return hi+len(after) <= len(lines) && slices.Equal(lines[hi:hi+len(after)], after)
+}
+
+// ReanchorComments relocates anchored line comments against doc's current source.
+// Comments that move are reassigned to the section now containing them;
+// comments whose lines can no longer be found are flagged as orphaned.
+// Suggestions without an anchor are located by the lines they replace.
+// Other unanchored line comments are only flagged if they fall past the end of
+// the document. Section-level comments are returned unchanged.
+func ReanchorComments(comments []ReviewComment, doc *Document) []ReviewComment {
+	result := make([]ReviewComment, len(comments))
+	for i, c := range comments {
+		result[i] = c
+		if c.StartLine == 0 || c.Orphaned {
+			continue
+		}
+		lo, hi := lineRangeIndex(c.StartLine, c.EndLine)
+		anchor := c.Anchor
+		if anchor == nil && c.Suggestion != nil && len(c.Suggestion.Original) == hi-lo {
+			anchor = &LineAnchor{Hash: linesHash(c.Suggestion.Original)}
+		}
+		if anchor == nil {
+			result[i].Orphaned = hi > len(doc.SourceLines)
+			continue
+		}
+		start, ok := anchor.Locate(doc.SourceLines, c.StartLine, hi-lo)
+		if !ok {
+			result[i].Orphaned = true
+			continue
+		}
+		delta := start - c.StartLine
+		result[i].StartLine = start
+		if c.EndLine > 0 {
+			result[i].EndLine = c.EndLine + delta
+		}
+		result[i].SectionID = doc.SectionIDAtLine(start)
+		result[i].Anchor = NewLineAnchor(doc.SourceLines, result[i].StartLine, result[i].EndLine)
+	}
+	return result
+}
+
+// DetachOrphans turns orphaned line comments into comments on their section,
+// so that no review output refers to lines that no longer hold what was
+// commented on. The body starts with the former lines, e.g. "L12-L14 (lines
+// changed): ...", and a suggestion is kept as a plain code block.
+func DetachOrphans(comments []ReviewComment) []ReviewComment {
+	result := make([]ReviewComment, len(comments))
+	for i, c := range comments {
+		result[i] = c
+		if !c.Orphaned || c.StartLine == 0 {
+			continue
+		}
+		body := c.FormatLineRef() + " (lines changed): " + c.Body
+		if c.Suggestion != nil {
+			body += "\n\n```\n" + strings.Join(c.Suggestion.Replacement, "\n") + "\n```"
+		}
+		result[i] = ReviewComment{
+			SectionID:  c.SectionID,
+			Action:     c.Action,
+			Decoration: c.Decoration,
+			Body:       strings.TrimSpace(body),
+		}
+	}
+	return result
+}
+
+// lineRangeIndex converts a 1-based inclusive line range (endLine 0 = single
+// line) into 0-based half-open slice indices.
+func lineRangeIndex(startLine, endLine int) (lo, hi int) {
+	if endLine < startLine {
+		endLine = startLine
+	}
+	return startLine - 1, endLine
+}
+
+// linesHash computes a truncated SHA-256 hash of the given lines.
+func linesHash(lines []string) string {
+	h := sha256.Sum256([]byte(strings.Join(lines, "\n")))
+	return fmt.Sprintf("%x", h[:8])
+}
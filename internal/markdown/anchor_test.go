@@ -0,0 +1,136 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewLineAnchor(t *testing.T) {
+	lines := strings.Split("a\nb\nc\nd\ne\nf\ng\nh", "\n")
+
+	a := NewLineAnchor(lines, 5, 6)
+	if a == nil {
+		t.Fatal("expected anchor")
+	}
+	if strings.Join(a.Before, ",") != "b,c,d" {
+		t.Errorf("Before = %v, want [b c d]", a.Before)
+	}
+	if strings.Join(a.After, ",") != "g,h" {
+		t.Errorf("After = %v, want [g h]", a.After)
+	}
+
+	if NewLineAnchor(lines, 8, 9) != nil {
+		t.Error("out-of-range anchor should be nil")
+	}
+	if NewLineAnchor(lines, 0, 0) != nil {
+		t.Error("zero start line should be nil")
+	}
+}
+
+func TestLineAnchorLocate(t *testing.T) {
+	original := strings.Split("1\n2\n3\ntarget\n5\n6\n7", "\n")
+	anchor := NewLineAnchor(original, 4, 0)
+
+	tests := []struct {
+		name   string
+		lines  string
+		want   int
+		wantOK bool
+	}{
+		{"unchanged", "1\n2\n3\ntarget\n5\n6\n7", 4, true},
+		{"shifted down", "0\n0\n1\n2\n3\ntarget\n5\n6\n7", 6, true},
+		{"shifted up", "3\ntarget\n5\n6\n7", 2, true},
+		{"outer context changed (fuzz)", "X\n2\n3\ntarget\n5\n6\nY", 4, true},
+		{"all context changed", "X\nY\nZ\ntarget\nX\nY\nZ", 4, true},
+		{"line removed", "1\n2\n3\n5\n6\n7", 0, false},
+		{"line edited", "1\n2\n3\ntarget!\n5\n6\n7", 0, false},
+		{
+			// A duplicate with matching context beats a nearer one without it.
+			"prefers matching context",
+			"target\n1\n2\n3\ntarget\n5\n6\n7", 5, true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := anchor.Locate(strings.Split(tt.lines, "\n"), 4, 1)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("Locate() = (%d, %v), want (%d, %v)", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestReanchorComments(t *testing.T) {
+	before, err := Parse([]byte("## A\n\nx\ny\n\n## B\n\nkeep\nrange end\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	comments := []ReviewComment{
+		{SectionID: "S1", Body: "section"},
+		{SectionID: "S2", Body: "range", StartLine: 8, EndLine: 9},
+		{SectionID: "S1", Body: "deleted", StartLine: 4},
+		{SectionID: "S1", Body: "unanchored", StartLine: 3},
+	}
+	comments[1].Anchor = NewLineAnchor(before.SourceLines, 8, 9)
+	comments[2].Anchor = NewLineAnchor(before.SourceLines, 4, 0)
+
+	// "y" is deleted and the "keep" block moves into a new section S3.
+	after, err := Parse([]byte("## A\n\nx\n\n## New\n\n## B\n\nkeep\nrange end\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := ReanchorComments(comments, after)
+
+	if got[0] != comments[0] {
+		t.Errorf("section comment changed: %+v", got[0])
+	}
+	if got[1].StartLine != 9 || got[1].EndLine != 10 || got[1].SectionID != "S3" || got[1].Orphaned {
+		t.Errorf("range comment = %+v, want L9-L10 in S3", got[1])
+	}
+	if !got[2].Orphaned {
+		t.Errorf("comment on deleted line should be orphaned: %+v", got[2])
+	}
+	if got[3].Orphaned || got[3].StartLine != 3 {
+		t.Errorf("unanchored comment in range should be unchanged: %+v", got[3])
+	}
+}
+
+func TestReanchorSuggestion(t *testing.T) {
+	doc, err := Parse([]byte("## A\n\nnew line\nx\ny\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	comments := []ReviewComment{
+		{SectionID: "S1", Body: "fix", StartLine: 3, Suggestion: &Suggestion{Original: []string{"x"}, Replacement: []string{"X"}}},
+		{SectionID: "S1", Body: "gone", StartLine: 3, Suggestion: &Suggestion{Original: []string{"z"}, Replacement: []string{"Z"}}},
+	}
+	got := ReanchorComments(comments, doc)
+	if got[0].StartLine != 4 || got[0].Orphaned {
+		t.Errorf("suggestion = %+v, want it moved to L4", got[0])
+	}
+	if !got[1].Orphaned {
+		t.Errorf("suggestion on a removed line should be orphaned: %+v", got[1])
+	}
+}
+
+func TestDetachOrphans(t *testing.T) {
+	comments := []ReviewComment{
+		{SectionID: "S1", Action: ActionNote, Body: "kept", StartLine: 3},
+		{SectionID: "S1", Action: ActionIssue, Decoration: DecorationBlocking, Body: "lost", StartLine: 4, EndLine: 5, Orphaned: true,
+			Anchor: &LineAnchor{Hash: "x"}},
+		{SectionID: "S2", Action: ActionSuggestion, Body: "rename", StartLine: 9, Orphaned: true,
+			Suggestion: &Suggestion{Original: []string{"a"}, Replacement: []string{"b"}}},
+	}
+	got := DetachOrphans(comments)
+	if got[0].StartLine != 3 || got[0].Body != "kept" {
+		t.Errorf("comment that is not orphaned changed: %+v", got[0])
+	}
+	want := ReviewComment{SectionID: "S1", Action: ActionIssue, Decoration: DecorationBlocking, Body: "L4-L5 (lines changed): lost"}
+	if got[1].SectionID != want.SectionID || got[1].Body != want.Body || got[1].StartLine != 0 || got[1].Anchor != nil || got[1].Orphaned ||
+		got[1].Decoration != want.Decoration {
+		t.Errorf("DetachOrphans()[1] = %+v, want %+v", got[1], want)
+	}
+	if got[2].Suggestion != nil || got[2].Body != "L9 (lines changed): rename\n\n```\nb\n```" {
+		t.Errorf("DetachOrphans()[2] = %+v, want the suggestion as a code block", got[2])
+	}
+}
@@ -0,0 +1,193 @@
+package markdown
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Annotation styles for Annotate.
+const (
+	AnnotateHTML = "html" // <!-- commd: [label] body -->
+	AnnotateNote = "note" // > [!NOTE] admonition, surrounded by blank lines
+)
+
+// annotationMarker starts the first line of every annotation, so Strip only
+// removes annotations written by Annotate.
+const annotationMarker = "commd:"
+
+var (
+	setextUnderlineRe = regexp.MustCompile(`^ {0,3}(=+|-+)\s*$`)
+	fenceRe           = regexp.MustCompile("^ {0,3}(```+|~~~+)")
+)
+
+// AnnotatedPath returns the default path of the annotated copy of filePath,
+// e.g. "plan.md" -> "plan.annotated.md".
+func AnnotatedPath(filePath string) string {
+	ext := filepath.Ext(filePath)
+	return strings.TrimSuffix(filePath, ext) + ".annotated" + ext
+}
+
+// Annotate returns a copy of source with each comment inserted as an
+// annotation in the given style: after the last line of a line comment's
+// range, after the heading of a section comment, and after the title for
+// overview comments. Annotations never split a fenced code
+// block or a setext heading. Comments whose target is not in d go at the end.
+func Annotate(source string, d *Document, comments []ReviewComment, style string) string {
+	lines := strings.Split(source, "\n")
+	trailingNewline := len(lines) > 1 && lines[len(lines)-1] == ""
+	if trailingNewline {
+		lines = lines[:len(lines)-1]
+	}
+
+	// after[i] holds the annotations inserted after line i (1-based; 0 = top of file).
+	after := make(map[int][]string)
+	fences := fencedRanges(lines)
+	for _, c := range comments {
+		at := annotationLine(d, lines, fences, c)
+		after[at] = append(after[at], formatAnnotation(c, style)...)
+	}
+
+	out := make([]string, 0, len(lines)+len(comments)*4)
+	out = append(out, after[0]...)
+	for i, l := range lines {
+		out = append(out, l)
+		out = append(out, after[i+1]...)
+	}
+	result := strings.Join(out, "\n")
+	if trailingNewline {
+		result += "\n"
+	}
+	return result
+}
+
+// annotationLine returns the 1-based line after which c's annotation goes.
+func annotationLine(d *Document, lines []string, fences [][2]int, c ReviewComment) int {
+	var at int
+	switch {
+	case c.StartLine > 0:
+		at = belowSetextUnderline(lines, max(c.StartLine, c.EndLine))
+	case c.SectionID == OverviewSectionID:
+		at = overviewLine(lines)
+	default:
+		s := d.FindSection(c.SectionID)
+		if s == nil || s.StartLine == 0 {
+			return len(lines)
+		}
+		at = belowSetextUnderline(lines, s.StartLine)
+	}
+	if at > len(lines) {
+		return len(lines)
+	}
+	for _, f := range fences {
+		if at >= f[0] && at < f[1] {
+			return f[1]
+		}
+	}
+	return at
+}
+
+// belowSetextUnderline returns at, or the line after it if line at is the
+// text of a setext heading, whose underline must directly follow it.
+func belowSetextUnderline(lines []string, at int) int {
+	if at > 0 && at < len(lines) && strings.TrimSpace(lines[at-1]) != "" && setextUnderlineRe.MatchString(lines[at]) {
+		return at + 1
+	}
+	return at
+}
+
+// overviewLine returns// overviewLine returns the line after which overview annotations go: the H1
+// title if the document starts with one, otherwise the top of the file.
+func overviewLine(lines []string) int {
+	for i, l := range lines {
+		if strings.TrimSpace(l) == "" {
+			continue
+		}
+		if isH1(l) {
+			return i + 1
+		}
+		break
+	}
+	return 0
+}
+
+// fencedRanges returns the 1-based [open, close] line ranges of fenced code blocks.
+// An unclosed fence runs to the end of the document.
+func fencedRanges(lines []string) [][2]int {
+	var ranges [][2]int
+	open, marker := 0, ""
+	for i, l := range lines {
+		m := fenceRe.FindStringSubmatch(l)
+		if m == nil {
+			continue
+		}
+		switch {
+		case open == 0:
+			open, marker = i+1, m[1]
+		case strings.Trim(strings.TrimSpace(l), marker[:1]) == "" && len(strings.TrimSpace(l)) >= len(marker):
+			ranges = append(ranges, [2]int{open, i + 1})
+			open = 0
+		}
+	}
+	if open != 0 {
+		ranges = append(ranges, [2]int{open, len(lines)})
+	}
+	return ranges
+}
+
+// formatAnnotation returns the lines inserted for c.
+func formatAnnotation(c ReviewComment, style string) []string {
+	text := fmt.Sprintf("%s [%s] %s", annotationMarker, c.FormatLabel(), c.Body)
+	if ref := c.FormatLineRef(); ref != "" {
+		text = fmt.Sprintf("%s `%s` [%s] %s", annotationMarker, ref, c.FormatLabel(), c.Body)
+	}
+	text = strings.TrimRight(text, " ") // suggestions may have no body
+	if style == AnnotateNote {
+		out := []string{"", "> [!NOTE]"}
+		for _, l := range strings.Split(text, "\n") {
+			out = append(out, strings.TrimRight("> "+l, " "))
+		}
+		return append(out, "")
+	}
+	// "-->" would end the comment early.
+	text = strings.ReplaceAll(text, "-->", "--&gt;")
+	return strings.Split("<!-- "+text+" -->", "\n")
+}
+
+// Strip removes the annotations written by Annotate from source, restoring
+// the original document. Other HTML comments and admonitions are kept.
+func Strip(source string) string {
+	lines := strings.Split(source, "\n")
+	out := make([]string, 0, len(lines))
+	for i := 0; i < len(lines); i++ {
+		l := lines[i]
+		if strings.HasPrefix(l, "<!-- "+annotationMarker) {
+			end := i
+			for end < len(lines) && !strings.HasSuffix(lines[end], "-->") {
+				end++
+			}
+			if end < len(lines) {
+				i = end
+				continue
+			}
+		}
+		if l == "> [!NOTE]" && i+1 < len(lines) && strings.HasPrefix(lines[i+1], "> "+annotationMarker) {
+			// Drop the blank line inserted before the block...
+			if len(out) > 0 && out[len(out)-1] == "" {
+				out = out[:len(out)-1]
+			}
+			i++
+			for i+1 < len(lines) && strings.HasPrefix(lines[i+1], ">") {
+				i++
+			}
+			// ...and the one after it.
+			if i+1 < len(lines) && lines[i+1] == "" {
+				i++
+			}
+			continue
+		}
+		out = append(out, l)
+	}
+	return strings.Join(out, "\n")
+}
@@ -0,0 +1,141 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+)
+
+const annotateTestSource = `# Plan
+
+Intro.
+
+Setup
+-----
+
+Install deps.
+` + "```sh" + `
+npm ci
+npm test
+` + "```" + `
+Done.
+
+## Deploy
+
+Run deploy.
+`
+
+func annotateTestComments() []ReviewComment {
+	return []ReviewComment{
+		{SectionID: OverviewSectionID, Action: ActionNote, Body: "Overall fine."},
+		{SectionID: "S1", Action: ActionIssue, Decoration: DecorationBlocking, Body: "Pin versions.\nUse a lockfile."},
+		{SectionID: "S1", Action: ActionQuestion, Body: "Why npm?", StartLine: 10},
+		{SectionID: "S2", Action: ActionNitpick, Body: "Typo --> fix", StartLine: 17, EndLine: 17},
+		{SectionID: "S9", Action: ActionTodo, Body: "Gone."},
+	}
+}
+
+func TestAnnotateHTML(t *testing.T) {
+	doc, err := Parse([]byte(annotateTestSource))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := Annotate(annotateTestSource, doc, annotateTestComments(), AnnotateHTML)
+	want := `# Plan
+<!-- commd: [note] Overall fine. -->
+
+Intro.
+
+Setup
+-----
+<!-- commd: [issue (blocking)] Pin versions.
+Use a lockfile. -->
+
+Install deps.
+` + "```sh" + `
+npm ci
+npm test
+` + "```" + `
+<!-- commd: ` + "`L10`" + ` [question] Why npm? -->
+Done.
+
+## Deploy
+
+Run deploy.
+<!-- commd: ` + "`L17`" + ` [nitpick] Typo --&gt; fix -->
+<!-- commd: [todo] Gone. -->
+`
+	if got != want {
+		t.Errorf("Annotate() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestAnnotateNote(t *testing.T) {
+	doc, err := Parse([]byte(annotateTestSource))
+	if err != nil {
+		t.Fatal(err)
+	}
+	comments := annotateTestComments()[1:2]
+	got := Annotate(annotateTestSource, doc, comments, AnnotateNote)
+	want := "Setup\n-----\n\n> [!NOTE]\n> commd: [issue (blocking)] Pin versions.\n> Use a lockfile.\n\n\nInstall deps.\n"
+	if !strings.Contains(got, want) {
+		t.Errorf("Annotate() =\n%s\nwant to contain:\n%s", got, want)
+	}
+}
+
+func TestAnnotateLineCommentOnSetextHeading(t *testing.T) {
+	source := "# Plan\n\nTitle\n=====\n\nText.\nMore\n\n---\n"
+	doc, err := Parse([]byte(source))
+	if err != nil {
+		t.Fatal(err)
+	}
+	comments := []ReviewComment{
+		{SectionID: "S1", Action: ActionNote, Body: "x", StartLine: 3},
+		{SectionID: "S1", Action: ActionNote, Body: "y", StartLine: 7},
+	}
+	got := Annotate(source, doc, comments, AnnotateHTML)
+	// The underline stays under the heading text; a thematic break after a
+	// blank line is not an underline.
+	want := "# Plan\n\nTitle\n=====\n<!-- commd: `L3` [note] x -->\n\nText.\nMore\n<!-- commd: `L7` [note] y -->\n\n---\n"
+	if got != want {
+		t.Errorf("Annotate() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestStripRoundTrip(t *testing.T) {
+	doc, err := Parse([]byte(annotateTestSource))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, style := range []string{AnnotateHTML, AnnotateNote} {
+		t.Run(style, func(t *testing.T) {
+			annotated := Annotate(annotateTestSource, doc, annotateTestComments(), style)
+			if annotated == annotateTestSource {
+				t.Fatal("Annotate() did not change the source")
+			}
+			if got := Strip(annotated); got != annotateTestSource {
+				t.Errorf("Strip(Annotate()) =\n%s\nwant original:\n%s", got, annotateTestSource)
+			}
+		})
+	}
+}
+
+func TestStripKeepsOtherComments(t *testing.T) {
+	source := "# Plan\n<!-- keep me -->\n\n> [!NOTE]\n> Real note.\n\n<!-- commd: [note] x -->\nText.\n"
+	want := "# Plan\n<!-- keep me -->\n\n> [!NOTE]\n> Real note.\n\nText.\n"
+	if got := Strip(source); got != want {
+		t.Errorf("Strip() =\n%q\nwant:\n%q", got, want)
+	}
+}
+
+func TestAnnotatedPath(t *testing.T) {
+	tests := map[string]string{
+		"plan.md":     "plan.annotated.md",
+		"docs/a.b.md": "docs/a.b.annotated.md",
+		"README":      "README.annotated",
+	}
+	for in, want := range tests {
+		if got := AnnotatedPath(in); got != want {
+			t.Errorf("AnnotatedPath(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
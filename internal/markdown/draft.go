@@ -0,0 +1,178 @@
+package markdown
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// Draft holds in-progress review comments and section verdicts persisted
+// between sessions.
+type Draft struct {
+	Comments []DraftComment     `json:"comments"`
+	Verdicts map[string]Verdict `json:"verdicts,omitempty"` // keyed by heading path joined with " > "
+}
+
+// DraftComment is a ReviewComment anchored by its section's heading path instead
+// of its positional ID, so it survives edits that renumber sections.
+// Line numbers are relative to the section heading (heading = 1); overview
+// comments use absolute line numbers.
+type DraftComment struct {
+	Section    []string    `json:"section,omitempty"` // heading path (empty = overview)
+	Action     ActionType  `json:"action"`
+	Decoration Decoration  `json:"decoration,omitempty"`
+	Body       string      `json:"body"`
+	StartLine  int         `json:"start_line,omitempty"` // 0 = section-level comment
+	EndLine    int         `json:"end_line,omitempty"`
+	Side       string      `json:"side,omitempty"`
+	Anchor     *LineAnchor `json:"anchor,omitempty"`
+	Orphaned   bool        `json:"orphaned,omitempty"`
+	Suggestion *Suggestion `json:"suggestion,omitempty"`
+}
+
+// DraftPath returns the sidecar file path for persisting draft comments.
+func DraftPath(filePath string) string {
+	return filePath + ".draft.json"
+}
+
+// NewDraft builds a Draft from the comments and verdicts of result on doc.
+// Comments and verdicts whose section cannot be found in doc are skipped.
+func NewDraft(result *ReviewResult, doc *Document) *Draft {
+	d := &Draft{}
+	for id, v := range result.Verdicts {
+		s := doc.FindSection(id)
+		if s == nil || v == VerdictNone {
+			continue
+		}
+		if d.Verdicts == nil {
+			d.Verdicts = make(map[string]Verdict)
+		}
+		d.Verdicts[viewedKey(s)] = v
+	}
+	for _, c := range result.Comments {
+		dc := DraftComment{
+			Action:     c.Action,
+			Decoration: c.Decoration,
+			Body:       c.Body,
+			StartLine:  c.StartLine,
+			EndLine:    c.EndLine,
+			Side:       c.Side,
+			Anchor:     c.Anchor,
+			Orphaned:   c.Orphaned,
+			Suggestion: c.Suggestion,
+		}
+		if c.SectionID != OverviewSectionID {
+			s := doc.FindSection(c.SectionID)
+			if s == nil {
+				continue
+			}
+			dc.Section = s.HeadingPath()
+			if c.StartLine > 0 {
+				dc.StartLine = c.StartLine - s.StartLine + 1
+				if c.EndLine > 0 {
+					dc.EndLine = c.EndLine - s.StartLine + 1
+				}
+			}
+		}
+		d.Comments = append(d.Comments, dc)
+	}
+	return d
+}
+
+// Restore maps draft comments back onto doc by heading path, then relocates
+// anchored line comments with ReanchorComments. Comments whose section or lines
+// no longer exist are kept but flagged as orphaned; orphaned comments whose
+// section is gone are attached to the overview.
+func (d *Draft) Restore(doc *Document) []ReviewComment {
+	comments := make([]ReviewComment, 0, len(d.Comments))
+	for _, dc := range d.Comments {
+		c := ReviewComment{
+			SectionID:  OverviewSectionID,
+			Action:     dc.Action,
+			Decoration: dc.Decoration,
+			Body:       dc.Body,
+			StartLine:  dc.StartLine,
+			EndLine:    dc.EndLine,
+			Side:       dc.Side,
+			Anchor:     dc.Anchor,
+			Orphaned:   dc.Orphaned,
+			Suggestion: dc.Suggestion,
+		}
+		if len(dc.Section) > 0 {
+			if s := doc.FindSectionByPath(dc.Section); s != nil {
+				c.SectionID = s.ID
+				if dc.StartLine > 0 {
+					c.StartLine = s.StartLine + dc.StartLine - 1
+					if dc.EndLine > 0 {
+						c.EndLine = s.StartLine + dc.EndLine - 1
+					}
+				}
+			} else if dc.StartLine == 0 || dc.Anchor == nil {
+				// Without a section or fingerprint there is nothing to re-anchor to.
+				c.Orphaned = true
+			}
+		}
+		comments = append(comments, c)
+	}
+	return ReanchorComments(comments, doc)
+}
+
+// RestoreVerdicts maps draft verdicts back onto doc by heading path, keyed by
+// section ID. Verdicts whose section no longer exists are dropped.
+func (d *Draft) RestoreVerdicts(doc *Document) map[string]Verdict {
+	if len(d.Verdicts) == 0 {
+		return nil
+	}
+	verdicts := make(map[string]Verdict)
+	for _, s := range doc.AllSections() {
+		if v, ok := d.Verdicts[viewedKey(s)]; ok {
+			verdicts[s.ID] = v
+		}
+	}
+	return verdicts
+}
+
+// IsEmpty reports whether the draft holds neither comments nor verdicts.
+func (d *Draft) IsEmpty() bool {
+	return d == nil || (len(d.Comments) == 0 && len(d.Verdicts) == 0)
+}
+
+// LoadDraft reads a draft file. Returns nil without error if the file does not exist.
+func LoadDraft(path string) (*Draft, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading draft: %w", err)
+	}
+	var d Draft
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, fmt.Errorf("parsing draft: %w", err)
+	}
+	return &d, nil
+}
+
+// SaveDraft writes the draft to a JSON file. An empty draft removes the file.
+func SaveDraft(path string, d *Draft) error {
+	if d.IsEmpty() {
+		return RemoveDraft(path)
+	}
+	data, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling draft: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("writing draft: %w", err)
+	}
+	return nil
+}
+
+// RemoveDraft deletes the draft file. A missing file is not an error.
+func RemoveDraft(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("removing draft: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+package markdown
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDraftPath(t *testing.T) {
+	if got := DraftPath("/plans/plan.md"); got != "/plans/plan.md.draft.json" {
+		t.Errorf("DraftPath() = %q, want %q", got, "/plans/plan.md.draft.json")
+	}
+}
+
+func TestDraftRestoreSurvivesRenumbering(t *testing.T) {
+	before, err := Parse([]byte("# Plan\n\n## Setup\n\nA\nB\n\n## Tests\n\nC\nD\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	comments := []ReviewComment{
+		{SectionID: OverviewSectionID, Action: ActionNote, Body: "overview"},
+		{SectionID: "S2", Action: ActionIssue, Decoration: DecorationBlocking, Body: "section"},
+		{SectionID: "S2", Action: ActionQuestion, Body: "line", StartLine: 10, EndLine: 11},
+	}
+	draft := NewDraft(&ReviewResult{Comments: comments}, before)
+
+	// A new section inserted before "Tests" shifts its ID and line numbers.
+	after, err := Parse([]byte("# Plan\n\n## Setup\n\nA\nB\n\n## New\n\nX\n\n## Tests\n\nC\nD\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := draft.Restore(after)
+	want := []ReviewComment{
+		{SectionID: OverviewSectionID, Action: ActionNote, Body: "overview"},
+		{SectionID: "S3", Action: ActionIssue, Decoration: DecorationBlocking, Body: "section"},
+		{SectionID: "S3", Action: ActionQuestion, Body: "line", StartLine: 14, EndLine: 15},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d comments, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("comment[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestDraftRestoreOrphans(t *testing.T) {
+	doc, err := Parse([]byte("## Kept\n\nA\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	draft := &Draft{Comments: []DraftComment{
+		{Section: []string{"Removed"}, Action: ActionNote, Body: "gone"},
+		{Section: []string{"Kept"}, Action: ActionNote, Body: "too far", StartLine: 10},
+		{Section: []string{"Kept"}, Action: ActionNote, Body: "ok"},
+	}}
+
+	got := draft.Restore(doc)
+	if len(got) != 3 {
+		t.Fatalf("got %d comments, want 3: %+v", len(got), got)
+	}
+	wantOrphaned := []bool{true, true, false}
+	for i, want := range wantOrphaned {
+		if got[i].Orphaned != want {
+			t.Errorf("comment %q Orphaned = %v, want %v", got[i].Body, got[i].Orphaned, want)
+		}
+	}
+	if got[0].SectionID != OverviewSectionID {
+		t.Errorf("orphan with missing section should attach to overview, got %q", got[0].SectionID)
+	}
+}
+
+func TestDraftRestoreReanchors(t *testing.T) {
+	before, err := Parse([]byte("## Steps\n\nintro\ntarget line\nafter\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := ReviewComment{SectionID: "S1", Action: ActionNote, Body: "here", StartLine: 4}
+	c.Anchor = NewLineAnchor(before.SourceLines, 4, 0)
+	draft := NewDraft(&ReviewResult{Comments: []ReviewComment{c}}, before)
+
+	// Lines inserted inside the section move the target down by two.
+	after, err := Parse([]byte("## Steps\n\nintro\nnew 1\nnew 2\ntarget line\nafter\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := draft.Restore(after)
+	if len(got) != 1 || got[0].StartLine != 6 || got[0].Orphaned {
+		t.Errorf("restored = %+v, want StartLine 6, not orphaned", got)
+	}
+}
+
+func TestDraftRestoreVerdicts(t *testing.T) {
+	before, err := Parse([]byte("# Plan\n\n## Setup\n\n## Tests\n\n### Unit\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	draft := NewDraft(&ReviewResult{Verdicts: map[string]Verdict{
+		"S1":      VerdictApprove,
+		"S2":      VerdictReject,
+		"S3":      VerdictNone,
+		"missing": VerdictNeedsChanges,
+	}}, before)
+	wantDraft := map[string]Verdict{
+		"Setup": VerdictApprove,
+		"Tests": VerdictReject,
+	}
+	if !maps.Equal(draft.Verdicts, wantDraft) {
+		t.Errorf("draft verdicts = %v, want %v", draft.Verdicts, wantDraft)
+	}
+
+	// "Setup" is removed: its verdict is dropped and "Tests" moves to S1.
+	after, err := Parse([]byte("# Plan\n\n## Tests\n\n### Unit\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := draft.RestoreVerdicts(after)
+	want := map[string]Verdict{"S1": VerdictReject}
+	if !maps.Equal(got, want) {
+		t.Errorf("RestoreVerdicts() = %v, want %v", got, want)
+	}
+}
+
+func TestSaveLoadRemoveDraft(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plan.md.draft.json")
+
+	// Missing file is not an error
+	d, err := LoadDraft(path)
+	if err != nil || d != nil {
+		t.Fatalf("LoadDraft(missing) = %v, %v; want nil, nil", d, err)
+	}
+
+	draft := &Draft{Comments: []DraftComment{{Section: []string{"S"}, Action: ActionNote, Body: "hi"}}}
+	if err := SaveDraft(path, draft); err != nil {
+		t.Fatalf("SaveDraft: %v", err)
+	}
+	loaded, err := LoadDraft(path)
+	if err != nil {
+		t.Fatalf("LoadDraft: %v", err)
+	}
+	if len(loaded.Comments) != 1 || loaded.Comments[0].Body != "hi" {
+		t.Errorf("loaded = %+v", loaded)
+	}
+
+	// A draft with only verdicts is kept
+	verdictsOnly := &Draft{Verdicts: map[string]Verdict{"S": VerdictApprove}}
+	if err := SaveDraft(path, verdictsOnly); err != nil {
+		t.Fatalf("SaveDraft(verdicts): %v", err)
+	}
+	loaded, err = LoadDraft(path)
+	if err != nil {
+		t.Fatalf("LoadDraft: %v", err)
+	}
+	if loaded.IsEmpty() || loaded.Verdicts["S"] != VerdictApprove {
+		t.Errorf("loaded = %+v, want verdict kept", loaded)
+	}
+
+	// Empty draft removes the file
+	if err := SaveDraft(path, &Draft{}); err != nil {
+		t.Fatalf("SaveDraft(empty): %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("empty draft should remove the file")
+	}
+
+	if err := RemoveDraft(path); err != nil {
+		t.Errorf("RemoveDraft(missing) = %v, want nil", err)
+	}
+}
+
+func TestLoadDraftInvalidJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bad.json")
+	if err := os.WriteFile(path, []byte("not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadDraft(path); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+}
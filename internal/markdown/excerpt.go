@@ -0,0 +1,168 @@
+package markdown
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Limits for source quotes in review output.
+const (
+	maxQuoteLines      = 8   // line comment ranges longer than this keep only their head and tail
+	maxExcerptLines    = 3   // non-blank lines quoted for a section comment
+	maxQuoteLineLength = 120 // longer source lines are cut off with "…"
+)
+
+// quoteLineRe matches the lines written by formatQuote, so ParseReview can
+// drop them when reading a review back.
+var quoteLineRe = regexp.MustCompile(`^> (?:L\d+:(?: .*)?|… \(\d+ lines? omitted\))$`)
+
+// quotedSourceRe matches a quoted source line, capturing its number and text.
+var quotedSourceRe = regexp.MustCompile(`^> L(\d+):(?: (.*))?$`)
+
+type quotedLine struct {
+	number int
+	text   string
+}
+
+// lineQuote returns the quote block for a line comment on [start, end]
+// (end == 0 means a single line). Ranges longer than maxQuoteLines keep
+// their first and last lines with the middle elided.
+func lineQuote(d *Document, start, end int) string {
+	src := sourceLines(d, start, end)
+	lines := make([]quotedLine, len(src))
+	for i, text := range src {
+		lines[i] = quotedLine{number: start + i, text: text}
+	}
+	if len(lines) <= maxQuoteLines {
+		return formatQuote(lines, nil, 0)
+	}
+	head := maxQuoteLines / 2
+	tail := maxQuoteLines - head
+	return formatQuote(lines[:head], lines[len(lines)-tail:], len(lines)-maxQuoteLines)
+}
+
+// sectionExcerpt returns a quote of the first non-blank body lines of the
+// section (the preamble for OverviewSectionID). Returns "" if there is no body.
+func sectionExcerpt(d *Document, id string) string {
+	var start, end int
+	if id == OverviewSectionID {
+		start, end = 1, len(d.SourceLines)
+		for _, s := range d.AllSections() {
+			if s.StartLine > 0 {
+				end = s.StartLine - 1
+				break
+			}
+		}
+	} else {
+		s := d.FindSection(id)
+		if s == nil || s.StartLine == 0 {
+			return ""
+		}
+		start, end = s.StartLine+1, s.EndLine
+	}
+
+	var lines []quotedLine
+	omitted := 0
+	for i, text := range sourceLines(d, start, end) {
+		if strings.TrimSpace(text) == "" || isH1(text) {
+			continue
+		}
+		if len(lines) == maxExcerptLines {
+			omitted++
+			continue
+		}
+		lines = append(lines, quotedLine{number: start + i, text: text})
+	}
+	return formatQuote(lines, nil, omitted)
+}
+
+// formatQuote renders head, an omission marker if omitted > 0, then tail as
+// "> L12: text" lines.
+func formatQuote(head, tail []quotedLine, omitted int) string {
+	if len(head) == 0 {
+		return ""
+	}
+	var out []string
+	for _, l := range head {
+		out = append(out, formatQuotedLine(l))
+	}
+	if omitted > 0 {
+		unit := "lines"
+		if omitted == 1 {
+			unit = "line"
+		}
+		out = append(out, fmt.Sprintf("> … (%d %s omitted)", omitted, unit))
+	}
+	for _, l := range tail {
+		out = append(out, formatQuotedLine(l))
+	}
+	return strings.Join(out, "\n")
+}
+
+func formatQuotedLine(l quotedLine) string {
+	return strings.TrimRight(fmt.Sprintf("> L%d: %s", l.number, quoteText(l.text)), " ")
+}
+
+// quoteText returns a source line as quoted: without trailing blanks and cut
+// off after maxQuoteLineLength characters.
+func quoteText(text string) string {
+	text = strings.TrimRight(text, " \t")
+	if r := []rune(text); len(r) > maxQuoteLineLength {
+		text = string(r[:maxQuoteLineLength]) + "…"
+	}
+	return text
+}
+
+// isH1 reports whether line is a level-1 heading (the document title).
+func isH1(line string) bool {
+	return strings.HasPrefix(line, "# ")
+}
+
+// splitSourceQuote splits a trailing quote block written by formatQuote off a
+// comment body read back by ParseReview. Returns the body and the quoted
+// lines, without the omitted ones.
+func splitSourceQuote(body string) (string, []quotedLine) {
+	lines := strings.Split(body, "\n")
+	n := len(lines)
+	for n > 0 && (quoteLineRe.MatchString(lines[n-1]) || strings.TrimSpace(lines[n-1]) == "") {
+		n--
+	}
+	var quote []quotedLine
+	for _, l := range lines[n:] {
+		if m := quotedSourceRe.FindStringSubmatch(l); m != nil {
+			number, _ := strconv.Atoi(m[1])
+			quote = append(quote, quotedLine{number: number, text: m[2]})
+		}
+	}
+	return strings.Join(lines[:n], "\n"), quote
+}
+
+// locateQuote returns how far the quoted lines have moved in lines: the
+// offset nearest to 0 at which every quoted line matches the source as
+// formatQuotedLine would quote it. Returns false if there is no such offset.
+func locateQuote(quote []quotedLine, lines []string) (int, bool) {
+	if len(quote) == 0 {
+		return 0, false
+	}
+	for d := 0; d <= len(lines); d++ {
+		if quoteMatchesAt(quote, lines, d) {
+			return d, true
+		}
+		if d > 0 && quoteMatchesAt(quote, lines, -d) {
+			return -d, true
+		}
+	}
+	return 0, false
+}
+
+func quoteMatchesAt(quote []quotedLine, lines []string, offset int) bool {
+	for _, q := range quote {
+		n := q.number + offset
+		if n < 1 || n > len(lines) || quoteText(lines[n-1]) != q.text {
+			return false
+		}
+	}
+	return true
+}
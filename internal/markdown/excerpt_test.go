@@ -0,0 +1,133 @@
+package markdown
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestLineQuote(t *testing.T) {
+	src := make([]string, 30)
+	for i := range src {
+		src[i] = fmt.Sprintf("line %d", i+1)
+	}
+	src[2] = ""
+	src[3] = strings.Repeat("x", maxQuoteLineLength+10)
+	d := &Document{SourceLines: src}
+
+	tests := []struct {
+		name       string
+		start, end int
+		want       string
+	}{
+		{"single line", 2, 0, "> L2: line 2"},
+		{"blank line", 3, 3, "> L3:"},
+		{"long line truncated", 4, 0, "> L4: " + strings.Repeat("x", maxQuoteLineLength) + "…"},
+		{"short range", 5, 7, "> L5: line 5\n> L6: line 6\n> L7: line 7"},
+		{"long range keeps head and tail", 11, 30, "> L11: line 11\n> L12: line 12\n> L13: line 13\n> L14: line 14\n" +
+			"> … (12 lines omitted)\n" +
+			"> L27: line 27\n> L28: line 28\n> L29: line 29\n> L30: line 30"},
+		{"out of range", 40, 41, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := lineQuote(d, tt.start, tt.end); got != tt.want {
+				t.Errorf("lineQuote(%d, %d) =\n%s\nwant:\n%s", tt.start, tt.end, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSectionExcerpt(t *testing.T) {
+	source := "# Plan\n\nIntro text.\n\n## Setup\n\nOne.\nTwo.\n\nThree.\nFour.\nFive.\n## Empty\n"
+	doc, err := Parse([]byte(source))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		id   string
+		want string
+	}{
+		{OverviewSectionID, "> L3: Intro text."},
+		{"S1", "> L7: One.\n> L8: Two.\n> L10: Three.\n> … (2 lines omitted)"},
+		{"S2", ""},
+		{"S9", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			if got := sectionExcerpt(doc, tt.id); got != tt.want {
+				t.Errorf("sectionExcerpt(%s) =\n%s\nwant:\n%s", tt.id, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatReviewQuoteRoundTrip(t *testing.T) {
+	source := "# Plan\n\nIntro.\n\n## Setup\n\nInstall deps.\nConfigure.\n\n## Deploy\n\nRun deploy.\n"
+	doc, err := Parse([]byte(source))
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := &ReviewResult{Comments: []ReviewComment{
+		{SectionID: "S1", Action: ActionIssue, Body: "Pin versions.\n\n> quoted by the reviewer"},
+		{SectionID: "S2", Action: ActionQuestion, Body: "Which env?", StartLine: 12},
+		{SectionID: "S1", Action: ActionNitpick, Body: "Typo.", StartLine: 7, EndLine: 8},
+	}}
+
+	out := FormatReviewWith(result, doc, "plan.md", FormatOptions{Quote: true})
+	for _, want := range []string{
+		"## S1: Setup\n> L7: Install deps.\n> L8: Configure.\n\n[issue] Pin versions.",
+		"`L12` [question] Which env?\n\n> L12: Run deploy.\n",
+		"`L7-L8` [nitpick] Typo.\n\n> L7: Install deps.\n> L8: Configure.\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+
+	parsed, orphans, err := ParseReview([]byte(out), doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(orphans) != 0 || len(parsed.Comments) != len(result.Comments) {
+		t.Fatalf("ParseReview() = %+v, orphans %+v", parsed.Comments, orphans)
+	}
+	for i, c := range parsed.Comments {
+		if c.Body != result.Comments[i].Body {
+			t.Errorf("comment %d body = %q, want %q (source quote not stripped)", i, c.Body, result.Comments[i].Body)
+		}
+	}
+}
+
+func TestParseReviewRelocatesQuotedComments(t *testing.T) {
+	source := "# Plan\n\n## Setup\n\nInstall deps.\nConfigure.\n\n## Deploy\n\nRun deploy.\n"
+	doc, err := Parse([]byte(source))
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := &ReviewResult{Comments: []ReviewComment{
+		{SectionID: "S1", Action: ActionNitpick, Body: "Typo.", StartLine: 5, EndLine: 6},
+		{SectionID: "S2", Action: ActionQuestion, Body: "Which env?", StartLine: 10},
+	}}
+	out := FormatReviewWith(result, doc, "plan.md", FormatOptions{Quote: true})
+
+	// Two lines inserted above Setup; the deploy line is reworded.
+	edited, err := Parse([]byte("# Plan\n\nIntro.\n\n## Setup\n\nInstall deps.\nConfigure.\n\n## Deploy\n\nRun deploy now.\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, orphans, err := ParseReview([]byte(out), edited)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(orphans) != 0 || len(parsed.Comments) != 2 {
+		t.Fatalf("ParseReview() = %+v, orphans %+v", parsed.Comments, orphans)
+	}
+	if c := parsed.Comments[0]; c.StartLine != 7 || c.EndLine != 8 || c.SectionID != "S1" || c.Orphaned {
+		t.Errorf("moved comment = %+v, want L7-L8 in S1", c)
+	}
+	if c := parsed.Comments[1]; !c.Orphaned || c.Body != "Which env?" {
+		t.Errorf("comment on the changed line = %+v, want orphaned", c)
+	}
+}
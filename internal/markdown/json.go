@@ -0,0 +1,63 @@
+package markdown
+
+import "encoding/json"
+
+// JSONReview is the JSON form of a review.
+type JSONReview struct {
+	File     string        `json:"file,omitempty"`
+	Comments []JSONComment `json:"comments"`
+	Verdicts []JSONVerdict `json:"verdicts,omitempty"`
+	Policy   *PolicyResult `json:"policy,omitempty"`
+}
+
+// JSONComment is a review comment in JSONReview.
+type JSONComment struct {
+	Section    string      `json:"section"` // section ID, or OverviewSectionID
+	Heading    string      `json:"heading"` // "S1: Title", or "Overview"
+	Label      ActionType  `json:"label"`
+	Decoration Decoration  `json:"decoration,omitempty"`
+	Body       string      `json:"body"`
+	StartLine  int         `json:"start_line,omitempty"` // 0 = section-level comment
+	EndLine    int         `json:"end_line,omitempty"`
+	Suggestion *Suggestion `json:"suggestion,omitempty"`
+}
+
+// JSONVerdict is a section verdict in JSONReview.
+type JSONVerdict struct {
+	Section string  `json:"section"`
+	Heading string  `json:"heading"`
+	Verdict Verdict `json:"verdict"`
+}
+
+// NewJSONReview builds the JSON form of result. Comments keep the order they
+// were added; verdicts are in document order.
+func NewJSONReview(result *ReviewResult, d *Document, filePath string) *JSONReview {
+	r := &JSONReview{File: filePath, Comments: []JSONComment{}, Policy: result.Policy}
+	for _, c := range result.Comments {
+		r.Comments = append(r.Comments, JSONComment{
+			Section:    c.SectionID,
+			Heading:    newSectionComments(d, c.SectionID).Heading,
+			Label:      c.Action,
+			Decoration: c.Decoration,
+			Body:       c.Body,
+			StartLine:  c.StartLine,
+			EndLine:    c.EndLine,
+			Suggestion: c.Suggestion,
+		})
+	}
+	for _, v := range SectionVerdicts(result, d) {
+		r.Verdicts = append(r.Verdicts, JSONVerdict{Section: v.ID, Heading: v.Heading, Verdict: v.Verdict})
+	}
+	return r
+}
+
+// FormatReviewJSON formats result as indented JSON. Returns "" if the review
+// has no comments or verdicts, like FormatReview.
+func FormatReviewJSON(result *ReviewResult, d *Document, filePath string) string {
+	if result.IsEmpty() {
+		return ""
+	}
+	// JSONReview only holds strings, ints, bools and slices, so marshaling cannot fail.
+	data, _ := json.MarshalIndent(NewJSONReview(result, d, filePath), "", "  ")
+	return string(data) + "\n"
+}
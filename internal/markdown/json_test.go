@@ -0,0 +1,52 @@
+package markdown
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestFormatReviewJSON(t *testing.T) {
+	result := &ReviewResult{
+		Comments: []ReviewComment{
+			{SectionID: OverviewSectionID, Action: ActionNote, Body: "Overall fine."},
+			{SectionID: "S1", Action: ActionSuggestion, Decoration: DecorationBlocking, Body: "Pin it.", StartLine: 5,
+				Suggestion: &Suggestion{Original: []string{"Install deps."}, Replacement: []string{"Install pinned deps."}}},
+		},
+		Verdicts: map[string]Verdict{"S2": VerdictReject},
+	}
+	out := FormatReviewJSON(result, templateTestDoc(), "plan.md")
+
+	var got JSONReview
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if got.File != "plan.md" || len(got.Comments) != 2 || len(got.Verdicts) != 1 {
+		t.Fatalf("FormatReviewJSON() = %s", out)
+	}
+	if c := got.Comments[0]; c.Section != OverviewSectionID || c.Heading != "Overview" || c.Label != ActionNote {
+		t.Errorf("overview comment = %+v", c)
+	}
+	if c := got.Comments[1]; c.Heading != "S1: Setup" || c.Decoration != DecorationBlocking || c.StartLine != 5 ||
+		c.Suggestion == nil || c.Suggestion.Replacement[0] != "Install pinned deps." {
+		t.Errorf("line comment = %+v", c)
+	}
+	if v := got.Verdicts[0]; v.Section != "S2" || v.Heading != "S2: Deploy" || v.Verdict != VerdictReject {
+		t.Errorf("verdict = %+v", v)
+	}
+
+	if got.Policy != nil {
+		t.Errorf("policy = %+v, want omitted without a policy", got.Policy)
+	}
+
+	result.Policy = Policy{MinViewed: 50}.Check(result.Comments, 1, 2, false)
+	if err := json.Unmarshal([]byte(FormatReviewJSON(result, templateTestDoc(), "plan.md")), &got); err != nil {
+		t.Fatal(err)
+	}
+	if p := got.Policy; p == nil || p.Viewed != 1 || p.Sections != 2 || len(p.Checks) != 1 || !p.Checks[0].Passed {
+		t.Errorf("policy = %+v", p)
+	}
+
+	if out := FormatReviewJSON(&ReviewResult{}, templateTestDoc(), "plan.md"); out != "" {
+		t.Errorf("empty review = %q, want empty", out)
+	}
+}
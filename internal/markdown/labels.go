@@ -0,0 +1,108 @@
+package markdown
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+)
+
+// LabelSet is the set of comment labels offered by the comment editor and
+// accepted when parsing a review file.
+type LabelSet struct {
+	Actions     []ActionType          // Action labels in Tab cycling order
+	Decorations []Decoration          // Decorations in cycling order (first entry is DecorationNone)
+	Default     ActionType            // Action preselected for new comments
+	Colors      map[ActionType]string // Display colour per action (ANSI 256 code or #rrggbb)
+}
+
+// Labels is the active label set. Replace it with SetLabels.
+var Labels = DefaultLabels()
+
+// DefaultLabels returns the built-in Conventional Comments label set.
+func DefaultLabels() LabelSet {
+	return LabelSet{
+		Actions:     slices.Clone(ActionLabels),
+		Decorations: slices.Clone(DecorationLabels),
+		Default:     DefaultAction,
+	}
+}
+
+// SetLabels validates ls and makes it the active label set.
+func SetLabels(ls LabelSet) error {
+	if err := ls.Validate(); err != nil {
+		return err
+	}
+	Labels = ls
+	return nil
+}
+
+// Validate reports whether the label set can be used by the editor and the review format.
+func (ls LabelSet) Validate() error {
+	if len(ls.Actions) == 0 {
+		return errors.New("label set has no actions")
+	}
+	seen := make(map[ActionType]bool)
+	for _, a := range ls.Actions {
+		if !reviewLabelNameRe.MatchString(string(a)) {
+			return fmt.Errorf("invalid label %q: must be non-empty without spaces, brackets or parentheses", a)
+		}
+		if seen[a] {
+			return fmt.Errorf("duplicate label %q", a)
+		}
+		seen[a] = true
+	}
+	if !seen[ls.Default] {
+		return fmt.Errorf("default label %q is not in the label list", ls.Default)
+	}
+	if len(ls.Decorations) == 0 || ls.Decorations[0] != DecorationNone {
+		return errors.New("decoration list must start with the empty decoration")
+	}
+	seenDeco := make(map[Decoration]bool)
+	for _, d := range ls.Decorations[1:] {
+		if !reviewLabelNameRe.MatchString(string(d)) {
+			return fmt.Errorf("invalid decoration %q: must be non-empty without spaces, brackets or parentheses", d)
+		}
+		if seenDeco[d] {
+			return fmt.Errorf("duplicate decoration %q", d)
+		}
+		seenDeco[d] = true
+	}
+	for _, a := range slices.Sorted(maps.Keys(ls.Colors)) {
+		if !seen[a] {
+			return fmt.Errorf("colour given for unknown label %q", a)
+		}
+		if !ValidColor(ls.Colors[a]) {
+			return fmt.Errorf("invalid colour %q for label %q: want an ANSI code (0-255) or #rrggbb", ls.Colors[a], a)
+		}
+	}
+	return nil
+}
+
+var hexColorRe = regexp.MustCompile(`^#(?:[0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// ValidColor reports whether c is an ANSI colour code or a hex colour.
+func ValidColor(c string) bool {
+	if hexColorRe.MatchString(c) {
+		return true
+	}
+	n, err := strconv.Atoi(c)
+	return err == nil && n >= 0 && n <= 255
+}
+
+// HasAction reports whether action is part of the label set.
+func (ls LabelSet) HasAction(action ActionType) bool {
+	return slices.Contains(ls.Actions, action)
+}
+
+// HasDecoration reports whether deco is part of the label set.
+func (ls LabelSet) HasDecoration(deco Decoration) bool {
+	return slices.Contains(ls.Decorations, deco)
+}
+
+// Color returns the configured display colour for action, or "" if none.
+func (ls LabelSet) Color(action ActionType) string {
+	return ls.Colors[action]
+}
@@ -0,0 +1,104 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLabelSetValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(ls *LabelSet)
+		wantErr string
+	}{
+		{
+			name:   "defaults",
+			modify: func(*LabelSet) {},
+		},
+		{
+			name: "custom labels",
+			modify: func(ls *LabelSet) {
+				ls.Actions = append(ls.Actions, "security", "perf")
+				ls.Decorations = append(ls.Decorations, "ux")
+				ls.Colors = map[ActionType]string{"security": "196"}
+			},
+		},
+		{
+			name:    "no actions",
+			modify:  func(ls *LabelSet) { ls.Actions = nil },
+			wantErr: "no actions",
+		},
+		{
+			name:    "label with space",
+			modify:  func(ls *LabelSet) { ls.Actions = append(ls.Actions, "needs work") },
+			wantErr: "invalid label",
+		},
+		{
+			name:    "duplicate label",
+			modify:  func(ls *LabelSet) { ls.Actions = append(ls.Actions, ActionNote) },
+			wantErr: "duplicate label",
+		},
+		{
+			name:    "unknown default",
+			modify:  func(ls *LabelSet) { ls.Default = "decision" },
+			wantErr: "default label",
+		},
+		{
+			name:    "missing empty decoration",
+			modify:  func(ls *LabelSet) { ls.Decorations = []Decoration{"ux"} },
+			wantErr: "empty decoration",
+		},
+		{
+			name:    "decoration with parenthesis",
+			modify:  func(ls *LabelSet) { ls.Decorations = append(ls.Decorations, "a(b)") },
+			wantErr: "invalid decoration",
+		},
+		{
+			name:    "invalid colour",
+			modify:  func(ls *LabelSet) { ls.Colors = map[ActionType]string{"issue": "red"} },
+			wantErr: `invalid colour "red" for label "issue"`,
+		},
+		{
+			name:    "colour for unknown label",
+			modify:  func(ls *LabelSet) { ls.Colors = map[ActionType]string{"perf": "33"} },
+			wantErr: "unknown label",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ls := DefaultLabels()
+			tt.modify(&ls)
+			err := ls.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Validate() error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseReviewCustomLabels(t *testing.T) {
+	defer func() { Labels = DefaultLabels() }()
+	ls := DefaultLabels()
+	ls.Actions = append(ls.Actions, "security")
+	ls.Decorations = append(ls.Decorations, "legal")
+	if err := SetLabels(ls); err != nil {
+		t.Fatalf("SetLabels() error: %v", err)
+	}
+
+	doc := &Document{Sections: []*Section{{ID: "S1", Title: "Step", Level: 2, StartLine: 1, EndLine: 2}}}
+	result, _, err := ParseReview([]byte("# Review\n\n## S1: Step\n[security (legal)] check licence\n"), doc)
+	if err != nil {
+		t.Fatalf("ParseReview() error: %v", err)
+	}
+	got := result.Comments[0]
+	if got.Action != "security" || got.Decoration != "legal" {
+		t.Errorf("label = %s, want security (legal)", got.FormatLabel())
+	}
+}
@@ -1,6 +1,9 @@
 package markdown
 
-import "fmt"
+import (
+	"fmt"
+	"slices"
+)
 
 // OverviewSectionID is the virtual section ID used for file-level comments on the overview/preamble.
 const OverviewSectionID = "overview"
@@ -25,6 +28,27 @@ type Section struct {
 	EndLine   int        // 1-based line number of last body line (0 = not set)
 }
 
+// HeadingPath returns the titles from the top-level ancestor down to this section.
+// Unlike ID, the path is stable when sections are inserted or removed elsewhere.
+func (s *Section) HeadingPath() []string {
+	var path []string
+	for cur := s; cur != nil; cur = cur.Parent {
+		path = append(path, cur.Title)
+	}
+	slices.Reverse(path)
+	return path
+}
+
+// FindSectionByPath returns the first section whose heading path equals path, or nil.
+func (d *Document) FindSectionByPath(path []string) *Section {
+	for _, s := range d.AllSections() {
+		if slices.Equal(s.HeadingPath(), path) {
+			return s
+		}
+	}
+	return nil
+}
+
 // AllSections returns a flat list of all sections in depth-first order.
 func (d *Document) AllSections() []*Section {
 	var result []*Section
@@ -49,15 +73,39 @@ func (d *Document) FindSection(id string) *Section {
 	return nil
 }
 
+// SectionIDAtLine returns the ID of the section containing the given 1-based line number.
+// Returns OverviewSectionID if the line is before any section.
+func (d *Document) SectionIDAtLine(line int) string {
+	result := OverviewSectionID
+	for _, s := range d.AllSections() {
+		if s.StartLine == 0 {
+			continue
+		}
+		if line < s.StartLine {
+			break
+		}
+		result = s.ID
+	}
+	return result
+}
+
 // ReviewComment is a review comment on a single section.
 type ReviewComment struct {
-	SectionID  string     // Target section ID
-	Action     ActionType // Comment action type
-	Decoration Decoration // Comment decoration (e.g. non-blocking, blocking)
-	Body       string     // Comment body text
-	StartLine  int        // 1-based start line (0 = section-level comment)
-	EndLine    int        // 1-based end line (0 = single line if StartLine > 0)
-	Side       string     // "RIGHT" or "LEFT" (for PR diff comments)
+	SectionID  string      // Target section ID
+	Action     ActionType  // Comment action type
+	Decoration Decoration  // Comment decoration (e.g. non-blocking, blocking)
+	Body       string      // Comment body text
+	StartLine  int         // 1-based start line (0 = section-level comment)
+	EndLine    int         // 1-based end line (0 = single line if StartLine > 0)
+	Side       string      // "RIGHT" or "LEFT" (for PR diff comments)
+	Anchor     *LineAnchor // fingerprint of the commented lines (nil = not anchored)
+	Orphaned   bool        // commented lines could not be found after the document changed
+	Suggestion *Suggestion // replacement for the commented lines (nil = plain comment)
+}
+
+// IsEmpty reports whether the comment has neither body text nor a suggestion.
+func (c *ReviewComment) IsEmpty() bool {
+	return c.Body == "" && c.Suggestion == nil
 }
 
 // FormatLabel returns the formatted label string for display.
@@ -107,7 +155,8 @@ const (
 	ActionChore      ActionType = "chore"
 )
 
-// ActionLabels is the ordered list of action labels for Tab cycling.
+// ActionLabels is the built-in ordered list of action labels for Tab cycling.
+// The active list is Labels.Actions, which may be replaced from config.
 var ActionLabels = []ActionType{
 	ActionSuggestion,
 	ActionIssue,
@@ -120,7 +169,7 @@ var ActionLabels = []ActionType{
 	ActionChore,
 }
 
-// DefaultAction is the default action type for new comments.
+// DefaultAction is the built-in default action type for new comments.
 const DefaultAction = ActionQuestion
 
 // Decoration is the decoration modifier for a Conventional Comment.
@@ -133,7 +182,7 @@ const (
 	DecorationIfMinor     Decoration = "if-minor"
 )
 
-// DecorationLabels is the ordered list of decoration labels for cycling.
+// DecorationLabels is the built-in ordered list of decoration labels for cycling.
 var DecorationLabels = []Decoration{
 	DecorationNone,
 	DecorationNonBlocking,
@@ -144,6 +193,8 @@ var DecorationLabels = []Decoration{
 // ReviewResult holds the entire review output.
 type ReviewResult struct {
 	Comments []ReviewComment
+	Verdicts map[string]Verdict // section ID -> verdict (sections without a verdict are absent)
+	Policy   *PolicyResult      // submit policy outcome (nil when no policy is enabled)
 }
 
 // Status is the exit status of a TUI review session.
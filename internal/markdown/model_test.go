@@ -82,3 +82,28 @@ func TestReviewCommentFormatLineRef(t *testing.T) {
 		})
 	}
 }
+
+func TestDocumentSectionIDAtLine(t *testing.T) {
+	doc, err := Parse([]byte("Intro\n\n## A\n\nBody\n\n### A1\n\nBody\n\n## B\n\nBody\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		line int
+		want string
+	}{
+		{1, OverviewSectionID},
+		{3, "S1"},
+		{5, "S1"},
+		{7, "S1.1"},
+		{10, "S1.1"},
+		{11, "S2"},
+		{99, "S2"},
+	}
+	for _, tt := range tests {
+		if got := doc.SectionIDAtLine(tt.line); got != tt.want {
+			t.Errorf("SectionIDAtLine(%d) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,248 @@
+package markdown
+
+import (
+	"cmp"
+	"math"
+	"slices"
+)
+
+// Review output orderings (FormatOptions.Order).
+const (
+	OrderAdded    = "added"    // section comments grouped by section, then line comments, in the order added
+	OrderDocument = "document" // one group per section in document order, line comments nested in their section
+	OrderLabel    = "label"    // one group per label, blocking comments first
+	OrderSeverity = "severity" // one group per decoration, blocking first
+)
+
+// Orders lists the valid FormatOptions.Order values.
+var Orders = []string{OrderAdded, OrderDocument, OrderLabel, OrderSeverity}
+
+// undecoratedHeading is the severity group heading for comments without a decoration.
+const undecoratedHeading = "undecorated"
+
+// CommentGroup is a group of comments under one heading of the review output.
+type CommentGroup struct {
+	Heading  string        // "S1: Title" or "Overview" (document order), a label, or a decoration
+	Section  *Section      // section of the group (document order only)
+	Quote    string        // excerpt of Section (document order with FormatOptions.Quote)
+	Comments []LineComment // comments in position order
+}
+
+// groupComments arranges comments for the document, label and severity orders.
+// In document order, section comments carry no Ref; in the other orders their
+// Ref names the section ("S1: Title" or "Overview") and their Quote is the
+// section excerpt.
+func groupComments(comments []ReviewComment, d *Document, opts FormatOptions) []CommentGroup {
+	entries := make([]LineComment, len(comments))
+	for i, c := range comments {
+		entries[i] = newCommentEntry(c, d, opts)
+	}
+	positions := commentPositions(d)
+	byPosition := func(a, b LineComment) int {
+		return cmp.Compare(positions.of(a.ReviewComment), positions.of(b.ReviewComment))
+	}
+
+	switch opts.Order {
+	case OrderDocument:
+		for i := range entries {
+			if entries[i].StartLine == 0 {
+				continue
+			}
+			// Nest line comments under the section that contains them.
+			entries[i].SectionID = d.SectionIDAtLine(entries[i].StartLine)
+		}
+		keys := sectionKeys(entries, positions)
+		return buildGroups(entries, keys, func(c LineComment) string { return c.SectionID }, byPosition,
+			func(id string) CommentGroup {
+				g := newSectionComments(d, id)
+				cg := CommentGroup{Heading: g.Heading, Section: g.Section}
+				if opts.Quote {
+					cg.Quote = sectionExcerpt(d, id)
+				}
+				return cg
+			})
+
+	case OrderLabel:
+		for i := range entries {
+			entries[i] = withSectionRef(entries[i], d, opts)
+		}
+		keys := labelKeys(comments)
+		blockingFirst := func(a, b LineComment) int {
+			return cmp.Or(
+				cmp.Compare(severityRank(a.Decoration), severityRank(b.Decoration)),
+				byPosition(a, b),
+			)
+		}
+		return buildGroups(entries, keys, func(c LineComment) string { return string(c.Action) }, blockingFirst,
+			func(key string) CommentGroup { return CommentGroup{Heading: key} })
+
+	case OrderSeverity:
+		for i := range entries {
+			entries[i] = withSectionRef(entries[i], d, opts)
+		}
+		keys := decorationKeys(comments)
+		labelRank := func(a ActionType) int {
+			if i := slices.Index(Labels.Actions, a); i >= 0 {
+				return i
+			}
+			return len(Labels.Actions)
+		}
+		byLabel := func(a, b LineComment) int {
+			return cmp.Or(cmp.Compare(labelRank(a.Action), labelRank(b.Action)), byPosition(a, b))
+		}
+		return buildGroups(entries, keys, func(c LineComment) string { return severityHeading(c.Decoration) }, byLabel,
+			func(key string) CommentGroup { return CommentGroup{Heading: key} })
+	}
+	return nil
+}
+
+// buildGroups puts each entry into the group named by keyOf, in the order of
+// keys, and sorts the entries of each group with compare (stable).
+func buildGroups(entries []LineComment, keys []string, keyOf func(LineComment) string,
+	compare func(a, b LineComment) int, newGroup func(key string) CommentGroup) []CommentGroup {
+	groups := make([]CommentGroup, 0, len(keys))
+	for _, key := range keys {
+		g := newGroup(key)
+		for _, e := range entries {
+			if keyOf(e) == key {
+				g.Comments = append(g.Comments, e)
+			}
+		}
+		slices.SortStableFunc(g.Comments, compare)
+		groups = append(groups, g)
+	}
+	return groups
+}
+
+// newCommentEntry wraps c for the review output. Line comments get their
+// reference, source lines, suggestion diff and (with opts.Quote) their quote.
+func newCommentEntry(c ReviewComment, d *Document, opts FormatOptions) LineComment {
+	e := LineComment{ReviewComment: c}
+	if c.StartLine > 0 {
+		e.Ref = c.FormatLineRef()
+		e.Source = sourceLines(d, c.StartLine, c.EndLine)
+		if c.Suggestion != nil {
+			e.Diff = c.Suggestion.Diff(c.StartLine)
+		}
+		if opts.Quote {
+			e.Quote = lineQuote(d, c.StartLine, c.EndLine)
+		}
+	}
+	return e
+}
+
+// withSectionRef gives a section comment a Ref naming its section, for
+// groupings whose headings do not identify the section.
+func withSectionRef(e LineComment, d *Document, opts FormatOptions) LineComment {
+	if e.StartLine > 0 {
+		return e
+	}
+	e.Ref = newSectionComments(d, e.SectionID).Heading
+	if opts.Quote {
+		e.Quote = sectionExcerpt(d, e.SectionID)
+	}
+	return e
+}
+
+// positionIndex maps sections to the line used to order their comments.
+type positionIndex map[string]int
+
+func commentPositions(d *Document) positionIndex {
+	p := positionIndex{OverviewSectionID: 0}
+	for _, s := range d.AllSections() {
+		p[s.ID] = s.StartLine
+	}
+	return p
+}
+
+// of returns the document position of c: its start line, or the heading line
+// of its section. Comments on unknown sections sort last.
+func (p positionIndex) of(c ReviewComment) int {
+	if c.StartLine > 0 {
+		return c.StartLine
+	}
+	if line, ok := p[c.SectionID]; ok {
+		return line
+	}
+	return math.MaxInt
+}
+
+// sectionKeys returns the section IDs of entries in document order.
+func sectionKeys(entries []LineComment, positions positionIndex) []string {
+	var keys []string
+	for _, e := range entries {
+		if !slices.Contains(keys, e.SectionID) {
+			keys = append(keys, e.SectionID)
+		}
+	}
+	slices.SortStableFunc(keys, func(a, b string) int {
+		return cmp.Compare(positions.of(ReviewComment{SectionID: a}), positions.of(ReviewComment{SectionID: b}))
+	})
+	return keys
+}
+
+// labelKeys returns the labels used by comments in label set order. Labels
+// outside the active set follow in the order they first appear.
+func labelKeys(comments []ReviewComment) []string {
+	var keys []string
+	for _, a := range Labels.Actions {
+		if slices.ContainsFunc(comments, func(c ReviewComment) bool { return c.Action == a }) {
+			keys = append(keys, string(a))
+		}
+	}
+	for _, c := range comments {
+		if !slices.Contains(keys, string(c.Action)) {
+			keys = append(keys, string(c.Action))
+		}
+	}
+	return keys
+}
+
+// decorationKeys returns the severity headings used by comments, most severe first.
+func decorationKeys(comments []ReviewComment) []string {
+	decos := slices.Clone(Labels.Decorations)
+	for _, c := range comments {
+		if !slices.Contains(decos, c.Decoration) {
+			decos = append(decos, c.Decoration)
+		}
+	}
+	slices.SortStableFunc(decos, func(a, b Decoration) int {
+		return cmp.Compare(severityRank(a), severityRank(b))
+	})
+	var keys []string
+	for _, d := range decos {
+		if slices.ContainsFunc(comments, func(c ReviewComment) bool { return c.Decoration == d }) {
+			keys = append(keys, severityHeading(d))
+		}
+	}
+	return keys
+}
+
+// severityRank orders decorations for output: blocking first, then
+// undecorated, then the rest.
+func severityRank(d Decoration) int {
+	switch d {
+	case DecorationBlocking:
+		return 0
+	case DecorationNone:
+		return 1
+	default:
+		return 2
+	}
+}
+
+func severityHeading(d Decoration) string {
+	if d == DecorationNone {
+		return undecoratedHeading
+	}
+	return string(d)
+}
+
+// isGroupHeading reports whether title is a heading written by the label or
+// severity order.
+func isGroupHeading(title string) bool {
+	if title == "" {
+		return false
+	}
+	return title == undecoratedHeading || Labels.HasAction(ActionType(title)) || Labels.HasDecoration(Decoration(title))
+}
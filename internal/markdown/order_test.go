@@ -0,0 +1,102 @@
+package markdown
+
+import (
+	"slices"
+	"testing"
+)
+
+func orderTestReview(t *testing.T) (*Document, *ReviewResult) {
+	t.Helper()
+	source := "# Plan\n\nIntro.\n\n## Setup\n\nInstall deps.\n\n## Deploy\n\nRun deploy.\nVerify.\n"
+	doc, err := Parse([]byte(source))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return doc, &ReviewResult{Comments: []ReviewComment{
+		{SectionID: "S2", Action: ActionQuestion, Body: "Which env?"},
+		{SectionID: "S2", Action: ActionIssue, Decoration: DecorationBlocking, Body: "No rollback.", StartLine: 12},
+		{SectionID: "S1", Action: ActionNitpick, Body: "Typo.", StartLine: 7},
+		{SectionID: OverviewSectionID, Action: ActionIssue, Body: "Too vague."},
+		{SectionID: "S2", Action: ActionIssue, Decoration: DecorationNonBlocking, Body: "Slow.", StartLine: 11},
+	}}
+}
+
+func TestFormatReviewDocumentOrder(t *testing.T) {
+	doc, result := orderTestReview(t)
+	got := FormatReviewWith(result, doc, "plan.md", FormatOptions{Order: OrderDocument})
+	want := "# Review\n\n" +
+		"Please review and address the following comments on: plan.md\n" +
+		"\n## Overview\n" +
+		"[issue] Too vague.\n" +
+		"\n## S1: Setup\n" +
+		"\n`L7` [nitpick] Typo.\n" +
+		"\n## S2: Deploy\n" +
+		"[question] Which env?\n" +
+		"\n`L11` [issue (non-blocking)] Slow.\n" +
+		"\n`L12` [issue (blocking)] No rollback.\n"
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestFormatReviewLabelOrder(t *testing.T) {
+	doc, result := orderTestReview(t)
+	got := FormatReviewWith(result, doc, "plan.md", FormatOptions{Order: OrderLabel})
+	want := "# Review\n\n" +
+		"Please review and address the following comments on: plan.md\n" +
+		"\n## issue\n" +
+		"\n`L12` [issue (blocking)] No rollback.\n" +
+		"\n`Overview` [issue] Too vague.\n" +
+		"\n`L11` [issue (non-blocking)] Slow.\n" +
+		"\n## question\n" +
+		"\n`S2: Deploy` [question] Which env?\n" +
+		"\n## nitpick\n" +
+		"\n`L7` [nitpick] Typo.\n"
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestFormatReviewSeverityOrder(t *testing.T) {
+	doc, result := orderTestReview(t)
+	data := NewReviewData(result, doc, "plan.md", FormatOptions{Order: OrderSeverity})
+	var headings []string
+	for _, g := range data.Groups {
+		headings = append(headings, g.Heading)
+	}
+	if want := []string{"blocking", undecoratedHeading, "non-blocking"}; !slices.Equal(headings, want) {
+		t.Fatalf("headings = %v, want %v", headings, want)
+	}
+	var bodies []string
+	for _, c := range data.Groups[1].Comments {
+		bodies = append(bodies, c.Body)
+	}
+	// Within a severity, label order then document position.
+	if want := []string{"Too vague.", "Which env?", "Typo."}; !slices.Equal(bodies, want) {
+		t.Errorf("undecorated bodies = %v, want %v", bodies, want)
+	}
+}
+
+func TestParseReviewOrders(t *testing.T) {
+	doc, result := orderTestReview(t)
+	for _, order := range Orders {
+		for _, quote := range []bool{false, true} {
+			out := FormatReviewWith(result, doc, "plan.md", FormatOptions{Order: order, Quote: quote})
+			parsed, orphans, err := ParseReview([]byte(out), doc)
+			if err != nil {
+				t.Fatalf("order %s: ParseReview() error: %v\n%s", order, err, out)
+			}
+			if len(orphans) != 0 || len(parsed.Comments) != len(result.Comments) {
+				t.Fatalf("order %s: got %+v, orphans %+v\n%s", order, parsed.Comments, orphans, out)
+			}
+			for _, want := range result.Comments {
+				if !slices.ContainsFunc(parsed.Comments, func(c ReviewComment) bool {
+					return c.SectionID == want.SectionID && c.Body == want.Body && c.StartLine == want.StartLine &&
+						c.Action == want.Action && c.Decoration == want.Decoration
+				}) {
+					t.Errorf("order %s quote %v: comment %+v not read back from\n%s", order, quote, want, out)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package markdown
+
+import "fmt"
+
+// Names of the Policy checks, as reported in PolicyCheck.Name.
+const (
+	PolicyRequireViewed = "require-viewed"
+	PolicyNoBlocking    = "require-no-blocking"
+	PolicyMinViewed     = "min-viewed"
+)
+
+// Policy is a set of opt-in checks a review must pass before it can be
+// submitted. The zero value enforces nothing.
+type Policy struct {
+	RequireViewed bool // every section must be viewed before approving
+	NoBlocking    bool // approval is refused while any blocking comment exists
+	MinViewed     int  // percentage of sections that must be viewed before submitting (0 = off)
+}
+
+// Enabled reports whether p enforces any check.
+func (p Policy) Enabled() bool {
+	return p.RequireViewed || p.NoBlocking || p.MinViewed > 0
+}
+
+// Validate reports settings that cannot be enforced.
+func (p Policy) Validate() error {
+	if p.MinViewed < 0 || p.MinViewed > 100 {
+		return fmt.Errorf("min-viewed must be a percentage between 0 and 100, got %d", p.MinViewed)
+	}
+	return nil
+}
+
+// PolicyCheck is the outcome of one Policy check.
+type PolicyCheck struct {
+	Name    string `json:"name"`
+	Passed  bool   `json:"passed"`
+	Message string `json:"message,omitempty"` // why the check failed
+}
+
+// PolicyResult is the outcome of Policy.Check.
+type PolicyResult struct {
+	Viewed   int           `json:"viewed"`
+	Sections int           `json:"sections"`
+	Checks   []PolicyCheck `json:"checks"`
+}
+
+// Passed reports whether every check passed.
+func (r *PolicyResult) Passed() bool {
+	return len(r.Failures()) == 0
+}
+
+// Failures returns the messages of the failed checks.
+func (r *PolicyResult) Failures() []string {
+	var msgs []string
+	for _, c := range r.Checks {
+		if !c.Passed {
+			msgs = append(msgs, c.Message)
+		}
+	}
+	return msgs
+}
+
+// Check evaluates p for a submission with the given comments, of which viewed
+// out of sections were marked viewed. approving reports whether the submission
+// approves the document; the approval checks pass when it does not.
+func (p Policy) Check(comments []ReviewComment, viewed, sections int, approving bool) *PolicyResult {
+	r := &PolicyResult{Viewed: viewed, Sections: sections}
+	if p.RequireViewed {
+		c := PolicyCheck{Name: PolicyRequireViewed, Passed: !approving || viewed >= sections}
+		if !c.Passed {
+			c.Message = fmt.Sprintf("approval requires every section to be viewed (%d/%d viewed)", viewed, sections)
+		}
+		r.Checks = append(r.Checks, c)
+	}
+	if p.NoBlocking {
+		blocking := 0
+		for _, cm := range comments {
+			if cm.Decoration == DecorationBlocking {
+				blocking++
+			}
+		}
+		c := PolicyCheck{Name: PolicyNoBlocking, Passed: !approving || blocking == 0}
+		if !c.Passed {
+			c.Message = fmt.Sprintf("approval is refused while %d blocking comment(s) remain", blocking)
+		}
+		r.Checks = append(r.Checks, c)
+	}
+	if p.MinViewed > 0 {
+		c := PolicyCheck{Name: PolicyMinViewed, Passed: viewed*100 >= p.MinViewed*sections}
+		if !c.Passed {
+			c.Message = fmt.Sprintf("at least %d%% of sections must be viewed (%d/%d viewed)", p.MinViewed, viewed, sections)
+		}
+		r.Checks = append(r.Checks, c)
+	}
+	return r
+}
+
+// Approves reports whether the review approves the document: it has no
+// comments and no verdicts, or every verdict given is approve.
+func (r *ReviewResult) Approves() bool {
+	approved := false
+	for _, v := range r.Verdicts {
+		switch v {
+		case VerdictNone:
+		case VerdictApprove:
+			approved = true
+		default:
+			return false
+		}
+	}
+	return approved || r.IsEmpty()
+}
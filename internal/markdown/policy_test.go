@@ -0,0 +1,87 @@
+package markdown
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestPolicyCheck(t *testing.T) {
+	blocking := []ReviewComment{{SectionID: "S1", Action: ActionIssue, Decoration: DecorationBlocking, Body: "x"}}
+	tests := []struct {
+		name      string
+		policy    Policy
+		comments  []ReviewComment
+		viewed    int
+		approving bool
+		want      []string // failed check names
+	}{
+		{name: "no policy", policy: Policy{}, viewed: 0},
+		{name: "approval with unviewed sections", policy: Policy{RequireViewed: true}, viewed: 2, approving: true, want: []string{PolicyRequireViewed}},
+		{name: "approval with every section viewed", policy: Policy{RequireViewed: true}, viewed: 4, approving: true},
+		{name: "require viewed ignores comment reviews", policy: Policy{RequireViewed: true}, viewed: 0},
+		{name: "approval with blocking comment", policy: Policy{NoBlocking: true}, comments: blocking, approving: true, want: []string{PolicyNoBlocking}},
+		{name: "blocking comment without approval", policy: Policy{NoBlocking: true}, comments: blocking},
+		{name: "below threshold", policy: Policy{MinViewed: 75}, viewed: 2, want: []string{PolicyMinViewed}},
+		{name: "threshold met", policy: Policy{MinViewed: 75}, viewed: 3},
+		{
+			name:      "every check fails",
+			policy:    Policy{RequireViewed: true, NoBlocking: true, MinViewed: 100},
+			comments:  blocking,
+			viewed:    1,
+			approving: true,
+			want:      []string{PolicyRequireViewed, PolicyNoBlocking, PolicyMinViewed},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := tt.policy.Check(tt.comments, tt.viewed, 4, tt.approving)
+			var failed []string
+			for _, c := range r.Checks {
+				if !c.Passed {
+					failed = append(failed, c.Name)
+					if c.Message == "" {
+						t.Errorf("check %s failed without a message", c.Name)
+					}
+				}
+			}
+			if !slices.Equal(failed, tt.want) {
+				t.Errorf("failed checks = %v, want %v", failed, tt.want)
+			}
+			if r.Passed() != (len(tt.want) == 0) {
+				t.Errorf("Passed() = %v", r.Passed())
+			}
+		})
+	}
+}
+
+func TestPolicyValidate(t *testing.T) {
+	for _, v := range []int{-1, 101} {
+		if err := (Policy{MinViewed: v}).Validate(); err == nil {
+			t.Errorf("MinViewed %d: expected error", v)
+		}
+	}
+	if err := (Policy{MinViewed: 100}).Validate(); err != nil {
+		t.Errorf("MinViewed 100: %v", err)
+	}
+}
+
+func TestReviewResultApproves(t *testing.T) {
+	comment := []ReviewComment{{SectionID: "S1", Action: ActionNote, Body: "x"}}
+	tests := []struct {
+		name   string
+		result ReviewResult
+		want   bool
+	}{
+		{"empty", ReviewResult{}, true},
+		{"comments only", ReviewResult{Comments: comment}, false},
+		{"approve verdicts with comments", ReviewResult{Comments: comment, Verdicts: map[string]Verdict{"S1": VerdictApprove}}, true},
+		{"mixed verdicts", ReviewResult{Verdicts: map[string]Verdict{"S1": VerdictApprove, "S2": VerdictReject}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.result.Approves(); got != tt.want {
+				t.Errorf("Approves() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
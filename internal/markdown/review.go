@@ -1,76 +1,276 @@
 package markdown
 
 import (
+	"cmp"
 	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 )
 
-// FormatReview formats a ReviewResult as a Markdown string.
+// FormatReview formats a ReviewResult as a Markdown string using the
+// built-in template (see templates/default.tmpl) and default options.
 // Section-level comments are grouped under section headings.
 // Line-level comments are listed separately by line number.
 func FormatReview(result *ReviewResult, d *Document, filePath string) string {
-	if len(result.Comments) == 0 {
-		return ""
-	}
+	return FormatReviewWith(result, d, filePath, FormatOptions{})
+}
 
-	target := filePath
-	if target == "" {
-		target = "the file"
-	}
+// FormatReviewWith is FormatReview with options.
+func FormatReviewWith(result *ReviewResult, d *Document, filePath string, opts FormatOptions) string {
+	// The built-in template only uses fields that always exist, so it cannot fail.
+	out, _ := RenderReview(nil, result, d, filePath, opts)
+	return out
+}
+
+var (
+	reviewSectionHeadingRe = regexp.MustCompile(`^## (S\d+(?:\.\d+)*)(?:: (.*))?$`)
+	reviewLabelRe          = regexp.MustCompile(`^\[([^\]\s()]+)(?: \(([^)\s]+)\))?\] ?(.*)$`)
+	reviewLineRefRe        = regexp.MustCompile("^`L(\\d+)(?:-L(\\d+))?` (.*)$")
+	reviewSectionRefRe     = regexp.MustCompile("^`(Overview|S\\d+(?:\\.\\d+)*)(?:: ([^`]*))?` (.*)$")
+	reviewLabelNameRe      = regexp.MustCompile(`^[^\[\]\s()]+$`)
+	reviewVerdictRe        = regexp.MustCompile("^- `(S\\d+(?:\\.\\d+)*)(?:: ([^`]*))?` (.+)$")
+)
 
-	// Separate section-level and line-level comments.
-	type group struct {
-		title    string
-		comments []ReviewComment
+// bodyIndent is the hanging indent of comment body continuation lines (see
+// hangLines). Reviews written before it was added have unindented bodies.
+const bodyIndent = "  "
+
+// parsedComment is a comment read back from review Markdown, before it is
+// matched against the current document.
+type parsedComment struct {
+	comment  ReviewComment
+	orphaned bool // section heading no longer matches any section
+}
+
+// ParseReview parses Markdown produced by FormatReview and maps its comments onto doc.
+// Line comments with a source quote (FormatOptions.Quote) are moved to where
+// the quoted lines are now, or flagged as orphaned if they are gone; suggestion diffs
+// are read back into ReviewComment.Suggestion, the "## Verdicts" list into
+// ReviewResult.Verdicts, and all
+// FormatOptions.Order layouts are accepted.
+// Section comments are matched by section ID when the title still agrees, otherwise by
+// title alone. Line comments are assigned to the section containing their start line.
+// Comments whose target no longer exists in doc are returned as orphans.
+func ParseReview(source []byte, doc *Document) (result *ReviewResult, orphans []ReviewComment, err error) {
+	lines := strings.Split(strings.ReplaceAll(string(source), "\r\n", "\n"), "\n")
+
+	start := slices.IndexFunc(lines, func(l string) bool { return strings.TrimSpace(l) == "# Review" })
+	if start < 0 {
+		return nil, nil, fmt.Errorf("not a commd review: missing %q heading", "# Review")
 	}
-	var sectionOrder []string
-	sectionGroups := make(map[string]*group)
-	var lineComments []ReviewComment
 
-	for _, c := range result.Comments {
-		if c.StartLine > 0 {
-			lineComments = append(lineComments, c)
+	var (
+		parsed        []*parsedComment
+		current       *parsedComment // comment currently receiving body lines
+		sectionID     string         // target of the current "## " group ("" before any group)
+		sectionOrphan bool
+		inLines       bool // true after the "---" divider
+		inDiff        bool // inside a suggestion's ```diff block
+		inVerdicts    bool // inside the "## Verdicts" list
+		verdicts      = make(map[string]Verdict)
+	)
+
+	for i := start + 1; i < len(lines); i++ {
+		line := lines[i]
+
+		// Diff lines such as "---" (a removed "--") are body text, not structure.
+		if current != nil && (inDiff || line == "```diff") {
+			inDiff = line != "```" || !inDiff
+			current.comment.Body += "\n" + line
 			continue
 		}
-		g, ok := sectionGroups[c.SectionID]
-		if !ok {
-			var title string
-			if c.SectionID == OverviewSectionID {
-				title = "Overview"
-			} else {
-				section := d.FindSection(c.SectionID)
-				title = c.SectionID
-				if section != nil {
-					title = fmt.Sprintf("%s: %s", c.SectionID, section.Title)
+
+		if inVerdicts {
+			if m := reviewVerdictRe.FindStringSubmatch(line); m != nil {
+				v, ok := parseVerdictLabel(m[3])
+				if !ok {
+					return nil, nil, fmt.Errorf("line %d: unknown verdict %q", i+1, m[3])
 				}
+				// Verdicts on sections that no longer exist are dropped.
+				if id := resolveSectionID(doc, m[1], m[2]); id != "" {
+					verdicts[id] = v
+				}
+				continue
+			}
+			inVerdicts = strings.TrimSpace(line) == ""
+			if inVerdicts {
+				continue
 			}
-			g = &group{title: title}
-			sectionGroups[c.SectionID] = g
-			sectionOrder = append(sectionOrder, c.SectionID)
 		}
-		g.comments = append(g.comments, c)
-	}
 
-	var sb strings.Builder
-	sb.WriteString("# Review\n\n")
-	fmt.Fprintf(&sb, "Please review and address the following comments on: %s\n", target)
+		if !inLines {
+			switch {
+			case line == "## Verdicts" && len(parsed) == 0:
+				current = nil
+				inVerdicts = true
+				continue
+			case line == "---" && isDivider(lines, i):
+				current = nil
+				inLines = true
+				continue
+			case line == "## Overview":
+				current = nil
+				sectionID, sectionOrphan = OverviewSectionID, false
+				continue
+			case strings.HasPrefix(line, "## "):
+				current = nil
+				m := reviewSectionHeadingRe.FindStringSubmatch(line)
+				if m == nil {
+					if !isGroupHeading(strings.TrimPrefix(line, "## ")) {
+						return nil, nil, fmt.Errorf("line %d: unrecognized section heading %q", i+1, line)
+					}
+					// Label or severity group: only comments with a reference follow.
+					sectionID, sectionOrphan = "", false
+					continue
+				}
+				sectionID = resolveSectionID(doc, m[1], m[2])
+				sectionOrphan = sectionID == ""
+				if sectionOrphan {
+					sectionID = m[1] // keep the original ID so the orphan can be reported
+				}
+				continue
+			}
+		}
+
+		var (
+			m          []string
+			lineRef    []string
+			sectionRef []string
+		)
+		switch {
+		case reviewLineRefRe.MatchString(line):
+			lineRef = reviewLineRefRe.FindStringSubmatch(line)
+			m = reviewLabelRe.FindStringSubmatch(lineRef[3])
+		case reviewSectionRefRe.MatchString(line):
+			sectionRef = reviewSectionRefRe.FindStringSubmatch(line)
+			m = reviewLabelRe.FindStringSubmatch(sectionRef[3])
+		case !inLines && sectionID != "":
+			m = reviewLabelRe.FindStringSubmatch(line)
+			// In unindented bodies, a line such as "[docs](url) say so" is
+			// body text rather than a comment with an unknown label.
+			if m != nil && current != nil && !isKnownLabel(m[1], m[2]) {
+				m = nil
+			}
+		}
+
+		if m == nil {
+			if current != nil {
+				current.comment.Body += "\n" + strings.TrimPrefix(line, bodyIndent)
+			}
+			continue
+		}
+
+		action, deco, err := parseReviewLabel(m[1], m[2])
+		if err != nil {
+			return nil, nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		current = &parsedComment{
+			comment: ReviewComment{
+				SectionID:  sectionID,
+				Action:     action,
+				Decoration: deco,
+				Body:       m[3],
+			},
+			orphaned: !inLines && sectionOrphan,
+		}
+		if sectionRef != nil {
+			id := OverviewSectionID
+			if sectionRef[1] != "Overview" {
+				id = resolveSectionID(doc, sectionRef[1], sectionRef[2])
+			}
+			current.comment.SectionID = cmp.Or(id, sectionRef[1])
+			current.orphaned = id == ""
+		}
+		if lineRef != nil {
+			current.comment.StartLine, _ = strconv.Atoi(lineRef[1])
+			if lineRef[2] != "" {
+				current.comment.EndLine, _ = strconv.Atoi(lineRef[2])
+			}
+			current.comment.SectionID = doc.SectionIDAtLine(current.comment.StartLine)
+			current.orphaned = max(current.comment.StartLine, current.comment.EndLine) > len(doc.SourceLines)
+		}
+		parsed = append(parsed, current)
+	}
 
-	// Section-level comments grouped by section
-	for _, id := range sectionOrder {
-		g := sectionGroups[id]
-		fmt.Fprintf(&sb, "\n## %s\n", g.title)
-		for _, c := range g.comments {
-			fmt.Fprintf(&sb, "[%s] %s\n", c.FormatLabel(), c.Body)
+	result = &ReviewResult{}
+	if len(verdicts) > 0 {
+		result.Verdicts = verdicts
+	}
+	for _, p := range parsed {
+		body, quote := splitSourceQuote(p.comment.Body)
+		p.comment.Body = strings.TrimSpace(body)
+		if p.comment.StartLine > 0 {
+			p.comment.Body, p.comment.Suggestion = splitSuggestionDiff(p.comment.Body)
+			if len(quote) > 0 {
+				relocateQuoted(&p.comment, quote, doc)
+				p.orphaned = false
+			}
+		}
+		if p.orphaned {
+			orphans = append(orphans, p.comment)
+		} else {
+			result.Comments = append(result.Comments, p.comment)
 		}
 	}
+	return result, orphans, nil
+}
 
-	// Line-level comments with inline code decoration, separated by a divider
-	if len(lineComments) > 0 {
-		sb.WriteString("\n---\n")
-		for _, c := range lineComments {
-			fmt.Fprintf(&sb, "\n`%s` [%s] %s\n", c.FormatLineRef(), c.FormatLabel(), c.Body)
+// isDivider reports whether lines[i], a "---" line, is the divider before the
+// line comments: it is surrounded by blank lines, unlike a "---" in a body.
+func isDivider(lines []string, i int) bool {
+	return strings.TrimSpace(lines[i-1]) == "" && (i+1 == len(lines) || strings.TrimSpace(lines[i+1]) == "")
+}
+
+// relocateQuoted moves a line comment to where the source lines quoted under
+// it are now, or flags it as orphaned if they are gone.
+func relocateQuoted(c *ReviewComment, quote []quotedLine, doc *Document) {
+	offset, ok := locateQuote(quote, doc.SourceLines)
+	if !ok {
+		c.Orphaned = true
+		return
+	}
+	c.StartLine += offset
+	if c.EndLine > 0 {
+		c.EndLine += offset
+	}
+	c.SectionID = doc.SectionIDAtLine(c.StartLine)
+}
+
+// resolveSectionID finds the section a review heading refers to.
+// The ID is trusted when its title still matches (or no title was written);
+// otherwise a section with the same title is used. Returns "" if none matches.
+func resolveSectionID(doc *Document, id, title string) string {
+	if s := doc.FindSection(id); s != nil && (title == "" || s.Title == title) {
+		return s.ID
+	}
+	if title == "" {
+		return ""
+	}
+	for _, s := range doc.AllSections() {
+		if s.Title == title {
+			return s.ID
 		}
 	}
+	return ""
+}
+
+// isKnownLabel reports whether label and deco belong to the active label set.
+func isKnownLabel(label, deco string) bool {
+	return Labels.HasAction(ActionType(label)) && Labels.HasDecoration(Decoration(deco))
+}
 
-	return sb.String()
+// parseReviewLabel validates the label and decoration of a "[label (decoration)]" prefix
+// against the active label set.
+func parseReviewLabel(label, deco string) (ActionType, Decoration, error) {
+	action := ActionType(label)
+	if !Labels.HasAction(action) {
+		return "", "", fmt.Errorf("unknown comment label %q", label)
+	}
+	decoration := Decoration(deco)
+	if !Labels.HasDecoration(decoration) {
+		return "", "", fmt.Errorf("unknown comment decoration %q", deco)
+	}
+	return action, decoration, nil
 }
@@ -1,6 +1,7 @@
 package markdown
 
 import (
+	"slices"
 	"strings"
 	"testing"
 )
@@ -243,3 +244,179 @@ func TestFormatReview(t *testing.T) {
 		})
 	}
 }
+
+func TestParseReviewRoundTrip(t *testing.T) {
+	source := []byte("# Plan\n\nIntro.\n\n## Step One\n\nBody 1.\n\n## Step Two\n\nBody 2.\nMore.\n")
+	doc, err := Parse(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &ReviewResult{
+		Comments: []ReviewComment{
+			{SectionID: OverviewSectionID, Action: ActionNote, Body: "Overall looks good."},
+			{SectionID: "S1", Action: ActionSuggestion, Decoration: DecorationNonBlocking, Body: "First line.\nSecond line."},
+			{SectionID: "S2", Action: ActionIssue, Decoration: DecorationBlocking, Body: "Not needed."},
+			{SectionID: "S1", Action: ActionQuestion, Body: "Is this used?", StartLine: 7},
+			{SectionID: "S2", Action: ActionNitpick, Body: "Merge these.", StartLine: 11, EndLine: 12},
+		},
+	}
+
+	output := FormatReview(want, doc, "plan.md")
+	got, orphans, err := ParseReview([]byte(output), doc)
+	if err != nil {
+		t.Fatalf("ParseReview() error: %v", err)
+	}
+	if len(orphans) != 0 {
+		t.Errorf("orphans = %v, want none", orphans)
+	}
+	if len(got.Comments) != len(want.Comments) {
+		t.Fatalf("got %d comments, want %d:\n%s", len(got.Comments), len(want.Comments), output)
+	}
+	for i := range want.Comments {
+		if got.Comments[i] != want.Comments[i] {
+			t.Errorf("comment[%d] = %+v, want %+v", i, got.Comments[i], want.Comments[i])
+		}
+	}
+}
+
+func TestParseReviewRoundTripBodies(t *testing.T) {
+	source := []byte("# Plan\n\nIntro.\n\n## Step One\n\nBody 1.\n\n## Step Two\n\nBody 2.\n")
+	doc, err := Parse(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, body := range []string{
+		"see below\n[docs](http://x) say so",
+		"a\n---\nb",
+		"a\n\n---\n\nb",
+		"see\n[issue] not a new comment",
+		"see\n`L3` [issue] not a line comment\n## Not a heading",
+		"list:\n  - nested\n\n```\ncode\n```",
+	} {
+		t.Run(body, func(t *testing.T) {
+			want := &ReviewResult{Comments: []ReviewComment{
+				{SectionID: "S1", Action: ActionNote, Body: body},
+				{SectionID: "S2", Action: ActionIssue, Body: "after"},
+				{SectionID: "S1", Action: ActionQuestion, Body: body, StartLine: 7},
+			}}
+			output := FormatReview(want, doc, "plan.md")
+			got, orphans, err := ParseReview([]byte(output), doc)
+			if err != nil {
+				t.Fatalf("ParseReview() error: %v\n%s", err, output)
+			}
+			if len(orphans) != 0 || !slices.Equal(got.Comments, want.Comments) {
+				t.Errorf("ParseReview() = %+v, orphans %+v; want %+v\n%s", got.Comments, orphans, want.Comments, output)
+			}
+		})
+	}
+}
+
+func TestParseReviewUnindentedBodies(t *testing.T) {
+	doc, err := Parse([]byte("# Plan\n\n## Step One\n\nBody 1.\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Reviews written before body lines were indented.
+	review := "# Review\n\n## S1: Step One\n[note] see below\n[docs](http://x) say so\n[issue] a\n---\nb\n"
+	got, _, err := ParseReview([]byte(review), doc)
+	if err != nil {
+		t.Fatalf("ParseReview() error: %v", err)
+	}
+	want := []ReviewComment{
+		{SectionID: "S1", Action: ActionNote, Body: "see below\n[docs](http://x) say so"},
+		{SectionID: "S1", Action: ActionIssue, Body: "a\n---\nb"},
+	}
+	if !slices.Equal(got.Comments, want) {
+		t.Errorf("ParseReview() = %+v, want %+v", got.Comments, want)
+	}
+}
+
+func TestParseReviewOrphans(t *testing.T) {
+	doc, err := Parse([]byte("## Renamed\n\nBody.\n\n## Kept\n\nBody.\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	review := "# Review\n\n" +
+		"Please review and address the following comments on: plan.md\n\n" +
+		"## S1: Removed step\n" +
+		"[issue] Gone.\n\n" +
+		"## S9: Kept\n" +
+		"[note] Moved to S2.\n\n" +
+		"---\n\n" +
+		"`L3` [question] Still here?\n\n" +
+		"`L40-L42` [todo] Past the end.\n"
+
+	got, orphans, err := ParseReview([]byte(review), doc)
+	if err != nil {
+		t.Fatalf("ParseReview() error: %v", err)
+	}
+
+	wantComments := []ReviewComment{
+		{SectionID: "S2", Action: ActionNote, Body: "Moved to S2."},
+		{SectionID: "S1", Action: ActionQuestion, Body: "Still here?", StartLine: 3},
+	}
+	if len(got.Comments) != len(wantComments) {
+		t.Fatalf("got %d comments, want %d: %+v", len(got.Comments), len(wantComments), got.Comments)
+	}
+	for i := range wantComments {
+		if got.Comments[i] != wantComments[i] {
+			t.Errorf("comment[%d] = %+v, want %+v", i, got.Comments[i], wantComments[i])
+		}
+	}
+
+	if len(orphans) != 2 {
+		t.Fatalf("got %d orphans, want 2: %+v", len(orphans), orphans)
+	}
+	if orphans[0].SectionID != "S1" || orphans[0].Body != "Gone." {
+		t.Errorf("orphans[0] = %+v, want S1 section comment", orphans[0])
+	}
+	if orphans[1].StartLine != 40 || orphans[1].EndLine != 42 {
+		t.Errorf("orphans[1] = %+v, want L40-L42 line comment", orphans[1])
+	}
+}
+
+func TestParseReviewErrors(t *testing.T) {
+	doc := &Document{Sections: []*Section{{ID: "S1", Title: "Step", Level: 2}}}
+
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{
+			name:    "missing review heading",
+			input:   "## S1: Step\n[note] hi\n",
+			wantErr: "missing",
+		},
+		{
+			name:    "unknown label",
+			input:   "# Review\n\n## S1: Step\n[opinion] hi\n",
+			wantErr: "unknown comment label",
+		},
+		{
+			name:    "unknown decoration",
+			input:   "# Review\n\n## S1: Step\n[note (urgent)] hi\n",
+			wantErr: "unknown comment decoration",
+		},
+		{
+			name:    "unrecognized heading",
+			input:   "# Review\n\n## Appendix\n[note] hi\n",
+			wantErr: "unrecognized section heading",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := ParseReview([]byte(tt.input), doc)
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %q, want to contain %q", err.Error(), tt.wantErr)
+			}
+		})
+	}
+}
@@ -5,16 +5,24 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 )
 
+// viewedStateVersion is the current sidecar format version.
+// Version 1 (no "version" field) keyed sections by title and hashed title+body.
+const viewedStateVersion = 2
+
 // ViewedState tracks which sections have been viewed and their content hashes.
 type ViewedState struct {
-	Sections map[string]string `json:"sections"` // title -> content hash
+	Version  int               `json:"version"`
+	Sections map[string]string `json:"sections"` // heading path key -> body hash
+
+	legacy bool // loaded from a version 1 file; migrated by Reconcile
 }
 
 // NewViewedState creates an empty ViewedState.
 func NewViewedState() *ViewedState {
-	return &ViewedState{Sections: make(map[string]string)}
+	return &ViewedState{Version: viewedStateVersion, Sections: make(map[string]string)}
 }
 
 // StatePath returns the sidecar file path for persisting viewed state.
@@ -22,13 +30,27 @@ func StatePath(filePath string) string {
 	return filePath + ".reviewed.json"
 }
 
-// contentHash computes a truncated SHA-256 hash of a section's title and body.
+// viewedKey returns the state key for a section: its heading path joined with " > ".
+// Sections with the same title under different parents get distinct keys.
+func viewedKey(s *Section) string {
+	return strings.Join(s.HeadingPath(), " > ")
+}
+
+// contentHash computes a truncated SHA-256 hash of a section's body.
+// The title is excluded so that a renamed heading keeps its hash.
 func contentHash(s *Section) string {
+	h := sha256.Sum256([]byte(s.Body))
+	return fmt.Sprintf("%x", h[:8])
+}
+
+// legacyContentHash computes the version 1 hash of a section's title and body.
+func legacyContentHash(s *Section) string {
 	h := sha256.Sum256([]byte(s.Title + "\x00" + s.Body))
 	return fmt.Sprintf("%x", h[:8])
 }
 
 // LoadViewedState reads a viewed state file. Returns an empty state on any error.
+// Version 1 files are loaded as-is and migrated by Reconcile.
 func LoadViewedState(path string) *ViewedState {
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -41,11 +63,15 @@ func LoadViewedState(path string) *ViewedState {
 	if state.Sections == nil {
 		state.Sections = make(map[string]string)
 	}
+	if state.Version < viewedStateVersion {
+		state.legacy = true
+	}
 	return &state
 }
 
 // SaveViewedState writes the viewed state to a JSON file.
 func SaveViewedState(path string, state *ViewedState) error {
+	state.Version = viewedStateVersion
 	data, err := json.MarshalIndent(state, "", "  ")
 	if err != nil {
 		return fmt.Errorf("marshaling viewed state: %w", err)
@@ -56,9 +82,55 @@ func SaveViewedState(path string, state *ViewedState) error {
 	return nil
 }
 
-// IsSectionViewed returns true if the section's title is tracked and its content hash matches.
+// Reconcile updates the state against the current document.
+// Version 1 entries are migrated to heading path keys when their title and
+// content still match. Entries whose key no longer matches any section are
+// treated as renames when a section without an entry has the same body hash.
+func (vs *ViewedState) Reconcile(doc *Document) {
+	sections := doc.AllSections()
+
+	if vs.legacy {
+		old := vs.Sections
+		vs.Sections = make(map[string]string)
+		for _, s := range sections {
+			if hash, ok := old[s.Title]; ok && hash == legacyContentHash(s) {
+				vs.Sections[viewedKey(s)] = contentHash(s)
+			}
+		}
+		vs.Version = viewedStateVersion
+		vs.legacy = false
+	}
+
+	current := make(map[string]bool, len(sections))
+	for _, s := range sections {
+		current[viewedKey(s)] = true
+	}
+	stale := make(map[string][]string) // body hash -> keys of entries without a section
+	for key, hash := range vs.Sections {
+		if !current[key] {
+			stale[hash] = append(stale[hash], key)
+		}
+	}
+
+	for _, s := range sections {
+		key := viewedKey(s)
+		if _, ok := vs.Sections[key]; ok || strings.TrimSpace(s.Body) == "" {
+			continue // already tracked, or too little content to identify a rename
+		}
+		hash := contentHash(s)
+		candidates := stale[hash]
+		if len(candidates) != 1 {
+			continue // no match, or ambiguous
+		}
+		delete(vs.Sections, candidates[0])
+		delete(stale, hash)
+		vs.Sections[key] = hash
+	}
+}
+
+// IsSectionViewed returns true if the section is tracked and its content hash matches.
 func (vs *ViewedState) IsSectionViewed(s *Section) bool {
-	hash, ok := vs.Sections[s.Title]
+	hash, ok := vs.Sections[viewedKey(s)]
 	if !ok {
 		return false
 	}
@@ -67,10 +139,10 @@ func (vs *ViewedState) IsSectionViewed(s *Section) bool {
 
 // MarkViewed records a section as viewed with its current content hash.
 func (vs *ViewedState) MarkViewed(s *Section) {
-	vs.Sections[s.Title] = contentHash(s)
+	vs.Sections[viewedKey(s)] = contentHash(s)
 }
 
 // UnmarkViewed removes a section's viewed status.
 func (vs *ViewedState) UnmarkViewed(s *Section) {
-	delete(vs.Sections, s.Title)
+	delete(vs.Sections, viewedKey(s))
 }
@@ -149,3 +149,87 @@ func TestSaveViewedStateError(t *testing.T) {
 		t.Error("should return error for invalid path")
 	}
 }
+
+func TestViewedStateDuplicateTitles(t *testing.T) {
+	doc, err := Parse([]byte("## API\n\n### Tests\n\nA\n\n## CLI\n\n### Tests\n\nB\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	apiTests := doc.FindSection("S1.1")
+	cliTests := doc.FindSection("S2.1")
+
+	state := NewViewedState()
+	state.MarkViewed(apiTests)
+	if !state.IsSectionViewed(apiTests) {
+		t.Error("API > Tests should be viewed")
+	}
+	if state.IsSectionViewed(cliTests) {
+		t.Error("CLI > Tests should not share the viewed mark")
+	}
+	if _, ok := state.Sections["API > Tests"]; !ok {
+		t.Errorf("expected heading path key, got %v", state.Sections)
+	}
+}
+
+func TestViewedStateReconcileRename(t *testing.T) {
+	before, err := Parse([]byte("## Setup\n\nInstall deps.\n\n## Empty\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	state := NewViewedState()
+	for _, s := range before.AllSections() {
+		state.MarkViewed(s)
+	}
+
+	after, err := Parse([]byte("## Installation\n\nInstall deps.\n\n## Blank\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	state.Reconcile(after)
+
+	if !state.IsSectionViewed(after.FindSection("S1")) {
+		t.Error("renamed section with unchanged body should stay viewed")
+	}
+	if _, ok := state.Sections["Setup"]; ok {
+		t.Error("old key should be removed after rename")
+	}
+	if state.IsSectionViewed(after.FindSection("S2")) {
+		t.Error("empty-bodied sections should not be matched as renames")
+	}
+}
+
+func TestViewedStateMigrateV1(t *testing.T) {
+	doc, err := Parse([]byte("## Parent\n\nP\n\n### Step\n\nbody\n\n## Changed\n\nnew body\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	step := doc.FindSection("S1.1")
+	changed := doc.FindSection("S2")
+
+	// Version 1 files had no version field and keyed by title with title+body hashes.
+	v1 := `{"sections": {"Step": "` + legacyContentHash(step) + `", "Changed": "stale"}}`
+	path := filepath.Join(t.TempDir(), "state.json")
+	if err := os.WriteFile(path, []byte(v1), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	state := LoadViewedState(path)
+	state.Reconcile(doc)
+
+	if !state.IsSectionViewed(step) {
+		t.Error("migrated section should be viewed")
+	}
+	if state.IsSectionViewed(changed) {
+		t.Error("section with stale v1 hash should not be viewed")
+	}
+	if _, ok := state.Sections["Parent > Step"]; !ok {
+		t.Errorf("expected migrated heading path key, got %v", state.Sections)
+	}
+
+	if err := SaveViewedState(path, state); err != nil {
+		t.Fatal(err)
+	}
+	if reloaded := LoadViewedState(path); reloaded.Version != viewedStateVersion || reloaded.legacy {
+		t.Errorf("saved state version = %d, want %d", reloaded.Version, viewedStateVersion)
+	}
+}
@@ -0,0 +1,204 @@
+package markdown
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// SuggestionFence opens a suggested-change block in a comment body, as on GitHub.
+const SuggestionFence = "```suggestion"
+
+// Suggestion is replacement text proposed for the lines of a line comment.
+type Suggestion struct {
+	Original    []string `json:"original"`    // commented lines when the suggestion was made
+	Replacement []string `json:"replacement"` // proposed lines (empty = delete the lines)
+}
+
+// Errors reported by ApplySuggestions.
+var (
+	ErrSuggestionConflict = errors.New("the lines have changed since the review")
+	ErrSuggestionOverlap  = errors.New("overlaps another suggestion")
+)
+
+var diffHunkHeaderRe = regexp.MustCompile(`^@@ -\d+(?:,\d+)? \+\d+(?:,\d+)? @@`)
+
+// SuggestionBlock returns lines as a ```suggestion fenced block.
+func SuggestionBlock(lines []string) string {
+	return SuggestionFence + "\n" + strings.Join(append(slices.Clone(lines), "```"), "\n")
+}
+
+// BodyWithSuggestion returns the body followed by the suggestion as a
+// ```suggestion block, the form used by the comment editor and GitHub.
+func (c *ReviewComment) BodyWithSuggestion() string {
+	if c.Suggestion == nil {
+		return c.Body
+	}
+	block := SuggestionBlock(c.Suggestion.Replacement)
+	if c.Body == "" {
+		return block
+	}
+	return c.Body + "\n\n" + block
+}
+
+// SplitSuggestion extracts the first ```suggestion block from body. It returns
+// the remaining text and the block's lines; ok is false if body has no
+// complete block.
+func SplitSuggestion(body string) (text string, replacement []string, ok bool) {
+	lines := strings.Split(body, "\n")
+	open := slices.IndexFunc(lines, func(l string) bool { return strings.TrimSpace(l) == SuggestionFence })
+	if open < 0 {
+		return body, nil, false
+	}
+	n := slices.IndexFunc(lines[open+1:], func(l string) bool { return strings.TrimSpace(l) == "```" })
+	if n < 0 {
+		return body, nil, false
+	}
+	closing := open + 1 + n
+	replacement = slices.Clone(lines[open+1 : closing])
+	rest := append(slices.Clone(lines[:open]), lines[closing+1:]...)
+	return strings.TrimSpace(strings.Join(rest, "\n")), replacement, true
+}
+
+// Diff returns the suggestion as a unified diff hunk for the lines starting
+// at startLine. Lines shared at the start and end of the range are kept as
+// context.
+func (s *Suggestion) Diff(startLine int) string {
+	prefix := 0
+	for prefix < min(len(s.Original), len(s.Replacement)) && s.Original[prefix] == s.Replacement[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < min(len(s.Original), len(s.Replacement))-prefix &&
+		s.Original[len(s.Original)-1-suffix] == s.Replacement[len(s.Replacement)-1-suffix] {
+		suffix++
+	}
+
+	out := []string{fmt.Sprintf("@@ -%s +%s @@", hunkRange(startLine, len(s.Original)), hunkRange(startLine, len(s.Replacement)))}
+	for _, l := range s.Original[:prefix] {
+		out = append(out, " "+l)
+	}
+	for _, l := range s.Original[prefix : len(s.Original)-suffix] {
+		out = append(out, "-"+l)
+	}
+	for _, l := range s.Replacement[prefix : len(s.Replacement)-suffix] {
+		out = append(out, "+"+l)
+	}
+	for _, l := range s.Original[len(s.Original)-suffix:] {
+		out = append(out, " "+l)
+	}
+	return strings.Join(out, "\n")
+}
+
+// hunkRange formats the "start,count" part of a hunk header. An empty range
+// refers to the line before it, as in diff(1).
+func hunkRange(start, count int) string {
+	if count == 0 {
+		start--
+	}
+	if count == 1 {
+		return strconv.Itoa(start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
+
+// splitSuggestionDiff extracts a trailing ```diff block written for a
+// suggestion (see Suggestion.Diff) from a comment body read back by
+// ParseReview.
+func splitSuggestionDiff(body string) (string, *Suggestion) {
+	lines := strings.Split(strings.TrimRight(body, "\n"), "\n")
+	if len(lines) < 3 || lines[len(lines)-1] != "```" {
+		return body, nil
+	}
+	open := len(lines) - 1
+	for open >= 0 && lines[open] != "```diff" {
+		open--
+	}
+	if open < 0 || open+1 >= len(lines)-1 || !diffHunkHeaderRe.MatchString(lines[open+1]) {
+		return body, nil
+	}
+	s := &Suggestion{Original: []string{}, Replacement: []string{}}
+	for _, l := range lines[open+2 : len(lines)-1] {
+		if l == "" {
+			l = " " // editors may strip the trailing space of an empty context line
+		}
+		switch l[0] {
+		case ' ':
+			s.Original = append(s.Original, l[1:])
+			s.Replacement = append(s.Replacement, l[1:])
+		case '-':
+			s.Original = append(s.Original, l[1:])
+		case '+':
+			s.Replacement = append(s.Replacement, l[1:])
+		default:
+			return body, nil
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines[:open], "\n")), s
+}
+
+// AppliedSuggestion is the outcome of applying one suggestion.
+type AppliedSuggestion struct {
+	Comment ReviewComment
+	Line    int   // 1-based line the suggestion was applied at (0 = not applied)
+	Err     error // ErrSuggestionConflict or ErrSuggestionOverlap if not applied
+}
+
+// Offset returns how far the suggestion moved from the line it was made on.
+func (a AppliedSuggestion) Offset() int {
+	if a.Line == 0 {
+		return 0
+	}
+	return a.Line - a.Comment.StartLine
+}
+
+// ApplySuggestions replaces the lines of each suggestion in comments and
+// returns the new lines. Like patch, a suggestion whose original lines have
+// moved is applied at the nearest position where they still match. A
+// suggestion is not applied if its original lines can no longer be found
+// (ErrSuggestionConflict) or if they overlap a suggestion applied before it
+// (ErrSuggestionOverlap). Comments without a suggestion are ignored.
+func ApplySuggestions(lines []string, comments []ReviewComment) ([]string, []AppliedSuggestion) {
+	var results []AppliedSuggestion
+	for _, c := range comments {
+		if c.Suggestion == nil || c.StartLine == 0 {
+			continue
+		}
+		r := AppliedSuggestion{Comment: c}
+		anchor := &LineAnchor{Hash: linesHash(c.Suggestion.Original)}
+		start, ok := anchor.Locate(lines, c.StartLine, len(c.Suggestion.Original))
+		switch {
+		case !ok:
+			r.Err = ErrSuggestionConflict
+		case slices.ContainsFunc(results, func(p AppliedSuggestion) bool { return overlaps(p, start, len(c.Suggestion.Original)) }):
+			r.Err = ErrSuggestionOverlap
+		default:
+			r.Line = start
+		}
+		results = append(results, r)
+	}
+
+	// Splice from the bottom up so earlier line numbers stay valid.
+	applied := slices.DeleteFunc(slices.Clone(results), func(r AppliedSuggestion) bool { return r.Line == 0 })
+	slices.SortFunc(applied, func(a, b AppliedSuggestion) int { return cmp.Compare(b.Line, a.Line) })
+	out := slices.Clone(lines)
+	for _, r := range applied {
+		lo := r.Line - 1
+		out = slices.Replace(out, lo, lo+len(r.Comment.Suggestion.Original), r.Comment.Suggestion.Replacement...)
+	}
+	return out, results
+}
+
+// overlaps reports whether the applied suggestion p touches the n lines
+// starting at line start. Two pure insertions at the same line also overlap.
+func overlaps(p AppliedSuggestion, start, n int) bool {
+	if p.Line == 0 {
+		return false
+	}
+	pn := len(p.Comment.Suggestion.Original)
+	return start < p.Line+max(pn, 1) && p.Line < start+max(n, 1)
+}
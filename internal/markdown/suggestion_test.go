@@ -0,0 +1,172 @@
+package markdown
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSplitSuggestion(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		wantText string
+		wantRepl []string
+		wantOK   bool
+	}{
+		{"plain text", "just text", "just text", nil, false},
+		{"block only", "```suggestion\na\nb\n```", "", []string{"a", "b"}, true},
+		{"text around block", "Before.\n\n```suggestion\nx\n```\n\nAfter.", "Before.\n\n\nAfter.", []string{"x"}, true},
+		{"empty block deletes", "```suggestion\n```", "", []string{}, true},
+		{"unclosed block", "```suggestion\nx", "```suggestion\nx", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, repl, ok := SplitSuggestion(tt.body)
+			if text != tt.wantText || ok != tt.wantOK || strings.Join(repl, "|") != strings.Join(tt.wantRepl, "|") {
+				t.Errorf("SplitSuggestion() = %q, %q, %v, want %q, %q, %v", text, repl, ok, tt.wantText, tt.wantRepl, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestSuggestionDiff(t *testing.T) {
+	tests := []struct {
+		name  string
+		s     Suggestion
+		start int
+		want  string
+	}{
+		{
+			name:  "single line",
+			s:     Suggestion{Original: []string{"old"}, Replacement: []string{"new"}},
+			start: 12,
+			want:  "@@ -12 +12 @@\n-old\n+new",
+		},
+		{
+			name:  "shared lines become context",
+			s:     Suggestion{Original: []string{"a", "b", "c"}, Replacement: []string{"a", "B", "B2", "c"}},
+			start: 5,
+			want:  "@@ -5,3 +5,4 @@\n a\n-b\n+B\n+B2\n c",
+		},
+		{
+			name:  "deletion",
+			s:     Suggestion{Original: []string{"x", "y"}, Replacement: []string{}},
+			start: 3,
+			want:  "@@ -3,2 +2,0 @@\n-x\n-y",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.s.Diff(tt.start); got != tt.want {
+				t.Errorf("Diff() =\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatReviewSuggestionRoundTrip(t *testing.T) {
+	source := "# Plan\n\n## Setup\n\n--\nInstall deps.\nnpm ci\n"
+	doc, err := Parse([]byte(source))
+	if err != nil {
+		t.Fatal(err)
+	}
+	comments := []ReviewComment{
+		{SectionID: "S1", Action: ActionSuggestion, Body: "Drop the rule.", StartLine: 5, EndLine: 6,
+			Suggestion: &Suggestion{Original: []string{"--", "Install deps."}, Replacement: []string{"Install deps."}}},
+		{SectionID: "S1", Action: ActionNitpick, StartLine: 7,
+			Suggestion: &Suggestion{Original: []string{"npm ci"}, Replacement: []string{"npm ci --ignore-scripts"}}},
+	}
+
+	out := FormatReviewWith(&ReviewResult{Comments: comments}, doc, "plan.md", FormatOptions{})
+	wantPart := "`L5-L6` [suggestion] Drop the rule.\n\n```diff\n@@ -5,2 +5 @@\n---\n Install deps.\n```\n\n`L7` [nitpick]\n\n```diff\n"
+	if !strings.Contains(out, wantPart) {
+		t.Errorf("FormatReview() =\n%s\nwant to contain:\n%s", out, wantPart)
+	}
+
+	for _, order := range Orders {
+		for _, quote := range []bool{false, true} {
+			out := FormatReviewWith(&ReviewResult{Comments: comments}, doc, "plan.md", FormatOptions{Order: order, Quote: quote})
+			result, orphans, err := ParseReview([]byte(out), doc)
+			if err != nil || len(orphans) != 0 || len(result.Comments) != 2 {
+				t.Fatalf("order %s quote %v: ParseReview() = %+v, %v, %v\n%s", order, quote, result, orphans, err, out)
+			}
+			for i, c := range result.Comments {
+				if c.Body != comments[i].Body || c.Suggestion == nil ||
+					strings.Join(c.Suggestion.Original, "|") != strings.Join(comments[i].Suggestion.Original, "|") ||
+					strings.Join(c.Suggestion.Replacement, "|") != strings.Join(comments[i].Suggestion.Replacement, "|") {
+					t.Errorf("order %s quote %v: comment %d = %+v (suggestion %+v)", order, quote, i, c, c.Suggestion)
+				}
+			}
+		}
+	}
+}
+
+func TestApplySuggestions(t *testing.T) {
+	lines := []string{"# Plan", "", "a", "b", "c", "d", ""}
+	suggest := func(start, end int, orig, repl []string) ReviewComment {
+		return ReviewComment{Action: ActionSuggestion, StartLine: start, EndLine: end,
+			Suggestion: &Suggestion{Original: orig, Replacement: repl}}
+	}
+
+	tests := []struct {
+		name     string
+		comments []ReviewComment
+		want     string
+		wantLine []int
+		wantErr  []error
+	}{
+		{
+			name:     "replace and delete",
+			comments: []ReviewComment{suggest(3, 0, []string{"a"}, []string{"A", "A2"}), suggest(5, 6, []string{"c", "d"}, nil)},
+			want:     "# Plan||A|A2|b|",
+			wantLine: []int{3, 5},
+			wantErr:  []error{nil, nil},
+		},
+		{
+			name:     "moved lines apply at an offset",
+			comments: []ReviewComment{suggest(1, 0, []string{"b"}, []string{"B"})},
+			want:     "# Plan||a|B|c|d|",
+			wantLine: []int{4},
+			wantErr:  []error{nil},
+		},
+		{
+			name:     "changed lines conflict",
+			comments: []ReviewComment{suggest(3, 0, []string{"x"}, []string{"y"}), suggest(4, 0, []string{"b"}, []string{"B"})},
+			want:     "# Plan||a|B|c|d|",
+			wantLine: []int{0, 4},
+			wantErr:  []error{ErrSuggestionConflict, nil},
+		},
+		{
+			name:     "overlapping suggestions",
+			comments: []ReviewComment{suggest(3, 4, []string{"a", "b"}, []string{"ab"}), suggest(4, 5, []string{"b", "c"}, []string{"bc"})},
+			want:     "# Plan||ab|c|d|",
+			wantLine: []int{3, 0},
+			wantErr:  []error{nil, ErrSuggestionOverlap},
+		},
+		{
+			name:     "plain comments are ignored",
+			comments: []ReviewComment{{Action: ActionNote, Body: "x", StartLine: 3}},
+			want:     "# Plan||a|b|c|d|",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, results := ApplySuggestions(lines, tt.comments)
+			if strings.Join(got, "|") != tt.want {
+				t.Errorf("lines = %q, want %q", strings.Join(got, "|"), tt.want)
+			}
+			if len(results) != len(tt.wantLine) {
+				t.Fatalf("results = %+v, want %d", results, len(tt.wantLine))
+			}
+			for i, r := range results {
+				if r.Line != tt.wantLine[i] || !errors.Is(r.Err, tt.wantErr[i]) {
+					t.Errorf("result %d = line %d, err %v, want line %d, err %v", i, r.Line, r.Err, tt.wantLine[i], tt.wantErr[i])
+				}
+			}
+		})
+	}
+	if strings.Join(lines, "|") != "# Plan||a|b|c|d|" {
+		t.Error("ApplySuggestions modified its input")
+	}
+}
@@ -0,0 +1,231 @@
+package markdown
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+//go:embed templates/default.tmpl
+var defaultTemplateText string
+
+// defaultTemplate renders the built-in review format.
+var defaultTemplate = template.Must(newReviewTemplate("default").Parse(defaultTemplateText))
+
+// DefaultTemplate returns the source of the built-in review template.
+func DefaultTemplate() string {
+	return defaultTemplateText
+}
+
+// FormatOptions controls optional parts of the review output.
+type FormatOptions struct {
+	// Quote adds the referenced source lines under each line comment and a
+	// short excerpt under each commented section heading.
+	Quote bool
+	// Order is one of the Order constants ("" means OrderAdded).
+	Order string
+}
+
+// ReviewData is the data model passed to review templates.
+type ReviewData struct {
+	Options  FormatOptions     // options the review is rendered with
+	FilePath string            // path of the reviewed file ("" if unknown)
+	Target   string            // FilePath, or "the file" if it is empty
+	Document *Document         // the reviewed document
+	Comments []ReviewComment   // all comments in the order they were added
+	Sections []SectionComments // section-level comments grouped by section, in order of first comment
+	Lines    []LineComment     // line-level comments in the order they were added
+	Groups   []CommentGroup    // all comments grouped by Options.Order (nil for OrderAdded)
+	Verdicts []SectionVerdict  // section verdicts in document order
+	Counts   []LabelCount      // comments per action label, in label order (labels without comments omitted)
+	Total    int               // number of comments
+}
+
+// SectionComments is the group of section-level comments on one section.
+type SectionComments struct {
+	ID       string          // section ID, or OverviewSectionID
+	Heading  string          // "S1: Title", or "Overview"
+	Section  *Section        // nil for the overview and for unknown sections
+	Comments []ReviewComment // comments in the order they were added
+	Quote    string          // "> L5: ..." excerpt of the section (only with FormatOptions.Quote)
+}
+
+// LineComment is a line-level comment with the source lines it refers to.
+type LineComment struct {
+	ReviewComment
+	Ref    string   // line reference, e.g. "L10-L12"
+	Source []string // referenced source lines (empty if out of range)
+	Quote  string   // "> L10: ..." quote of Source, capped for long ranges (only with FormatOptions.Quote)
+	Diff   string   // unified diff of the Suggestion ("" if there is none)
+}
+
+// LabelCount is the number of comments with a given action label.
+type LabelCount struct {
+	Label ActionType
+	Count int
+}
+
+// NewReviewData builds the template data for result.
+func NewReviewData(result *ReviewResult, d *Document, filePath string, opts FormatOptions) *ReviewData {
+	data := &ReviewData{
+		Options:  opts,
+		FilePath: filePath,
+		Target:   filePath,
+		Document: d,
+		Comments: result.Comments,
+		Verdicts: SectionVerdicts(result, d),
+		Total:    len(result.Comments),
+	}
+	if data.Target == "" {
+		data.Target = "the file"
+	}
+
+	groups := make(map[string]int) // section ID -> index into data.Sections
+	counts := make(map[ActionType]int)
+	for _, c := range result.Comments {
+		counts[c.Action]++
+		if c.StartLine > 0 {
+			data.Lines = append(data.Lines, newCommentEntry(c, d, opts))
+			continue
+		}
+		i, ok := groups[c.SectionID]
+		if !ok {
+			i = len(data.Sections)
+			groups[c.SectionID] = i
+			g := newSectionComments(d, c.SectionID)
+			if opts.Quote {
+				g.Quote = sectionExcerpt(d, c.SectionID)
+			}
+			data.Sections = append(data.Sections, g)
+		}
+		data.Sections[i].Comments = append(data.Sections[i].Comments, c)
+	}
+
+	if opts.Order != "" && opts.Order != OrderAdded {
+		data.Groups = groupComments(result.Comments, d, opts)
+	}
+
+	for _, a := range Labels.Actions {
+		if n := counts[a]; n > 0 {
+			data.Counts = append(data.Counts, LabelCount{Label: a, Count: n})
+			delete(counts, a)
+		}
+	}
+	// Labels no longer in the active set (e.g. from a resumed review) go last.
+	for _, c := range result.Comments {
+		if n := counts[c.Action]; n > 0 {
+			data.Counts = append(data.Counts, LabelCount{Label: c.Action, Count: n})
+			delete(counts, c.Action)
+		}
+	}
+	return data
+}
+
+func newSectionComments(d *Document, id string) SectionComments {
+	if id == OverviewSectionID {
+		return SectionComments{ID: id, Heading: "Overview"}
+	}
+	g := SectionComments{ID: id, Heading: id}
+	if s := d.FindSection(id); s != nil {
+		g.Section = s
+		g.Heading = fmt.Sprintf("%s: %s", id, s.Title)
+	}
+	return g
+}
+
+// sourceLines returns the 1-based line range [start, end] of d's source,
+// clipped to the document. end == 0 means a single line.
+func sourceLines(d *Document, start, end int) []string {
+	if end < start {
+		end = start
+	}
+	end = min(end, len(d.SourceLines))
+	if start < 1 || start > end {
+		return nil
+	}
+	return d.SourceLines[start-1 : end]
+}
+
+// ParseReviewTemplate reads a review template from path.
+func ParseReviewTemplate(path string) (*template.Template, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading template: %w", err)
+	}
+	tmpl, err := newReviewTemplate(filepath.Base(path)).Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("parsing template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// RenderReview renders result with tmpl (nil means the built-in template).
+// Returns "" if there are no comments or verdicts, like FormatReview.
+func RenderReview(tmpl *template.Template, result *ReviewResult, d *Document, filePath string, opts FormatOptions) (string, error) {
+	if result.IsEmpty() {
+		return "", nil
+	}
+	if tmpl == nil {
+		tmpl = defaultTemplate
+	}
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, NewReviewData(result, d, filePath, opts)); err != nil {
+		return "", fmt.Errorf("rendering template: %w", err)
+	}
+	return sb.String(), nil
+}
+
+// newReviewTemplate returns an empty template with the review helper functions.
+func newReviewTemplate(name string) *template.Template {
+	return template.New(name).Funcs(template.FuncMap{
+		"join":   strings.Join,
+		"quote":  quoteLines,
+		"indent": indentLines,
+		"hang":   hangLines,
+		"upper":  strings.ToUpper,
+		"lower":  strings.ToLower,
+		"trim":   strings.TrimSpace,
+	})
+}
+
+// quoteLines prefixes each line of v (a string or []string) with "> ".
+func quoteLines(v any) (string, error) {
+	return indentLines("> ", v)
+}
+
+// hangLines prefixes each line of s but the first with prefix (a hanging
+// indent), so that no continuation line of a comment body can be read back
+// as a new comment or as the review's structure. Blank lines stay empty.
+func hangLines(prefix, s string) string {
+	first, rest, ok := strings.Cut(s, "\n")
+	if !ok {
+		return s
+	}
+	out, _ := indentLines(prefix, rest)
+	return first + "\n" + out
+}
+
+// indentLines prefixes each line of v (a string or []string) with prefix and
+// joins them with newlines. Trailing spaces are trimmed from each line.
+func indentLines(prefix string, v any) (string, error) {
+	var lines []string
+	switch v := v.(type) {
+	case string:
+		lines = strings.Split(v, "\n")
+	case []string:
+		lines = v
+	default:
+		return "", fmt.Errorf("cannot prefix lines of %T", v)
+	}
+	var sb strings.Builder
+	for i, l := range lines {
+		if i > 0 {
+			sb.WriteByte('\n')
+		}
+		sb.WriteString(strings.TrimRight(prefix+l, " "))
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,144 @@
+package markdown
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func templateTestDoc() *Document {
+	return &Document{
+		Title: "Plan",
+		Sections: []*Section{
+			{ID: "S1", Title: "Setup", Level: 2, StartLine: 3, EndLine: 5},
+			{ID: "S2", Title: "Deploy", Level: 2, StartLine: 6, EndLine: 8},
+		},
+		SourceLines: []string{"# Plan", "", "## Setup", "", "Install deps.", "## Deploy", "", "Run deploy."},
+	}
+}
+
+func TestFormatReviewExactOutput(t *testing.T) {
+	result := &ReviewResult{Comments: []ReviewComment{
+		{SectionID: "S2", Action: ActionIssue, Decoration: DecorationBlocking, Body: "Missing rollback."},
+		{SectionID: OverviewSectionID, Action: ActionNote, Body: "Overall fine."},
+		{SectionID: "S1", Action: ActionSuggestion, Body: "Pin versions.", StartLine: 5},
+		{SectionID: "S2", Action: ActionQuestion, Body: "Which env?"},
+		{SectionID: "S2", Action: ActionNitpick, Body: "Typo.", StartLine: 6, EndLine: 8},
+	}}
+	want := "# Review\n\n" +
+		"Please review and address the following comments on: plan.md\n" +
+		"\n## S2: Deploy\n" +
+		"[issue (blocking)] Missing rollback.\n" +
+		"[question] Which env?\n" +
+		"\n## Overview\n" +
+		"[note] Overall fine.\n" +
+		"\n---\n" +
+		"\n`L5` [suggestion] Pin versions.\n" +
+		"\n`L6-L8` [nitpick] Typo.\n"
+	if got := FormatReview(result, templateTestDoc(), "plan.md"); got != want {
+		t.Errorf("FormatReview() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestNewReviewData(t *testing.T) {
+	result := &ReviewResult{Comments: []ReviewComment{
+		{SectionID: "S2", Action: ActionQuestion, Body: "a"},
+		{SectionID: "S1", Action: ActionIssue, Body: "b", StartLine: 4, EndLine: 5},
+		{SectionID: "S2", Action: ActionIssue, Body: "c"},
+		{SectionID: "S9", Action: ActionIssue, Body: "d"},
+		{SectionID: "S1", Action: ActionNote, Body: "e", StartLine: 99},
+	}}
+	data := NewReviewData(result, templateTestDoc(), "", FormatOptions{})
+
+	if data.Target != "the file" || data.Total != 5 {
+		t.Errorf("Target = %q, Total = %d", data.Target, data.Total)
+	}
+	if len(data.Sections) != 2 || data.Sections[0].ID != "S2" || len(data.Sections[0].Comments) != 2 {
+		t.Fatalf("Sections = %+v, want S2 (2 comments) then S9", data.Sections)
+	}
+	if data.Sections[0].Section == nil || data.Sections[1].Section != nil || data.Sections[1].Heading != "S9" {
+		t.Errorf("unknown section should have nil Section and bare ID heading: %+v", data.Sections[1])
+	}
+	if len(data.Lines) != 2 {
+		t.Fatalf("Lines = %+v, want 2", data.Lines)
+	}
+	if got := strings.Join(data.Lines[0].Source, "|"); got != "|Install deps." || data.Lines[0].Ref != "L4-L5" {
+		t.Errorf("Lines[0] = %+v", data.Lines[0])
+	}
+	if data.Lines[1].Source != nil {
+		t.Errorf("out of range Source = %q, want nil", data.Lines[1].Source)
+	}
+	want := []LabelCount{{ActionIssue, 3}, {ActionQuestion, 1}, {ActionNote, 1}}
+	if len(data.Counts) != len(want) {
+		t.Fatalf("Counts = %+v, want %+v", data.Counts, want)
+	}
+	for i := range want {
+		if data.Counts[i] != want[i] {
+			t.Errorf("Counts[%d] = %+v, want %+v", i, data.Counts[i], want[i])
+		}
+	}
+}
+
+func TestRenderReviewCustomTemplate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "slack.tmpl")
+	tmplText := `*Review of {{.FilePath}}* ({{.Total}} comments:{{range .Counts}} {{.Count}} {{.Label}}{{end}})
+{{range .Sections}}• {{.Heading}}:{{range .Comments}} [{{.FormatLabel}}] {{.Body}}{{end}}
+{{end}}{{range .Lines}}{{.Ref}} {{upper (printf "%s" .Action)}}
+{{quote .Source}}
+{{end}}`
+	if err := os.WriteFile(path, []byte(tmplText), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tmpl, err := ParseReviewTemplate(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := &ReviewResult{Comments: []ReviewComment{
+		{SectionID: "S1", Action: ActionIssue, Decoration: DecorationBlocking, Body: "Pin versions."},
+		{SectionID: "S2", Action: ActionNitpick, Body: "Typo.", StartLine: 6, EndLine: 8},
+	}}
+	got, err := RenderReview(tmpl, result, templateTestDoc(), "plan.md", FormatOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "*Review of plan.md* (2 comments: 1 issue 1 nitpick)\n" +
+		"• S1: Setup: [issue (blocking)] Pin versions.\n" +
+		"L6-L8 NITPICK\n" +
+		"> ## Deploy\n>\n> Run deploy.\n"
+	if got != want {
+		t.Errorf("RenderReview() =\n%q\nwant:\n%q", got, want)
+	}
+
+	if got, err := RenderReview(tmpl, &ReviewResult{}, templateTestDoc(), "plan.md", FormatOptions{}); got != "" || err != nil {
+		t.Errorf("RenderReview(no comments) = %q, %v, want empty", got, err)
+	}
+}
+
+func TestReviewTemplateErrors(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := ParseReviewTemplate(filepath.Join(dir, "missing.tmpl")); err == nil || !strings.Contains(err.Error(), "reading template") {
+		t.Errorf("missing file error = %v", err)
+	}
+
+	bad := filepath.Join(dir, "bad.tmpl")
+	if err := os.WriteFile(bad, []byte("{{range .Sections}}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ParseReviewTemplate(bad); err == nil || !strings.Contains(err.Error(), "parsing template") {
+		t.Errorf("bad template error = %v", err)
+	}
+
+	unknownField := filepath.Join(dir, "field.tmpl")
+	if err := os.WriteFile(unknownField, []byte("{{.Nope}}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tmpl, err := ParseReviewTemplate(unknownField)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := &ReviewResult{Comments: []ReviewComment{{SectionID: "S1", Action: ActionNote, Body: "x"}}}
+	if _, err := RenderReview(tmpl, result, templateTestDoc(), "", FormatOptions{}); err == nil || !strings.Contains(err.Error(), "rendering template") {
+		t.Errorf("render error = %v", err)
+	}
+}
@@ -0,0 +1,82 @@
+package markdown
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+// Verdict is a reviewer's decision on a whole section, e.g. one step of a plan.
+type Verdict string
+
+const (
+	VerdictNone         Verdict = ""
+	VerdictApprove      Verdict = "approve"
+	VerdictNeedsChanges Verdict = "needs-changes"
+	VerdictReject       Verdict = "reject"
+)
+
+// Verdicts lists the verdicts in cycling order.
+var Verdicts = []Verdict{VerdictApprove, VerdictNeedsChanges, VerdictReject}
+
+// Next returns the verdict after v in the cycle
+// none -> approve -> needs changes -> reject -> none.
+func (v Verdict) Next() Verdict {
+	i := slices.Index(Verdicts, v)
+	if i+1 >= len(Verdicts) {
+		return VerdictNone
+	}
+	return Verdicts[i+1]
+}
+
+// Label returns the display form of v, e.g. "needs changes".
+func (v Verdict) Label() string {
+	return strings.ReplaceAll(string(v), "-", " ")
+}
+
+// parseVerdictLabel returns the verdict whose Label is label.
+func parseVerdictLabel(label string) (Verdict, bool) {
+	for _, v := range Verdicts {
+		if v.Label() == label {
+			return v, true
+		}
+	}
+	return VerdictNone, false
+}
+
+// SectionVerdict is the verdict on one section.
+type SectionVerdict struct {
+	ID      string   // section ID
+	Heading string   // "S1: Title", or the bare ID if the section is unknown
+	Section *Section // nil if the section is not in the document
+	Verdict Verdict
+}
+
+// SectionVerdicts returns the verdicts of result in document order. Verdicts
+// on sections that are not in d follow, ordered by ID.
+func SectionVerdicts(result *ReviewResult, d *Document) []SectionVerdict {
+	var out []SectionVerdict
+	for _, s := range d.AllSections() {
+		if v := result.Verdicts[s.ID]; v != VerdictNone {
+			out = append(out, SectionVerdict{ID: s.ID, Heading: newSectionComments(d, s.ID).Heading, Section: s, Verdict: v})
+		}
+	}
+	var unknown []SectionVerdict
+	for id, v := range result.Verdicts {
+		if v != VerdictNone && d.FindSection(id) == nil {
+			unknown = append(unknown, SectionVerdict{ID: id, Heading: id, Verdict: v})
+		}
+	}
+	slices.SortFunc(unknown, func(a, b SectionVerdict) int { return cmp.Compare(a.ID, b.ID) })
+	return append(out, unknown...)
+}
+
+// IsEmpty reports whether the review has neither comments nor verdicts.
+func (r *ReviewResult) IsEmpty() bool {
+	for _, v := range r.Verdicts {
+		if v != VerdictNone {
+			return false
+		}
+	}
+	return len(r.Comments) == 0
+}
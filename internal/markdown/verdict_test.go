@@ -0,0 +1,90 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestVerdictNext(t *testing.T) {
+	want := []Verdict{VerdictApprove, VerdictNeedsChanges, VerdictReject, VerdictNone}
+	v := VerdictNone
+	for i, w := range want {
+		v = v.Next()
+		if v != w {
+			t.Errorf("step %d: Next() = %q, want %q", i, v, w)
+		}
+	}
+	if got := VerdictNeedsChanges.Label(); got != "needs changes" {
+		t.Errorf("Label() = %q", got)
+	}
+}
+
+func TestSectionVerdicts(t *testing.T) {
+	result := &ReviewResult{Verdicts: map[string]Verdict{
+		"S2": VerdictReject, "S9": VerdictApprove, "S1": VerdictNeedsChanges, "S3": VerdictNone,
+	}}
+	got := SectionVerdicts(result, templateTestDoc())
+	if len(got) != 3 {
+		t.Fatalf("SectionVerdicts() = %+v, want 3", got)
+	}
+	if got[0].Heading != "S1: Setup" || got[1].Heading != "S2: Deploy" || got[2].Heading != "S9" || got[2].Section != nil {
+		t.Errorf("SectionVerdicts() = %+v, want S1, S2, then unknown S9", got)
+	}
+}
+
+func TestFormatReviewVerdicts(t *testing.T) {
+	result := &ReviewResult{
+		Comments: []ReviewComment{{SectionID: "S2", Action: ActionIssue, Body: "Missing rollback."}},
+		Verdicts: map[string]Verdict{"S2": VerdictReject, "S1": VerdictApprove},
+	}
+	want := "# Review\n\n" +
+		"Please review and address the following comments on: plan.md\n" +
+		"\n## Verdicts\n" +
+		"- `S1: Setup` approve\n" +
+		"- `S2: Deploy` reject\n" +
+		"\n## S2: Deploy\n" +
+		"[issue] Missing rollback.\n"
+	if got := FormatReview(result, templateTestDoc(), "plan.md"); got != want {
+		t.Errorf("FormatReview() =\n%s\nwant:\n%s", got, want)
+	}
+
+	onlyVerdicts := &ReviewResult{Verdicts: map[string]Verdict{"S1": VerdictNeedsChanges}}
+	if got := FormatReview(onlyVerdicts, templateTestDoc(), "plan.md"); !strings.Contains(got, "- `S1: Setup` needs changes") {
+		t.Errorf("verdict-only review =\n%s", got)
+	}
+}
+
+func TestParseReviewVerdicts(t *testing.T) {
+	doc := templateTestDoc()
+	result := &ReviewResult{
+		Comments: []ReviewComment{
+			{SectionID: "S1", Action: ActionNote, Body: "ok"},
+			{SectionID: "S2", Action: ActionIssue, Body: "bad", StartLine: 8},
+		},
+		Verdicts: map[string]Verdict{"S1": VerdictApprove, "S2": VerdictNeedsChanges},
+	}
+	for _, order := range Orders {
+		out := FormatReviewWith(result, doc, "plan.md", FormatOptions{Order: order})
+		got, _, err := ParseReview([]byte(out), doc)
+		if err != nil {
+			t.Fatalf("order %s: %v\n%s", order, err, out)
+		}
+		if len(got.Comments) != 2 || got.Verdicts["S1"] != VerdictApprove || got.Verdicts["S2"] != VerdictNeedsChanges || len(got.Verdicts) != 2 {
+			t.Errorf("order %s: ParseReview() = %+v", order, got)
+		}
+	}
+
+	bad := "# Review\n\n## Verdicts\n- `S1: Setup` maybe\n"
+	if _, _, err := ParseReview([]byte(bad), doc); err == nil || !strings.Contains(err.Error(), `unknown verdict "maybe"`) {
+		t.Errorf("unknown verdict error = %v", err)
+	}
+
+	renamed := "# Review\n\n## Verdicts\n- `S7: Gone` reject\n- `S4: Deploy` approve\n"
+	got, _, err := ParseReview([]byte(renamed), doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Verdicts) != 1 || got.Verdicts["S2"] != VerdictApprove {
+		t.Errorf("verdicts = %v, want the renumbered S2 only", got.Verdicts)
+	}
+}
@@ -44,10 +44,13 @@ func (w *WezTermSpawner) Name() string {
 func (w *WezTermSpawner) SpawnAndWait(ctx context.Context, cmd string, args []string) error {
 	direction, percent := w.splitDirection()
 
-	fullCmd := append([]string{cmd}, args...)
-	splitArgs := append([]string{
-		"cli", "split-pane", direction, "--percent", percent, "--",
-	}, fullCmd...)
+	// Start the pane in our working directory, so the command finds the same
+	// files and config as we do rather than the focused pane's directory.
+	splitArgs := []string{"cli", "split-pane", direction, "--percent", percent}
+	if wd, err := os.Getwd(); err == nil {
+		splitArgs = append(splitArgs, "--cwd", wd)
+	}
+	splitArgs = append(append(splitArgs, "--", cmd), args...)
 
 	out, err := w.run("wezterm", splitArgs...)
 	if err != nil {
@@ -3,6 +3,7 @@ package pane
 import (
 	"context"
 	"fmt"
+	"slices"
 	"testing"
 )
 
@@ -23,6 +24,7 @@ func TestWezTermSpawnerAvailable(t *testing.T) {
 type mockRunner struct {
 	calls   []mockCall
 	callIdx int
+	args    [][]string // recorded arguments of each call
 }
 
 type mockCall struct {
@@ -36,6 +38,7 @@ func (m *mockRunner) Output(name string, args ...string) ([]byte, error) {
 	}
 	c := m.calls[m.callIdx]
 	m.callIdx++
+	m.args = append(m.args, args)
 	return c.out, c.err
 }
 
@@ -232,10 +235,17 @@ func TestSpawnAndWaitSuccess(t *testing.T) {
 	}
 	// Ensure WEZTERM_PANE is not set so splitDirection falls back without calling runner
 	t.Setenv("WEZTERM_PANE", "")
+	dir := t.TempDir()
+	t.Chdir(dir)
 	err := w.SpawnAndWait(context.Background(), "commd", []string{"review", "plan.md"})
 	if err != nil {
 		t.Fatalf("SpawnAndWait() error = %v", err)
 	}
+	split := w.runner.(*mockRunner).args[0]
+	want := []string{"--cwd", dir, "--", "commd", "review", "plan.md"}
+	if got := split[len(split)-len(want):]; !slices.Equal(got, want) {
+		t.Errorf("split-pane args = %v, want suffix %v", split, want)
+	}
 }
 
 func TestSpawnAndWaitSplitError(t *testing.T) {
@@ -2,6 +2,7 @@ package tui
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/key"
@@ -21,14 +22,16 @@ const (
 	ModeHelp                       // Help overlay
 	ModeSearch                     // Section search
 	ModeLineSelect                 // Visual line selection in raw view
+	ModeThreadList                 // PR review thread list (reply/resolve)
 )
 
 // confirmKind identifies the action pending confirmation.
 type confirmKind int
 
 const (
-	confirmQuit   confirmKind = iota // quit without submitting
-	confirmSubmit                    // submit the review
+	confirmQuit         confirmKind = iota // quit without submitting
+	confirmSubmit                          // submit the review
+	confirmRestoreDraft                    // restore draft comments from a previous session
 )
 
 // Focus represents which pane has focus.
@@ -44,8 +47,14 @@ const scrollToEnd = 1 << 30
 
 // AppResult is the result returned when the TUI exits.
 type AppResult struct {
-	Review *markdown.ReviewResult
-	Status markdown.Status
+	Review   *markdown.ReviewResult
+	Status   markdown.Status
+	Viewed   int // sections marked viewed at exit
+	Sections int // total number of sections
+
+	// Replies and Resolutions are the changes queued on existing PR review threads.
+	Replies     []ThreadReply
+	Resolutions []ThreadResolution
 }
 
 // App is the main Bubble Tea model for the TUI.
@@ -56,24 +65,33 @@ type App struct {
 	linePane    *LinePane
 	comment     *CommentEditor
 	commentList *CommentList
+	threadList  *ThreadList
 	search      *SearchBar
 	keymap      KeyMap
 	styles      Styles
 
-	mode      AppMode
-	focus     Focus
-	fullView  bool
-	rawView   bool // true = raw source + line numbers, false = glamour rendering
-	width     int
-	height    int
-	ready     bool
-	leftRatio int // left pane width percentage (default 30, range 10-50)
-	opts      AppOptions
+	mode         AppMode
+	focus        Focus
+	fullView     bool
+	rawView      bool      // true = raw source + line numbers, false = glamour rendering
+	fileDiff     bool      // PR mode: raw view shows the whole file instead of the diff hunks
+	diff         *DiffData // PR mode: the hunks shown, with any expanded context
+	hideResolved bool      // hide resolved PR review threads
+	width        int
+	height       int
+	ready        bool
+	leftRatio    int // left pane width percentage (default 30, range 10-50)
+	opts         AppOptions
 
 	result         AppResult
-	confirmAction  confirmKind // what the confirm dialog is for
-	pendingG       bool        // gg chord: true when first 'g' was pressed
-	editCommentIdx int         // index of comment being edited in comment list mode (-1 = new)
+	confirmAction  confirmKind   // what the confirm dialog is for
+	pendingTop     bool          // Top chord: true when the first key of e.g. "gg" was pressed
+	editCommentIdx int           // index of comment being edited in comment list mode (-1 = new)
+	replyThread    *ReviewThread // thread whose reply is being edited (nil = not replying)
+
+	draftPath    string          // sidecar path for draft comments ("" = drafts disabled)
+	pendingDraft *markdown.Draft // draft awaiting the restore confirmation
+	warnings     []string        // non-fatal problems to report after the TUI exits
 }
 
 // DiffData holds parsed diff information for PR mode display.
@@ -82,15 +100,43 @@ type DiffData struct {
 	LineMap      []int    // maps display index → file line number for commenting
 	SideMap      []string // maps display index → "RIGHT" or "LEFT"
 	TypeMap      []byte   // maps display index → diff line type ('+', '-', ' ')
+	Outside      []bool   // maps display index → line outside the patch, commented on at file level
+
+	// FullFile is the same diff laid over the whole file (nil = hunk view only)
+	FullFile *DiffData
+
+	// Expand returns the hunks with more context in the gap above or below
+	// display index i, or nil if there is none (nil = context is fixed)
+	Expand func(i int, down bool) *DiffData
 }
 
 // AppOptions configures the TUI appearance.
 type AppOptions struct {
-	Theme       string    // "dark" or "light"
+	Theme       Theme     // colour theme; the zero value is the dark theme
+	LeftRatio   int       // initial section list width in percent (0 = default 30, clamped to 10-50)
 	FilePath    string    // file path (displayed in title bar)
 	TrackViewed bool      // persist viewed state to sidecar file
 	PRMode      bool      // PR review mode: changes dialog text and enables diff view
 	Diff        *DiffData // when set, raw view shows diff instead of full source
+
+	// Comments preloads existing review comments (e.g. from a resumed review file).
+	Comments []markdown.ReviewComment
+
+	// Verdicts preloads section verdicts (section ID -> verdict).
+	Verdicts map[string]markdown.Verdict
+
+	// Threads are the PR's existing review threads on this file, shown read-only.
+	Threads []*ReviewThread
+
+	// KeyMap overrides the default key bindings (nil = DefaultKeyMap).
+	KeyMap *KeyMap
+
+	// Policy holds the checks a review must pass before it can be submitted.
+	Policy markdown.Policy
+
+	// SaveDrafts persists in-progress comments to a sidecar file after every change
+	// and offers to restore them on the next session. Ignored in PR mode.
+	SaveDrafts bool
 }
 
 // NewApp creates a new App model.
@@ -105,30 +151,37 @@ func NewApp(doc *markdown.Document, opts AppOptions) *App {
 		sectionList:    NewSectionList(doc, state),
 		comment:        NewCommentEditor(),
 		commentList:    NewCommentList(),
+		threadList:     NewThreadList(),
 		search:         NewSearchBar(),
-		keymap:         DefaultKeyMap(),
+		keymap:         keyMapOrDefault(opts.KeyMap),
 		styles:         styles,
-		leftRatio:      30,
+		leftRatio:      initialLeftRatio(opts.LeftRatio),
 		opts:           opts,
 		editCommentIdx: -1,
 		result: AppResult{
 			Status: markdown.StatusCancelled,
 		},
 	}
+	a.loadComments(opts.Comments)
+	for id, v := range opts.Verdicts {
+		a.sectionList.SetVerdict(id, v)
+	}
+	if opts.SaveDrafts && !opts.PRMode && opts.FilePath != "" {
+		a.draftPath = markdown.DraftPath(opts.FilePath)
+		draft, err := markdown.LoadDraft(a.draftPath)
+		if err != nil {
+			a.warnings = append(a.warnings, err.Error())
+		} else if !draft.IsEmpty() {
+			a.pendingDraft = draft
+			a.confirmAction = confirmRestoreDraft
+			a.mode = ModeConfirm
+		}
+	}
 	if opts.Diff != nil {
 		// PR mode: use diff lines, start in raw view with section filtering
-		a.linePane = NewLinePane(opts.Diff.DisplayLines, 0, 0, styles, doc.AllSections())
-		a.linePane.diffLineMap = opts.Diff.LineMap
-		a.linePane.diffSideMap = opts.Diff.SideMap
-		a.linePane.diffTypeMap = opts.Diff.TypeMap
-		// Recalculate gutter width from max file line number
-		maxLine := 1
-		for _, l := range opts.Diff.LineMap {
-			if l > maxLine {
-				maxLine = l
-			}
-		}
-		a.linePane.gutterWidth = len(fmt.Sprintf("%d", maxLine)) + 1
+		a.diff = opts.Diff
+		a.linePane = NewLinePane(nil, 0, 0, styles, doc.AllSections())
+		a.linePane.setDiff(opts.Diff)
 		a.rawView = true
 	} else if len(doc.SourceLines) > 0 {
 		a.linePane = NewLinePane(doc.SourceLines, 0, 0, styles, doc.AllSections())
@@ -141,6 +194,64 @@ func (a *App) Result() AppResult {
 	return a.result
 }
 
+// Warnings returns non-fatal problems encountered during the session
+// (e.g. draft persistence failures) for the caller to report.
+func (a *App) Warnings() []string {
+	return a.warnings
+}
+
+// loadComments adds comments to the section list.
+func (a *App) loadComments(comments []markdown.ReviewComment) {
+	for i := range comments {
+		c := comments[i]
+		a.sectionList.AddComment(c.SectionID, &c)
+	}
+}
+
+// saveDraft persists the current comments and verdicts to the draft sidecar
+// file, if enabled.
+func (a *App) saveDraft() {
+	if a.draftPath == "" {
+		return
+	}
+	draft := markdown.NewDraft(a.sectionList.BuildReviewResult(), a.doc)
+	if err := markdown.SaveDraft(a.draftPath, draft); err != nil {
+		a.warnings = append(a.warnings, err.Error())
+		a.draftPath = "" // stop retrying on every change
+	}
+}
+
+// restoreDraft loads the pending draft's comments and verdicts into the section list.
+// Comments that could not be re-anchored are flagged as orphaned in the comment list.
+// The draft holds every comment of its session, including those of a resumed
+// review, so comments that are already loaded are not added again.
+func (a *App) restoreDraft() {
+	for _, c := range a.pendingDraft.Restore(a.doc) {
+		if !a.hasComment(c) {
+			a.loadComments([]markdown.ReviewComment{c})
+		}
+	}
+	for id, v := range a.pendingDraft.RestoreVerdicts(a.doc) {
+		a.sectionList.SetVerdict(id, v)
+	}
+	a.pendingDraft = nil
+	a.saveDraft()
+}
+
+// hasComment reports whether a comment with the same lines, label and text
+// as c is already loaded.
+func (a *App) hasComment(c markdown.ReviewComment) bool {
+	for _, e := range a.sectionList.GetComments(c.SectionID) {
+		if e.StartLine == c.StartLine && e.EndLine == c.EndLine && e.Side == c.Side &&
+			e.Action == c.Action && e.Decoration == c.Decoration && e.Body == c.Body &&
+			(e.Suggestion == nil) == (c.Suggestion == nil) &&
+			(e.Suggestion == nil || slices.Equal(e.Suggestion.Replacement, c.Suggestion.Replacement)) {
+			return true
+		}
+	}
+	return false
+}
+
 // isRawMode returns true when raw source view is active.
 func (a *App) isRawMode() bool {
 	return a.rawView && a.linePane != nil
@@ -200,49 +311,64 @@ func (a *App) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return a.handleSearchMode(msg)
 	case ModeLineSelect:
 		return a.handleLineSelectMode(msg)
+	case ModeThreadList:
+		return a.handleThreadListMode(msg)
 	}
 	return a, nil
 }
 
+// goToTop moves the cursor of the focused pane to the first item.
+func (a *App) goToTop() {
+	switch {
+	case a.focus == FocusLeft:
+		a.sectionList.CursorTop()
+		a.refreshAfterCursorMove()
+	case a.isRawMode():
+		a.linePane.CursorTop()
+		a.syncSectionFromLineCursor()
+	default:
+		a.detail.Viewport().GotoTop()
+		a.syncCursorToScroll()
+	}
+}
+
+// goToBottom moves the cursor of the focused pane to the last item.
+func (a *App) goToBottom() {
+	switch {
+	case a.focus == FocusLeft:
+		a.sectionList.CursorBottom()
+		a.refreshAfterCursorMove()
+	case a.isRawMode():
+		a.linePane.CursorBottom()
+		a.syncSectionFromLineCursor()
+	default:
+		a.detail.Viewport().GotoBottom()
+		a.syncCursorToScroll()
+	}
+}
+
 func (a *App) handleNormalMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	// Handle 'gg' chord (second g after pending g)
-	if a.pendingG {
-		a.pendingG = false
-		if msg.String() == "g" {
-			switch {
-			case a.focus == FocusLeft:
-				a.sectionList.CursorTop()
-				a.refreshAfterCursorMove()
-			case a.isRawMode():
-				a.linePane.CursorTop()
-				a.syncSectionFromLineCursor()
-			default:
-				a.detail.Viewport().GotoTop()
-				a.syncCursorToScroll()
-			}
+	// A single-character Top key is a chord (e.g. "gg"): the first press only arms it.
+	if a.pendingTop {
+		a.pendingTop = false
+		if key.Matches(msg, a.keymap.Top) {
+			a.goToTop()
 			return a, nil
 		}
-		// Not 'g' after pending g -- fall through to normal handling
+		// Not a second Top key -- fall through to normal handling
 	}
 
-	// Check for 'g' (start of gg chord) and 'G' (go to bottom)
-	switch msg.String() {
-	case "g":
-		a.pendingG = true
-		return a, nil
-	case "G":
-		switch {
-		case a.focus == FocusLeft:
-			a.sectionList.CursorBottom()
-			a.refreshAfterCursorMove()
-		case a.isRawMode():
-			a.linePane.CursorBottom()
-			a.syncSectionFromLineCursor()
-		default:
-			a.detail.Viewport().GotoBottom()
-			a.syncCursorToScroll()
+	switch {
+	case key.Matches(msg, a.keymap.Top):
+		if isPrintableKey(msg.String()) {
+			a.pendingTop = true
+		} else {
+			a.goToTop()
 		}
 		return a, nil
+	case key.Matches(msg, a.keymap.Bottom):
+		a.goToBottom()
+		return a, nil
 	}
 
 	switch {
@@ -288,6 +414,24 @@ func (a *App) handleNormalMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		a.refreshDetail()
 		return a, nil
 
+	case key.Matches(msg, a.keymap.HideResolved):
+		if len(a.opts.Threads) > 0 {
+			a.hideResolved = !a.hideResolved
+			a.refreshDetail()
+			return a, nil
+		}
+
+	case key.Matches(msg, a.keymap.DiffView):
+		if a.opts.Diff != nil && a.opts.Diff.FullFile != nil {
+			a.fileDiff = !a.fileDiff
+			if a.fileDiff {
+				a.showDiff(a.opts.Diff.FullFile, 0)
+			} else {
+				a.showDiff(a.diff, 0)
+			}
+			return a, nil
+		}
+
 	case key.Matches(msg, a.keymap.RawView):
 		if a.linePane != nil {
 			a.rawView = !a.rawView
@@ -419,11 +563,20 @@ func (a *App) handleLeftPaneKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			}
 		}
 
+	case key.Matches(msg, a.keymap.ThreadList):
+		a.openThreadList(a.selectedSectionID())
+
 	case key.Matches(msg, a.keymap.Viewed):
 		if section := a.sectionList.Selected(); section != nil {
 			a.sectionList.ToggleViewed(section.ID)
 		}
 
+	case key.Matches(msg, a.keymap.Verdict):
+		if section := a.sectionList.Selected(); section != nil {
+			a.sectionList.CycleVerdict(section.ID)
+			a.saveDraft()
+		}
+
 	case key.Matches(msg, a.keymap.Search):
 		cmd := a.search.Open()
 		a.mode = ModeSearch
@@ -444,6 +597,8 @@ func (a *App) handleRightPaneKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case key.Matches(msg, a.keymap.Down):
 		a.detail.Viewport().ScrollDown(1)
 		a.syncCursorToScroll()
+	case key.Matches(msg, a.keymap.ThreadList):
+		a.openThreadList(a.selectedSectionID())
 	}
 	return a, nil
 }
@@ -474,16 +629,28 @@ func (a *App) handleLinePaneKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			a.linePane.CursorDown()
 			a.syncSectionFromLineCursor()
 		}
+	case key.Matches(msg, a.keymap.ExpandUp):
+		a.expandDiff(false)
+	case key.Matches(msg, a.keymap.ExpandDown):
+		a.expandDiff(true)
 	case key.Matches(msg, a.keymap.Comment):
 		if !a.linePane.CanComment() {
 			return a, nil
 		}
 		startLine, endLine := a.linePane.SelectedRange()
+		if a.linePane.SelectionOutsideDiff() {
+			return a, a.openFileLevelComment(startLine, endLine)
+		}
 		sectionID := a.linePane.SectionIDAtLine(startLine)
 		a.editCommentIdx = -1
 		cmd := a.comment.OpenWithLines(sectionID, nil, startLine, endLine, a.linePane.CursorSide())
 		a.mode = ModeComment
 		return a, cmd
+	case key.Matches(msg, a.keymap.Suggest):
+		if !a.linePane.CanComment() {
+			return a, nil
+		}
+		return a, a.openSuggestion()
 	case key.Matches(msg, a.keymap.VisualSelect):
 		a.linePane.StartVisualSelect()
 		a.mode = ModeLineSelect
@@ -494,6 +661,8 @@ func (a *App) handleLinePaneKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			a.commentList.Open(sectionID, comments)
 			a.mode = ModeCommentList
 		}
+	case key.Matches(msg, a.keymap.ThreadList):
+		a.openThreadList(a.linePane.SectionIDAtLine(a.linePane.Cursor() + 1))
 	}
 	return a, nil
 }
@@ -511,12 +680,17 @@ func (a *App) handleLineSelectMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if startLine == 0 {
 			return a, nil // no valid diff line selected
 		}
+		if a.linePane.SelectionOutsideDiff() {
+			return a, a.openFileLevelComment(startLine, endLine)
+		}
 		sectionID := a.linePane.SectionIDAtLine(startLine)
 		a.linePane.CancelVisualSelect()
 		a.editCommentIdx = -1
 		cmd := a.comment.OpenWithLines(sectionID, nil, startLine, endLine, a.linePane.CursorSide())
 		a.mode = ModeComment
 		return a, cmd
+	case key.Matches(msg, a.keymap.Suggest):
+		return a, a.openSuggestion()
 	case key.Matches(msg, a.keymap.Cancel):
 		a.linePane.CancelVisualSelect()
 		a.mode = ModeNormal
@@ -524,6 +698,75 @@ func (a *App) handleLineSelectMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return a, nil
 }
 
+// showDiff shows d in the line pane, limited to the selected section unless
+// in full view, with the cursor on display index cursor.
+func (a *App) showDiff(d *DiffData, cursor int) {
+	a.linePane.setDiff(d)
+	a.updateLinePaneViewRange()
+	a.linePane.setCursor(cursor)
+	a.refreshDetail()
+}
+
+// expandDiff shows more context in the gap above or below the cursor line of
+// the hunk view, keeping the cursor on its line.
+func (a *App) expandDiff(down bool) {
+	if a.fileDiff || a.diff == nil || a.diff.Expand == nil {
+		return
+	}
+	cursor := a.linePane.Cursor()
+	expanded := a.diff.Expand(cursor, down)
+	if expanded == nil {
+		return
+	}
+	if !down {
+		// The lines went in above the cursor
+		cursor += len(expanded.DisplayLines) - len(a.diff.DisplayLines)
+	}
+	a.diff = expanded
+	a.showDiff(expanded, cursor)
+}
+
+// openFileLevelComment opens the comment editor for lines outside the PR
+// patch, which GitHub takes no line comments on: the comment is added to the
+// overview, which is posted as a file-level comment, and starts with the
+// line reference.
+func (a *App) openFileLevelComment(startLine, endLine int) tea.Cmd {
+	a.linePane.CancelVisualSelect()
+	a.editCommentIdx = -1
+	a.mode = ModeComment
+	return a.comment.OpenWithBody(markdown.OverviewSectionID, markdown.FormatLineRef(startLine, endLine)+": ")
+}
+
+// openSuggestion opens the comment editor with a suggested change for the
+// selected lines, pre-filled with their current content. Lines removed in a
+// PR diff cannot be changed, so nothing happens on the LEFT side.
+func (a *App) openSuggestion() tea.Cmd {
+	startLine, endLine := a.linePane.SelectedRange()
+	side := a.linePane.CursorSide()
+	if startLine == 0 || side == "LEFT" || a.linePane.SelectionOutsideDiff() {
+		return nil
+	}
+	lines := sourceRange(a.doc.SourceLines, startLine, endLine)
+	if lines == nil {
+		return nil
+	}
+	sectionID := a.linePane.SectionIDAtLine(startLine)
+	a.linePane.CancelVisualSelect()
+	a.editCommentIdx = -1
+	a.mode = ModeComment
+	return a.comment.OpenSuggestion(sectionID, startLine, endLine, side, lines)
+}
+
+// sourceRange returns the 1-based inclusive line range of lines (endLine 0 =
+// single line), or nil if it is out of bounds.
+func sourceRange(lines []string, startLine, endLine int) []string {
+	endLine = max(endLine, startLine)
+	if startLine < 1 || endLine > len(lines) {
+		return nil
+	}
+	return lines[startLine-1 : endLine]
+}
+
 // syncSectionFromLineCursor updates the left pane cursor to match the section
 // containing the current line cursor position.
 func (a *App) syncSectionFromLineCursor() {
@@ -537,15 +780,27 @@ func (a *App) syncSectionFromLineCursor() {
 }
 
 func (a *App) handleCommentMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if a.replyThread != nil {
+		return a.handleReplyMode(msg)
+	}
 	switch {
 	case key.Matches(msg, a.keymap.Save):
 		result := a.comment.Result()
 		if result != nil {
+			// Fingerprint the commented lines so the comment can follow them if the file changes
+			if result.StartLine > 0 && !result.Orphaned && result.Side != "LEFT" {
+				result.Anchor = markdown.NewLineAnchor(a.doc.SourceLines, result.StartLine, result.EndLine)
+			}
+			// A suggestion block typed into a plain line comment replaces the commented lines as they are now
+			if s := result.Suggestion; s != nil && s.Original == nil {
+				s.Original = slices.Clone(sourceRange(a.doc.SourceLines, result.StartLine, result.EndLine))
+			}
 			if a.editCommentIdx >= 0 {
 				a.sectionList.UpdateComment(a.comment.SectionID(), a.editCommentIdx, result)
 			} else {
 				a.sectionList.AddComment(a.comment.SectionID(), result)
 			}
+			a.saveDraft()
 		}
 		a.returnFromComment()
 		a.refreshDetail()
@@ -555,15 +810,15 @@ func (a *App) handleCommentMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		a.returnFromComment()
 		return a, nil
 
-	case msg.Type == tea.KeyCtrlD:
+	case key.Matches(msg, a.keymap.CycleDecoration):
 		a.comment.CycleDecoration()
 		return a, nil
 
-	case msg.Type == tea.KeyShiftTab:
+	case key.Matches(msg, a.keymap.CycleLabelReverse):
 		a.comment.CycleLabelReverse()
 		return a, nil
 
-	case msg.Type == tea.KeyTab:
+	case key.Matches(msg, a.keymap.CycleLabel):
 		a.comment.CycleLabel()
 		return a, nil
 	}
@@ -585,8 +840,76 @@ func (a *App) returnFromComment() {
 	a.editCommentIdx = -1
 }
 
+// handleReplyMode handles keys while editing the reply to a review thread.
+// Saving an empty reply removes the queued one.
+func (a *App) handleReplyMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, a.keymap.Save):
+		a.replyThread.Reply = a.comment.Text()
+		a.returnFromReply()
+		return a, nil
+	case key.Matches(msg, a.keymap.Cancel):
+		a.returnFromReply()
+		return a, nil
+	case key.Matches(msg, a.keymap.CycleDecoration, a.keymap.CycleLabelReverse, a.keymap.CycleLabel):
+		return a, nil // replies have no label
+	}
+	cmd := a.comment.Update(msg)
+	return a, cmd
+}
+
+// returnFromReply closes the reply editor and returns to the thread list.
+func (a *App) returnFromReply() {
+	a.comment.Close()
+	a.replyThread = nil
+	a.mode = ModeThreadList
+}
+
+// openThreadList opens the thread list for the visible threads of a section.
+// Does nothing if the section has none.
+func (a *App) openThreadList(sectionID string) {
+	if sectionID == "" {
+		return
+	}
+	threads := groupThreads(a.doc, a.visibleThreads())[sectionID]
+	if len(threads) == 0 {
+		return
+	}
+	a.threadList.Open(sectionID, threads)
+	a.mode = ModeThreadList
+}
+
+func (a *App) handleThreadListMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	thread := a.threadList.Selected()
+	switch {
+	case key.Matches(msg, a.keymap.Cancel):
+		a.threadList.Close()
+		a.mode = ModeNormal
+		a.refreshDetail()
+	case key.Matches(msg, a.keymap.Up):
+		a.threadList.CursorUp()
+	case key.Matches(msg, a.keymap.Down):
+		a.threadList.CursorDown()
+	case key.Matches(msg, a.keymap.Reply):
+		if thread != nil {
+			a.replyThread = thread
+			a.mode = ModeComment
+			return a, a.comment.OpenReply(thread.Reply)
+		}
+	case key.Matches(msg, a.keymap.Resolve):
+		if thread != nil {
+			thread.toggleResolve = !thread.toggleResolve
+		}
+	case key.Matches(msg, a.keymap.Delete):
+		if thread != nil {
+			thread.Reply = ""
+		}
+	}
+	return a, nil
+}
+
 func (a *App) handleCommentListMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	if msg.Type == tea.KeyEsc {
+	if key.Matches(msg, a.keymap.Cancel) {
 		a.commentList.Close()
 		a.mode = ModeNormal
 		a.refreshDetail()
@@ -619,6 +942,7 @@ func (a *App) handleCommentListMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		sectionID := a.commentList.SectionID()
 		idx := a.commentList.Cursor()
 		a.sectionList.DeleteComment(sectionID, idx)
+		a.saveDraft()
 		comments := a.sectionList.GetComments(sectionID)
 		if len(comments) == 0 {
 			a.commentList.Close()
@@ -638,17 +962,34 @@ func (a *App) handleConfirmMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "y", "Y":
 		switch a.confirmAction {
 		case confirmSubmit:
+			if p := a.checkPolicy(); p != nil && !p.Passed() {
+				return a, nil // the dialog explains why; only esc/n close it
+			}
 			return a.submitReview()
 		case confirmQuit:
 			a.result.Status = markdown.StatusCancelled
 			return a, tea.Quit
+		case confirmRestoreDraft:
+			a.restoreDraft()
+			a.mode = ModeNormal
+			a.refreshDetail()
+			return a, nil
 		}
 	case "n", "N":
+		if a.confirmAction == confirmRestoreDraft {
+			a.pendingDraft = nil
+			a.saveDraft() // discard the old draft
+		}
 		a.mode = ModeNormal
 		return a, nil
 	}
 	switch msg.Type {
 	case tea.KeyEsc:
+		if a.confirmAction == confirmRestoreDraft {
+			// The draft must be restored or discarded before any change
+			// can overwrite it.
+			return a, nil
+		}
 		a.mode = ModeNormal
 		return a, nil
 	case tea.KeyCtrlC:
@@ -712,17 +1053,40 @@ func (a *App) handleSearchMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 func (a *App) submitReview() (tea.Model, tea.Cmd) {
 	review := a.sectionList.BuildReviewResult()
+	review.Policy = a.checkPolicy()
+	review.Comments = markdown.DetachOrphans(review.Comments)
 
-	if len(review.Comments) == 0 {
+	if review.IsEmpty() {
 		a.result.Status = markdown.StatusApproved
 	} else {
 		a.result.Status = markdown.StatusSubmitted
 	}
 	a.result.Review = review
+	a.result.Viewed = a.sectionList.ViewedCount()
+	a.result.Sections = a.sectionList.TotalSectionCount()
+	a.result.Replies, a.result.Resolutions = threadChanges(a.opts.Threads)
+
+	if a.draftPath != "" {
+		if err := markdown.RemoveDraft(a.draftPath); err != nil {
+			a.warnings = append(a.warnings, err.Error())
+		}
+	}
 
 	return a, tea.Quit
 }
 
+// checkPolicy evaluates the submit policy against the current review, or
+// returns nil if no policy is enabled. In PR mode the approval is chosen
+// after every file is reviewed, so only the viewed threshold applies here.
+func (a *App) checkPolicy() *markdown.PolicyResult {
+	if !a.opts.Policy.Enabled() {
+		return nil
+	}
+	review := a.sectionList.BuildReviewResult()
+	approving := !a.opts.PRMode && review.Approves()
+	return a.opts.Policy.Check(review.Comments, a.sectionList.ViewedCount(), a.sectionList.TotalSectionCount(), approving)
+}
+
 func (a *App) syncCursorToScroll() {
 	if a.isRawMode() {
 		a.syncSectionFromLineCursor()
@@ -783,6 +1147,7 @@ func (a *App) refreshDetail() {
 	if a.detail == nil {
 		return
 	}
+	a.detail.SetThreads(groupThreads(a.doc, a.visibleThreads()))
 
 	if a.fullView {
 		a.detail.ShowAll(a.doc, a.sectionList.GetComments)
@@ -814,6 +1179,35 @@ func (a *App) refreshLinePane() {
 		allComments = append(allComments, a.sectionList.GetComments(s.ID)...)
 	}
 	a.linePane.SetComments(allComments)
+	a.linePane.SetThreads(a.visibleThreads())
+}
+
+// visibleThreads returns the PR review threads to display, without the
+// resolved ones when they are hidden.
+func (a *App) visibleThreads() []*ReviewThread {
+	if !a.hideResolved {
+		return a.opts.Threads
+	}
+	var threads []*ReviewThread
+	for _, t := range a.opts.Threads {
+		if !t.Resolved {
+			threads = append(threads, t)
+		}
+	}
+	return threads
+}
+
+// threadProgress returns the status bar thread count, e.g.
+// " [3 threads, 1 resolved hidden]", or "" if the PR has no threads on this file.
+func (a *App) threadProgress() string {
+	total := len(a.opts.Threads)
+	if total == 0 {
+		return ""
+	}
+	if hidden := total - len(a.visibleThreads()); hidden > 0 {
+		return fmt.Sprintf(" [%d threads, %d resolved hidden]", total, hidden)
+	}
+	return fmt.Sprintf(" [%d threads]", total)
 }
 
 // updateLinePaneViewRange sets the linePane view range based on fullView and selected section.
@@ -901,6 +1295,22 @@ func (a *App) contentHeightWith(tbHeight int) int {
 	return max(a.height-tbHeight-3, 4)
 }
 
+// keyMapOrDefault returns *km, or the default key map if km is nil.
+func keyMapOrDefault(km *KeyMap) KeyMap {
+	if km == nil {
+		return DefaultKeyMap()
+	}
+	return *km
+}
+
+// initialLeftRatio returns the starting left pane ratio for the given option value.
+func initialLeftRatio(ratio int) int {
+	if ratio == 0 {
+		return 30
+	}
+	return min(max(ratio, 10), 50)
+}
+
 func (a *App) resizeLeftPane(delta int) {
 	newRatio := a.leftRatio + delta
 	if a.width < 80 || newRatio < 10 || newRatio > 50 {
@@ -1024,6 +1434,12 @@ func (a *App) renderRightContent(width, height int) string {
 		if ref := a.comment.FormatLineRef(); ref != "" {
 			commentLabel += " (" + ref + ")"
 		}
+		if a.replyThread != nil {
+			commentLabel = "Reply"
+			if a.replyThread.Line > 0 {
+				commentLabel += fmt.Sprintf(" (L%d)", a.replyThread.Line)
+			}
+		}
 		separator := a.styles.CommentBorder.Width(width - 2).Render(commentLabel)
 		commentView := a.comment.View()
 
@@ -1045,6 +1461,9 @@ func (a *App) renderRightContent(width, height int) string {
 	case ModeCommentList:
 		return a.commentList.Render(width, height, a.styles)
 
+	case ModeThreadList:
+		return a.threadList.Render(width, height, a.styles)
+
 	case ModeLineSelect:
 		if a.linePane != nil {
 			a.linePane.SetSize(width, height)
@@ -1066,22 +1485,39 @@ func (a *App) statusEntry(key, label string) string {
 }
 
 func (a *App) renderStatusBar() string {
+	km := a.keymap
+	if a.mode == ModeComment && a.replyThread != nil {
+		return a.styles.StatusBar.Render(
+			a.statusEntry(keyHint(km.Save), "save reply") + "  " +
+				a.statusEntry(keyHint(km.Cancel), "cancel"),
+		)
+	}
 	if a.mode == ModeComment {
 		return a.styles.StatusBar.Render(
-			a.statusEntry("tab/S-tab", "label:") + " " +
-				a.styles.Title.Render(a.comment.FormatLabel()) + "  " +
-				a.statusEntry("ctrl+d", "deco") + "  " +
-				a.statusEntry("ctrl+s", "save") + "  " +
-				a.statusEntry("esc", "cancel"),
+			a.statusEntry(keyHint(km.CycleLabel, km.CycleLabelReverse), "label:") + " " +
+				labelStyle(a.styles.Title, a.comment.Label()).Render(a.comment.FormatLabel()) + "  " +
+				a.statusEntry(keyHint(km.CycleDecoration), "deco") + "  " +
+				a.statusEntry(keyHint(km.Save), "save") + "  " +
+				a.statusEntry(keyHint(km.Cancel), "cancel"),
 		)
 	}
 
 	if a.mode == ModeCommentList {
 		return a.styles.StatusBar.Render(
-			a.statusEntry("j/k", "navigate") + "  " +
-				a.statusEntry("e", "edit") + "  " +
-				a.statusEntry("d", "delete") + "  " +
-				a.statusEntry("esc", "back"),
+			a.statusEntry(keyHint(km.Down, km.Up), "navigate") + "  " +
+				a.statusEntry(keyHint(km.Edit), "edit") + "  " +
+				a.statusEntry(keyHint(km.Delete), "delete") + "  " +
+				a.statusEntry(keyHint(km.Cancel), "back"),
+		)
+	}
+
+	if a.mode == ModeThreadList {
+		return a.styles.StatusBar.Render(
+			a.statusEntry(keyHint(km.Down, km.Up), "navigate") + "  " +
+				a.statusEntry(keyHint(km.Reply), "reply") + "  " +
+				a.statusEntry(keyHint(km.Resolve), "resolve/unresolve") + "  " +
+				a.statusEntry(keyHint(km.Delete), "drop reply") + "  " +
+				a.statusEntry(keyHint(km.Cancel), "back"),
 		)
 	}
 
@@ -1090,12 +1526,15 @@ func (a *App) renderStatusBar() string {
 		if a.linePane != nil {
 			startLine, endLine := a.linePane.SelectedRange()
 			lineInfo = markdown.FormatLineRef(startLine, endLine)
+			if a.linePane.SelectionOutsideDiff() {
+				lineInfo += " (file-level)"
+			}
 		}
 		return a.styles.StatusBar.Render(
 			a.styles.Title.Render("VISUAL") + "  " +
-				a.statusEntry("j/k", "extend") + "  " +
-				a.statusEntry("c", "comment") + "  " +
-				a.statusEntry("esc", "cancel") + "  " +
+				a.statusEntry(keyHint(km.Down, km.Up), "extend") + "  " +
+				a.statusEntry(keyHint(km.Comment), "comment") + "  " +
+				a.statusEntry(keyHint(km.Cancel), "cancel") + "  " +
 				lineInfo,
 		)
 	}
@@ -1106,10 +1545,23 @@ func (a *App) renderStatusBar() string {
 
 	if a.isRawMode() {
 		lineInfo := fmt.Sprintf("L%d/%d", a.linePane.Cursor()+1, a.linePane.LineCount())
+		if a.linePane.SelectionOutsideDiff() {
+			lineInfo += " (file-level)"
+		}
+		diffToggle := ""
+		if a.opts.Diff != nil && a.opts.Diff.FullFile != nil {
+			// Label shows the view that the key will switch TO
+			diffMode := "file"
+			if a.fileDiff {
+				diffMode = "hunks"
+			}
+			diffToggle = a.statusEntry(keyHint(km.DiffView), diffMode) + "  "
+		}
 		progress := ""
 		if commentCount := a.sectionList.TotalCommentCount(); commentCount > 0 {
 			progress = fmt.Sprintf(" [%d comments]", commentCount)
 		}
+		progress += a.verdictProgress() + a.threadProgress()
 		// Label shows the mode that f will switch TO (not the current mode)
 		viewMode := "full"
 		if a.fullView {
@@ -1117,15 +1569,16 @@ func (a *App) renderStatusBar() string {
 		}
 
 		return a.styles.StatusBar.Render(
-			a.statusEntry("r", "render") + "  " +
-				a.statusEntry("f", viewMode) + "  " +
-				a.statusEntry("c", "comment") + "  " +
-				a.statusEntry("V", "select") + "  " +
-				a.statusEntry("C", "comments") + "  " +
-				a.statusEntry("s", "submit") + "  " +
-				a.statusEntry("tab", "switch") + "  " +
-				a.statusEntry("?", "help") + "  " +
-				a.statusEntry("q", "quit") + "  " +
+			a.statusEntry(keyHint(km.RawView), "render") + "  " +
+				a.statusEntry(keyHint(km.FullView), viewMode) + "  " +
+				diffToggle +
+				a.statusEntry(keyHint(km.Comment), "comment") + "  " +
+				a.statusEntry(keyHint(km.VisualSelect), "select") + "  " +
+				a.statusEntry(keyHint(km.CommentList), "comments") + "  " +
+				a.statusEntry(keyHint(km.Submit), "submit") + "  " +
+				a.statusEntry(keyHint(km.Tab), "switch") + "  " +
+				a.statusEntry(keyHint(km.Help), "help") + "  " +
+				a.statusEntry(keyHint(km.Quit), "quit") + "  " +
 				lineInfo + progress,
 		)
 	}
@@ -1140,38 +1593,77 @@ func (a *App) renderStatusBar() string {
 	if commentCount := a.sectionList.TotalCommentCount(); commentCount > 0 {
 		progress += fmt.Sprintf(" [%d comments]", commentCount)
 	}
+	progress += a.verdictProgress() + a.threadProgress()
 
 	rawToggle := ""
 	if a.linePane != nil {
-		rawToggle = a.statusEntry("r", "raw") + "  "
+		rawToggle = a.statusEntry(keyHint(km.RawView), "raw") + "  "
 	}
 
 	return a.styles.StatusBar.Render(
-		a.statusEntry("enter", "toggle") + "  " +
-			a.statusEntry("f", viewMode) + "  " +
+		a.statusEntry(keyHint(km.Toggle), "toggle") + "  " +
+			a.statusEntry(keyHint(km.FullView), viewMode) + "  " +
 			rawToggle +
-			a.statusEntry("c", "comment") + "  " +
-			a.statusEntry("C", "comments") + "  " +
-			a.statusEntry("v", "viewed") + "  " +
-			a.statusEntry("/", "search") + "  " +
-			a.statusEntry("s", "submit") + "  " +
-			a.statusEntry("tab", "switch") + "  " +
-			a.statusEntry("?", "help") + "  " +
-			a.statusEntry("q", "quit") + "  " +
+			a.statusEntry(keyHint(km.Comment), "comment") + "  " +
+			a.statusEntry(keyHint(km.CommentList), "comments") + "  " +
+			a.statusEntry(keyHint(km.Viewed), "viewed") + "  " +
+			a.statusEntry(keyHint(km.Search), "search") + "  " +
+			a.statusEntry(keyHint(km.Submit), "submit") + "  " +
+			a.statusEntry(keyHint(km.Tab), "switch") + "  " +
+			a.statusEntry(keyHint(km.Help), "help") + "  " +
+			a.statusEntry(keyHint(km.Quit), "quit") + "  " +
 			progress,
 	)
 }
 
+// verdictProgress returns the status bar verdict counts, e.g.
+// " [2 approved, 1 rejected]", or "" if no section has a verdict.
+func (a *App) verdictProgress() string {
+	var parts []string
+	for _, v := range markdown.Verdicts {
+		if n := a.sectionList.VerdictCount(v); n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", n, verdictStatusLabels[v]))
+		}
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return " [" + strings.Join(parts, ", ") + "]"
+}
+
+// verdictStatusLabels are the status bar labels of the verdict counts.
+var verdictStatusLabels = map[markdown.Verdict]string{
+	markdown.VerdictApprove:      "approved",
+	markdown.VerdictNeedsChanges: "needs changes",
+	markdown.VerdictReject:       "rejected",
+}
+
 // renderConfirm renders a full-screen confirmation dialog.
 func (a *App) renderConfirm() string {
 	var message string
+	keys := a.styles.StatusKey.Render("y") + " yes   " +
+		a.styles.StatusKey.Render("n") + " no   " +
+		a.styles.StatusKey.Render("esc") + " cancel"
 	switch a.confirmAction {
 	case confirmSubmit:
+		if p := a.checkPolicy(); p != nil && !p.Passed() {
+			message = "Cannot submit yet:\n"
+			for _, f := range p.Failures() {
+				message += "\n- " + f
+			}
+			keys = a.styles.StatusKey.Render("esc") + " back"
+			break
+		}
 		if a.opts.PRMode {
 			message = fmt.Sprintf("Finish reviewing this file? (%d comments)", a.sectionList.TotalCommentCount())
 		} else {
 			message = fmt.Sprintf("Submit review? (%d comments)", a.sectionList.TotalCommentCount())
 		}
+	case confirmRestoreDraft:
+		message = fmt.Sprintf("Found %d unsaved draft comment(s) and %d verdict(s) from a previous session.\n\nRestore them?",
+			len(a.pendingDraft.Comments), len(a.pendingDraft.Verdicts))
+		keys = a.styles.StatusKey.Render("y") + " restore   " +
+			a.styles.StatusKey.Render("n") + " discard"
 	case confirmQuit:
 		switch {
 		case a.opts.PRMode:
@@ -1189,10 +1681,7 @@ func (a *App) renderConfirm() string {
 		Border(lipgloss.RoundedBorder()).
 		BorderForeground(lipgloss.Color("170")).
 		Render(
-			message + "\n\n" +
-				a.styles.StatusKey.Render("y") + " yes   " +
-				a.styles.StatusKey.Render("n") + " no   " +
-				a.styles.StatusKey.Render("esc") + " cancel",
+			message + "\n\n" + keys,
 		)
 
 	return lipgloss.Place(a.width, a.height, lipgloss.Center, lipgloss.Center, dialog)
@@ -1215,54 +1704,90 @@ func clipLines(s string, maxLines int) string {
 }
 
 func (a *App) renderHelp() string {
-	rawViewHelp := ""
+	km := a.keymap
+	var sb strings.Builder
+	section := func(title string) {
+		fmt.Fprintf(&sb, "\n  %s:\n", title)
+	}
+	line := func(keys, desc string) {
+		fmt.Fprintf(&sb, "    %-15s %s\n", keys, desc)
+	}
+
+	sb.WriteString(a.styles.Title.Render("commd - Help") + "\n")
+
+	section("Navigation")
+	line(helpKeys(km.Down, km.Up), "Move cursor down/up")
+	if isPrintableKey(keyHint(km.Top)) {
+		line(keyHint(km.Top)+keyHint(km.Top), "Go to top")
+	} else {
+		line(helpKeys(km.Top), "Go to top")
+	}
+	line(helpKeys(km.Bottom), "Go to bottom")
+	line(helpKeys(km.Toggle), "Toggle expand/collapse")
+	line(helpKeys(km.FullView), "Toggle full/section view")
+	line(helpKeys(km.RawView), "Toggle raw source/rendered view")
+	line(helpKeys(km.ScrollLeft, km.ScrollRight), "Scroll detail pane left/right")
+	line(helpKeys(km.ScrollToStart, km.ScrollToEnd), "Scroll detail to start/end")
+	line(helpKeys(km.HalfPageDown, km.HalfPageUp), "Half page down/up")
+	line(helpKeys(km.PageDown, km.PageUp), "Full page down/up")
+	line(helpKeys(km.PaneGrow, km.PaneShrink), "Resize left pane")
+	line(helpKeys(km.Tab), "Switch between left/right pane")
+
+	section("Review")
+	line(helpKeys(km.Comment), "Add comment on selected section")
+	line(helpKeys(km.CommentList), "Manage comments (edit/delete)")
+	line(helpKeys(km.Viewed), "Toggle viewed mark")
+	line(helpKeys(km.Verdict), "Cycle section verdict: approve [+], needs changes [~], reject [✗], none")
+	line(helpKeys(km.Search), "Search sections")
+	line(helpKeys(km.Submit), "Submit review")
+	if len(a.opts.Threads) > 0 {
+		line(helpKeys(km.ThreadList), "Reply to or resolve review threads of the section")
+		line(helpKeys(km.HideResolved), "Show/hide resolved review threads")
+	}
+
 	if a.linePane != nil {
-		rawViewHelp = `
-  Raw Source View (r to toggle):
-    j/k             Move line cursor
-    c               Add line comment at cursor
-    V               Start visual line selection
-    V + j/k + c     Comment on selected range
-    Esc             Cancel visual selection
-    C               Manage comments for section at cursor
-`
-	}
-	help := fmt.Sprintf(`%s
-
-  Navigation:
-    j/k, Up/Down   Move cursor up/down
-    gg              Go to top
-    G               Go to bottom
-    Enter           Toggle expand/collapse
-    f               Toggle full/section view
-    r               Toggle raw source/rendered view
-    h/l, Left/Right Scroll detail pane left/right
-    H/L             Scroll detail to start/end
-    Ctrl+D/Ctrl+U   Half page down/up
-    Ctrl+F/Ctrl+B   Full page down/up
-    >/<             Resize left pane
-    Tab             Switch between left/right pane
-
-  Review:
-    c               Add comment on selected section
-    C               Manage comments (edit/delete)
-    v               Toggle viewed mark
-    /               Search sections
-    s               Submit review
-%s
-  Comment Editor:
-    Tab             Cycle label (forward)
-    Shift+Tab       Cycle label (reverse)
-    Ctrl+D          Cycle decoration
-    Ctrl+S          Save comment
-    Esc             Cancel editing
-
-  Other:
-    ?               Toggle this help
-    q, Ctrl+C       Quit
-
-  Press Esc or ? or q to close this help.
-`, a.styles.Title.Render("commd - Help"), rawViewHelp)
+		section(fmt.Sprintf("Raw Source View (%s to toggle)", keyHint(km.RawView)))
+		line(helpKeys(km.Down, km.Up), "Move line cursor")
+		line(helpKeys(km.Comment), "Add line comment at cursor")
+		line(helpKeys(km.VisualSelect), "Start visual line selection")
+		line(fmt.Sprintf("%s + %s + %s", keyHint(km.VisualSelect), keyHint(km.Down, km.Up), keyHint(km.Comment)), "Comment on selected range")
+		line(helpKeys(km.Suggest), "Suggest a change to the selected line(s)")
+		line(helpKeys(km.Cancel), "Cancel visual selection")
+		line(helpKeys(km.CommentList), "Manage comments for section at cursor")
+		if a.opts.Diff != nil && a.opts.Diff.FullFile != nil {
+			line(helpKeys(km.DiffView), "Toggle diff hunks/whole file (comments outside the diff are file-level)")
+		}
+		if a.diff != nil && a.diff.Expand != nil {
+			line(helpKeys(km.ExpandUp)+" / "+helpKeys(km.ExpandDown), "Expand context above / below the cursor line (hunk view)")
+		}
+	}
+
+	section("Comment List")
+	line(helpKeys(km.Edit), "Edit selected comment")
+	line(helpKeys(km.Delete), "Delete selected comment")
+	line(helpKeys(km.Cancel), "Back")
+
+	if len(a.opts.Threads) > 0 {
+		section("Thread List")
+		line(helpKeys(km.Reply), "Reply to selected thread (posted with the review)")
+		line(helpKeys(km.Resolve), "Resolve/unresolve selected thread")
+		line(helpKeys(km.Delete), "Drop queued reply")
+		line(helpKeys(km.Cancel), "Back")
+	}
+
+	section("Comment Editor")
+	line(helpKeys(km.CycleLabel), "Cycle label (forward)")
+	line(helpKeys(km.CycleLabelReverse), "Cycle label (reverse)")
+	line(helpKeys(km.CycleDecoration), "Cycle decoration")
+	line(helpKeys(km.Save), "Save comment")
+	line(helpKeys(km.Cancel), "Cancel editing")
+
+	section("Other")
+	line(helpKeys(km.Help), "Toggle this help")
+	line(helpKeys(km.Quit), "Quit")
+
+	fmt.Fprintf(&sb, "\n  Press Esc or %s or q to close this help.\n", keyHint(km.Help))
+	help := sb.String()
 
 	return clipLines(help, a.height)
 }
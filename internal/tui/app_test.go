@@ -2,6 +2,8 @@ package tui
 
 import (
 	"fmt"
+	"os"
+	"slices"
 	"strings"
 	"testing"
 
@@ -1883,3 +1885,532 @@ func TestAppViewedState(t *testing.T) {
 		}
 	})
 }
+
+func TestNewAppPreloadsComments(t *testing.T) {
+	app := NewApp(makeLargeDoc(3, 0), AppOptions{
+		Comments: []markdown.ReviewComment{
+			{SectionID: "S1", Action: markdown.ActionNote, Body: "first"},
+			{SectionID: "S1", Action: markdown.ActionIssue, Body: "second"},
+			{SectionID: "S3", Action: markdown.ActionQuestion, Body: "line", StartLine: 4},
+		},
+	})
+
+	if got := len(app.sectionList.GetComments("S1")); got != 2 {
+		t.Errorf("S1 comments = %d, want 2", got)
+	}
+	if got := len(app.sectionList.GetComments("S3")); got != 1 {
+		t.Errorf("S3 comments = %d, want 1", got)
+	}
+	if got := app.sectionList.TotalCommentCount(); got != 3 {
+		t.Errorf("TotalCommentCount() = %d, want 3", got)
+	}
+}
+
+func TestDraftPersistence(t *testing.T) {
+	dir := t.TempDir()
+	filePath := dir + "/plan.md"
+	draftPath := markdown.DraftPath(filePath)
+	doc, err := markdown.Parse([]byte("# Plan\n\n## Step 1\n\nA\n\n## Step 2\n\nB\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	newApp := func() *App {
+		t.Helper()
+		app := NewApp(doc, AppOptions{FilePath: filePath, SaveDrafts: true})
+		model, _ := app.Update(tea.WindowSizeMsg{Width: 120, Height: 30})
+		return model.(*App)
+	}
+
+	// First session: saving a comment writes the draft
+	a := newApp()
+	if a.mode != ModeNormal {
+		t.Fatalf("mode = %d, want ModeNormal without a draft", a.mode)
+	}
+	a.sectionList.SelectBySectionID("S2")
+	a.Update(keyMsg("c"))
+	a.comment.textarea.SetValue("draft comment")
+	a.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	if _, err := os.Stat(draftPath); err != nil {
+		t.Fatalf("draft file not written: %v", err)
+	}
+
+	// Second session: prompt to restore, accept
+	a = newApp()
+	if a.mode != ModeConfirm || a.confirmAction != confirmRestoreDraft {
+		t.Fatalf("mode = %d, confirm = %d; want restore prompt", a.mode, a.confirmAction)
+	}
+	if !strings.Contains(a.View(), "Restore them?") {
+		t.Error("restore prompt should be shown")
+	}
+	a.Update(keyMsg("y"))
+	comments := a.sectionList.GetComments("S2")
+	if len(comments) != 1 || comments[0].Body != "draft comment" {
+		t.Fatalf("restored comments = %+v", comments)
+	}
+
+	// Submitting clears the draft
+	a.confirmAction = confirmSubmit
+	a.mode = ModeConfirm
+	a.Update(keyMsg("y"))
+	if _, err := os.Stat(draftPath); !os.IsNotExist(err) {
+		t.Error("draft file should be removed on submit")
+	}
+	if len(a.Warnings()) != 0 {
+		t.Errorf("warnings = %v, want none", a.Warnings())
+	}
+}
+
+func TestDraftRestoreDeclined(t *testing.T) {
+	dir := t.TempDir()
+	filePath := dir + "/plan.md"
+	doc := makeLargeDoc(2, 0)
+	draft := &markdown.Draft{Comments: []markdown.DraftComment{{Action: markdown.ActionNote, Body: "old"}}}
+	if err := markdown.SaveDraft(markdown.DraftPath(filePath), draft); err != nil {
+		t.Fatal(err)
+	}
+
+	a := NewApp(doc, AppOptions{FilePath: filePath, SaveDrafts: true})
+	a.Update(keyMsg("n"))
+	if a.mode != ModeNormal {
+		t.Errorf("mode = %d, want ModeNormal", a.mode)
+	}
+	if a.sectionList.HasComments() {
+		t.Error("declined draft should not be restored")
+	}
+	if _, err := os.Stat(markdown.DraftPath(filePath)); !os.IsNotExist(err) {
+		t.Error("declined draft should be discarded")
+	}
+}
+
+func TestDraftRestoreVerdicts(t *testing.T) {
+	dir := t.TempDir()
+	filePath := dir + "/plan.md"
+	doc, err := markdown.Parse([]byte("# Plan\n\n## Step 1\n\nA\n\n## Step 2\n\nB\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// First session: setting a verdict writes the draft
+	a := initApp(t, doc)
+	a.draftPath = markdown.DraftPath(filePath)
+	a.sectionList.SelectBySectionID("S2")
+	a.Update(keyMsg("a"))
+	if _, err := os.Stat(a.draftPath); err != nil {
+		t.Fatalf("draft file not written: %v", err)
+	}
+
+	// Second session: the verdict is restored
+	a = NewApp(doc, AppOptions{FilePath: filePath, SaveDrafts: true})
+	if a.mode != ModeConfirm || a.confirmAction != confirmRestoreDraft {
+		t.Fatalf("mode = %d, confirm = %d; want restore prompt", a.mode, a.confirmAction)
+	}
+	a.Update(keyMsg("y"))
+	if got := a.sectionList.Verdict("S2"); got != markdown.VerdictApprove {
+		t.Errorf("Verdict(S2) = %q, want %q", got, markdown.VerdictApprove)
+	}
+}
+
+func TestDraftRestorePromptIgnoresEsc(t *testing.T) {
+	dir := t.TempDir()
+	filePath := dir + "/plan.md"
+	doc := makeLargeDoc(2, 0)
+	draft := &markdown.Draft{Comments: []markdown.DraftComment{{Action: markdown.ActionNote, Body: "old"}}}
+	if err := markdown.SaveDraft(markdown.DraftPath(filePath), draft); err != nil {
+		t.Fatal(err)
+	}
+
+	a := NewApp(doc, AppOptions{FilePath: filePath, SaveDrafts: true})
+	a.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if a.mode != ModeConfirm || a.confirmAction != confirmRestoreDraft {
+		t.Errorf("mode = %d, confirm = %d; want the restore prompt to stay open", a.mode, a.confirmAction)
+	}
+	loaded, err := markdown.LoadDraft(markdown.DraftPath(filePath))
+	if err != nil || loaded.IsEmpty() {
+		t.Errorf("draft = %+v, %v; want it kept until restored or discarded", loaded, err)
+	}
+}
+
+func TestDraftRestoreWithResumedComments(t *testing.T) {
+	dir := t.TempDir()
+	filePath := dir + "/plan.md"
+	doc, err := markdown.Parse([]byte("# Plan\n\n## Step 1\n\nA\n\n## Step 2\n\nB\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resumed := markdown.ReviewComment{SectionID: "S2", Action: markdown.ActionNote, Body: "resumed"}
+	added := markdown.ReviewComment{SectionID: "S2", Action: markdown.ActionIssue, Body: "added", StartLine: 9}
+	// The draft of the last session holds the resumed comment too
+	draft := markdown.NewDraft(&markdown.ReviewResult{Comments: []markdown.ReviewComment{resumed, added}}, doc)
+	if err := markdown.SaveDraft(markdown.DraftPath(filePath), draft); err != nil {
+		t.Fatal(err)
+	}
+
+	a := NewApp(doc, AppOptions{FilePath: filePath, SaveDrafts: true, Comments: []markdown.ReviewComment{resumed}})
+	a.Update(keyMsg("y"))
+	comments := a.sectionList.GetComments("S2")
+	if len(comments) != 2 || comments[0].Body != "resumed" || comments[1].Body != "added" {
+		t.Errorf("comments = %+v, want the resumed comment once and the added one", comments)
+	}
+}
+
+func TestLineCommentSaveSetsAnchor(t *testing.T) {
+	doc, err := markdown.Parse([]byte("# Plan\n\n## Step 1\n\nfirst\nsecond\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := initApp(t, doc)
+	a.Update(keyMsg("r")) // raw view, right pane focused
+	a.linePane.ScrollToLine(5)
+	a.Update(keyMsg("c"))
+	a.comment.textarea.SetValue("line comment")
+	a.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+
+	comments := a.sectionList.GetComments("S1")
+	if len(comments) != 1 {
+		t.Fatalf("comments = %d, want 1", len(comments))
+	}
+	c := comments[0]
+	if c.StartLine != 5 || c.Anchor == nil {
+		t.Fatalf("comment = %+v, want anchored L5", c)
+	}
+	if got, ok := c.Anchor.Locate(doc.SourceLines, 5, 1); !ok || got != 5 {
+		t.Errorf("anchor locates at (%d, %v), want (5, true)", got, ok)
+	}
+}
+
+func TestSuggestionFromSelectedRange(t *testing.T) {
+	doc, err := markdown.Parse([]byte("# Plan\n\n## Step 1\n\nfirst\nsecond\nthird\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := initApp(t, doc)
+	a.Update(keyMsg("r")) // raw view, right pane focused
+	a.linePane.ScrollToLine(5)
+	a.Update(keyMsg("V"))
+	a.Update(keyMsg("j"))
+	a.Update(keyMsg("p"))
+	if a.mode != ModeComment {
+		t.Fatalf("mode = %d, want ModeComment", a.mode)
+	}
+	if got, want := a.comment.textarea.Value(), "```suggestion\nfirst\nsecond\n```"; got != want {
+		t.Fatalf("prefill = %q, want %q", got, want)
+	}
+	a.comment.textarea.SetValue("```suggestion\n1st\nsecond\n```")
+	a.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+
+	comments := a.sectionList.GetComments("S1")
+	if len(comments) != 1 {
+		t.Fatalf("comments = %d, want 1", len(comments))
+	}
+	c := comments[0]
+	if c.StartLine != 5 || c.EndLine != 6 || c.Action != markdown.ActionSuggestion || c.Suggestion == nil {
+		t.Fatalf("comment = %+v, want a suggestion on L5-L6", c)
+	}
+	if got := strings.Join(c.Suggestion.Original, "|"); got != "first|second" {
+		t.Errorf("Original = %q", got)
+	}
+}
+
+func TestVerdictKeyAndStatusBar(t *testing.T) {
+	a := initApp(t, makeLargeDoc(3, 0))
+	a.Update(keyMsg("j")) // S1
+	a.Update(keyMsg("a")) // approve
+	a.Update(keyMsg("j")) // S2
+	a.Update(keyMsg("a"))
+	a.Update(keyMsg("a"))
+	a.Update(keyMsg("a")) // reject
+
+	if got := a.renderStatusBar(); !strings.Contains(got, "[1 approved, 1 rejected]") {
+		t.Errorf("status bar = %q, want verdict counts", got)
+	}
+
+	a.Update(keyMsg("s"))
+	a.Update(keyMsg("y"))
+	result := a.Result()
+	if result.Status != markdown.StatusSubmitted {
+		t.Fatalf("status = %s, want submitted for a verdict-only review", result.Status)
+	}
+	if result.Review.Verdicts["S1"] != markdown.VerdictApprove || result.Review.Verdicts["S2"] != markdown.VerdictReject {
+		t.Errorf("verdicts = %v", result.Review.Verdicts)
+	}
+}
+
+func TestSubmitPolicyBlocksConfirm(t *testing.T) {
+	a := NewApp(makeLargeDoc(2, 0), AppOptions{Policy: markdown.Policy{RequireViewed: true}})
+	a.Update(tea.WindowSizeMsg{Width: 120, Height: 30})
+
+	a.Update(keyMsg("s"))
+	if got := a.renderConfirm(); !strings.Contains(got, "Cannot submit yet") || !strings.Contains(got, "0/2 viewed") {
+		t.Errorf("confirm dialog = %q, want the policy failure", got)
+	}
+	a.Update(keyMsg("y"))
+	if a.mode != ModeConfirm || a.Result().Status != markdown.StatusCancelled {
+		t.Fatalf("approval was submitted despite the policy (mode %d, status %s)", a.mode, a.Result().Status)
+	}
+	a.Update(keyMsg("n"))
+
+	a.Update(keyMsg("j"))
+	a.Update(keyMsg("v"))
+	a.Update(keyMsg("j"))
+	a.Update(keyMsg("v"))
+	a.Update(keyMsg("s"))
+	a.Update(keyMsg("y"))
+	result := a.Result()
+	if result.Status != markdown.StatusApproved {
+		t.Fatalf("status = %s, want approved once every section is viewed", result.Status)
+	}
+	if p := result.Review.Policy; p == nil || !p.Passed() || p.Viewed != 2 || p.Sections != 2 {
+		t.Errorf("policy = %+v", p)
+	}
+}
+
+func TestHideResolvedThreads(t *testing.T) {
+	threads := []*ReviewThread{
+		{Line: 3, Resolved: true, Comments: []ThreadComment{{Author: "alice", Body: "done"}}},
+		{Line: 5, Comments: []ThreadComment{{Author: "bob", Body: "open"}}},
+	}
+	a := NewApp(makeLargeDoc(2, 0), AppOptions{Threads: threads})
+	a.Update(tea.WindowSizeMsg{Width: 120, Height: 30})
+
+	if got := a.renderStatusBar(); !strings.Contains(got, "[2 threads]") {
+		t.Errorf("status bar = %q, want the thread count", got)
+	}
+	a.Update(keyMsg("R"))
+	if got := a.visibleThreads(); len(got) != 1 || got[0] != threads[1] {
+		t.Errorf("visibleThreads() = %v, want only the unresolved thread", got)
+	}
+	if got := a.renderStatusBar(); !strings.Contains(got, "[2 threads, 1 resolved hidden]") {
+		t.Errorf("status bar = %q, want the hidden count", got)
+	}
+	a.Update(keyMsg("R"))
+	if got := a.visibleThreads(); len(got) != 2 {
+		t.Errorf("visibleThreads() = %d threads, want 2 after toggling back", len(got))
+	}
+}
+
+func TestThreadReplyAndResolve(t *testing.T) {
+	threads := []*ReviewThread{
+		{ID: "T1", Line: 3, Comments: []ThreadComment{{Author: "alice", Body: "Typo here"}}},
+		{ID: "T2", Line: 5, Resolved: true, Comments: []ThreadComment{{Author: "bob", Body: "Done"}}},
+	}
+	a := NewApp(makeLargeDoc(2, 0), AppOptions{PRMode: true, Threads: threads})
+	a.Update(tea.WindowSizeMsg{Width: 120, Height: 30})
+
+	// All threads fall in the overview of this document
+	a.Update(keyMsg("T"))
+	if a.mode != ModeThreadList {
+		t.Fatalf("mode = %v, want ModeThreadList", a.mode)
+	}
+	if view := a.View(); !strings.Contains(view, "@alice") || !strings.Contains(view, "@bob") {
+		t.Errorf("thread list should show both threads:\n%s", view)
+	}
+
+	// Reply to the first thread
+	a.Update(keyMsg("r"))
+	if a.mode != ModeComment || a.replyThread != threads[0] {
+		t.Fatalf("mode = %v, replyThread = %v; want the reply editor on T1", a.mode, a.replyThread)
+	}
+	a.Update(keyMsg("Fixed"))
+	a.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	if a.mode != ModeThreadList || threads[0].Reply != "Fixed" {
+		t.Fatalf("mode = %v, reply = %q; want the reply queued", a.mode, threads[0].Reply)
+	}
+
+	// Unresolve the second thread, then resolve and unresolve the first one again
+	a.Update(keyMsg("j"))
+	a.Update(keyMsg("x"))
+	a.Update(keyMsg("k"))
+	a.Update(keyMsg("x"))
+	a.Update(keyMsg("x"))
+	if view := a.View(); !strings.Contains(view, "will unresolve") {
+		t.Errorf("thread list should show the queued unresolve:\n%s", view)
+	}
+
+	a.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	a.Update(keyMsg("s"))
+	a.Update(keyMsg("y"))
+	result := a.Result()
+	if len(result.Replies) != 1 || result.Replies[0] != (ThreadReply{ThreadID: "T1", Body: "Fixed"}) {
+		t.Errorf("Replies = %+v", result.Replies)
+	}
+	if len(result.Resolutions) != 1 || result.Resolutions[0] != (ThreadResolution{ThreadID: "T2", Resolve: false}) {
+		t.Errorf("Resolutions = %+v", result.Resolutions)
+	}
+}
+
+func TestThreadReplyDrop(t *testing.T) {
+	thread := &ReviewThread{ID: "T1", Line: 3, Reply: "queued"}
+	a := NewApp(makeLargeDoc(1, 0), AppOptions{PRMode: true, Threads: []*ReviewThread{thread}})
+	a.Update(tea.WindowSizeMsg{Width: 120, Height: 30})
+
+	a.Update(keyMsg("T"))
+	a.Update(keyMsg("d"))
+	if thread.Reply != "" {
+		t.Errorf("reply = %q, want it dropped", thread.Reply)
+	}
+
+	// Saving an empty reply also drops it
+	thread.Reply = "queued"
+	a.Update(keyMsg("r"))
+	a.comment.textarea.SetValue("  ")
+	a.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	if thread.Reply != "" {
+		t.Errorf("reply = %q, want it dropped", thread.Reply)
+	}
+}
+
+func TestInitialLeftRatio(t *testing.T) {
+	tests := []struct {
+		in, want int
+	}{
+		{0, 30},
+		{40, 40},
+		{5, 10},
+		{80, 50},
+	}
+	for _, tt := range tests {
+		if got := initialLeftRatio(tt.in); got != tt.want {
+			t.Errorf("initialLeftRatio(%d) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestFullFileDiffView(t *testing.T) {
+	doc, err := markdown.Parse([]byte("# T\n\n## A\n\nnew\nkeep\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	diff := &DiffData{
+		DisplayLines: []string{"- old", "+ new"},
+		LineMap:      []int{5, 5},
+		SideMap:      []string{"LEFT", "RIGHT"},
+		TypeMap:      []byte{'-', '+'},
+		FullFile: &DiffData{
+			DisplayLines: []string{"  # T", "  ", "  ## A", "  ", "- old", "+ new", "  keep"},
+			LineMap:      []int{1, 2, 3, 4, 5, 5, 6},
+			SideMap:      []string{"RIGHT", "RIGHT", "RIGHT", "RIGHT", "LEFT", "RIGHT", "RIGHT"},
+			TypeMap:      []byte{' ', ' ', ' ', ' ', '-', '+', ' '},
+			Outside:      []bool{true, true, true, true, false, false, true},
+		},
+	}
+	a := NewApp(doc, AppOptions{PRMode: true, Diff: diff})
+	a.Update(tea.WindowSizeMsg{Width: 120, Height: 30})
+	a.Update(keyMsg("f"))
+	a.Update(tea.KeyMsg{Type: tea.KeyTab})
+
+	a.Update(keyMsg("D"))
+	if got := a.linePane.LineCount(); got != 7 {
+		t.Fatalf("LineCount() = %d, want the whole file", got)
+	}
+	if got := a.renderStatusBar(); !strings.Contains(got, "(file-level)") {
+		t.Errorf("status bar = %q, want the file-level hint on an unchanged line", got)
+	}
+
+	// Unchanged line: an overview comment prefixed with the line
+	a.Update(keyMsg("c"))
+	if a.mode != ModeComment || a.comment.SectionID() != markdown.OverviewSectionID {
+		t.Fatalf("mode = %d, section = %q, want an overview comment", a.mode, a.comment.SectionID())
+	}
+	if got := a.comment.Text(); got != "L1:" {
+		t.Errorf("Text() = %q, want the line reference", got)
+	}
+	a.Update(keyMsg("esc"))
+
+	// Added line: a regular line comment
+	for range 5 {
+		a.Update(keyMsg("j"))
+	}
+	a.Update(keyMsg("c"))
+	if a.comment.SectionID() == markdown.OverviewSectionID || a.comment.FormatLineRef() != "L5" {
+		t.Errorf("section = %q, lines = %q, want a line comment on L5", a.comment.SectionID(), a.comment.FormatLineRef())
+	}
+	a.Update(keyMsg("esc"))
+
+	a.Update(keyMsg("D"))
+	if got := a.linePane.LineCount(); got != 2 {
+		t.Errorf("LineCount() = %d, want the hunks back", got)
+	}
+}
+
+func TestExpandDiffContext(t *testing.T) {
+	doc, err := markdown.Parse([]byte("# T\n\n## A\n\nnew\nkeep\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expanded := &DiffData{
+		DisplayLines: []string{"  ## A", "  ", "- old", "+ new"},
+		LineMap:      []int{3, 4, 5, 5},
+		SideMap:      []string{"RIGHT", "RIGHT", "LEFT", "RIGHT"},
+		TypeMap:      []byte{' ', ' ', '-', '+'},
+		Outside:      []bool{true, true, false, false},
+	}
+	var calls []string
+	diff := &DiffData{
+		DisplayLines: []string{"- old", "+ new"},
+		LineMap:      []int{5, 5},
+		SideMap:      []string{"LEFT", "RIGHT"},
+		TypeMap:      []byte{'-', '+'},
+		FullFile:     &DiffData{DisplayLines: []string{"  # T"}, LineMap: []int{1}, SideMap: []string{"RIGHT"}, TypeMap: []byte{' '}},
+		Expand: func(i int, down bool) *DiffData {
+			calls = append(calls, fmt.Sprintf("%d %v", i, down))
+			if down {
+				return nil
+			}
+			return expanded
+		},
+	}
+	a := NewApp(doc, AppOptions{PRMode: true, Diff: diff})
+	a.Update(tea.WindowSizeMsg{Width: 120, Height: 30})
+	a.Update(keyMsg("f"))
+	a.Update(tea.KeyMsg{Type: tea.KeyTab})
+	a.Update(keyMsg("j"))
+
+	a.Update(keyMsg("]"))
+	if got := a.linePane.LineCount(); got != 2 {
+		t.Errorf("LineCount() = %d, want the hunk unchanged without a gap", got)
+	}
+	a.Update(keyMsg("["))
+	if !slices.Equal(calls, []string{"1 true", "1 false"}) {
+		t.Errorf("Expand calls = %q", calls)
+	}
+	if got := a.linePane.LineCount(); got != 4 {
+		t.Fatalf("LineCount() = %d, want the expanded lines", got)
+	}
+	if got := a.linePane.Cursor(); got != 3 {
+		t.Errorf("Cursor() = %d, want 3 (still on the added line)", got)
+	}
+
+	// Expanded lines are outside the patch: file-level comments
+	for range 3 {
+		a.Update(keyMsg("k"))
+	}
+	a.Update(keyMsg("c"))
+	if a.comment.SectionID() != markdown.OverviewSectionID || a.comment.Text() != "L3:" {
+		t.Errorf("section = %q, text = %q, want a file-level comment on L3", a.comment.SectionID(), a.comment.Text())
+	}
+	a.Update(keyMsg("esc"))
+
+	// The whole-file view and back keeps the expanded hunks
+	a.Update(keyMsg("D"))
+	a.Update(keyMsg("["))
+	if len(calls) != 2 {
+		t.Errorf("Expand should not be called in the whole-file view, calls = %q", calls)
+	}
+	a.Update(keyMsg("D"))
+	if got := a.linePane.LineCount(); got != 4 {
+		t.Errorf("LineCount() = %d, want the expanded hunks back", got)
+	}
+}
+
+func TestSubmitDetachesOrphanedComments(t *testing.T) {
+	orphan := markdown.ReviewComment{SectionID: "S1", Action: markdown.ActionNote, Body: "gone", StartLine: 3, Orphaned: true}
+	a := NewApp(makeLargeDoc(2, 0), AppOptions{Comments: []markdown.ReviewComment{orphan}})
+	a.confirmAction = confirmSubmit
+	a.mode = ModeConfirm
+	a.Update(keyMsg("y"))
+
+	comments := a.Result().Review.Comments
+	if len(comments) != 1 || comments[0].StartLine != 0 || comments[0].Body != "L3 (lines changed): gone" {
+		t.Errorf("submitted comments = %+v, want a section comment with the former line", comments)
+	}
+}
@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"slices"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/textarea"
@@ -12,11 +13,17 @@ import (
 type CommentEditor struct {
 	textarea   textarea.Model
 	sectionID  string
-	labelIndex int    // index into markdown.ActionLabels
-	decoIndex  int    // index into markdown.DecorationLabels
-	startLine  int    // 1-based start line (0 = section-level)
-	endLine    int    // 1-based end line (0 = single line)
-	side       string // "RIGHT" or "LEFT" (for PR diff)
+	labels     markdown.LabelSet
+	labelIndex int                 // index into labels.Actions (-1 = action)
+	decoIndex  int                 // index into labels.Decorations (-1 = decoration)
+	action     markdown.ActionType // edited comment's label, used if it is not in labels
+	decoration markdown.Decoration // edited comment's decoration, used if it is not in labels
+	startLine  int                 // 1-based start line (0 = section-level)
+	endLine    int                 // 1-based end line (0 = single line)
+	side       string              // "RIGHT" or "LEFT" (for PR diff)
+	anchor     *markdown.LineAnchor
+	orphaned   bool
+	original   []string // lines a suggestion replaces (nil = taken from the document on save)
 }
 
 // NewCommentEditor creates a new CommentEditor.
@@ -29,6 +36,7 @@ func NewCommentEditor() *CommentEditor {
 
 	return &CommentEditor{
 		textarea: ta,
+		labels:   markdown.Labels,
 	}
 }
 
@@ -37,17 +45,32 @@ func (c *CommentEditor) Open(sectionID string, existing *markdown.ReviewComment)
 	c.sectionID = sectionID
 
 	if existing != nil {
+		// A label that is not in the label set (e.g. from a review written
+		// with other labels) is kept until another one is picked.
 		c.labelIndex = c.labelIndexFor(existing.Action)
 		c.decoIndex = c.decorationIndexFor(existing.Decoration)
-		c.textarea.SetValue(existing.Body)
+		c.action = existing.Action
+		c.decoration = existing.Decoration
+		c.textarea.SetValue(existing.BodyWithSuggestion())
+		c.original = nil
+		if existing.Suggestion != nil {
+			c.original = existing.Suggestion.Original
+		}
 		c.startLine = existing.StartLine
 		c.endLine = existing.EndLine
+		c.side = existing.Side
+		c.anchor = existing.Anchor
+		c.orphaned = existing.Orphaned
 	} else {
-		c.labelIndex = c.labelIndexFor(markdown.DefaultAction)
+		c.labelIndex = c.labelIndexFor(c.labels.Default)
 		c.decoIndex = 0
 		c.textarea.SetValue("")
 		c.startLine = 0
 		c.endLine = 0
+		c.side = ""
+		c.anchor = nil
+		c.orphaned = false
+		c.original = nil
 	}
 
 	return c.textarea.Focus()
@@ -63,9 +86,45 @@ func (c *CommentEditor) OpenWithLines(sectionID string, existing *markdown.Revie
 	return cmd
 }
 
-// labelIndexFor returns the index of the given action in ActionLabels.
+// OpenSuggestion opens the comment editor for a new suggested change to
+// lines, which are the current content of [startLine, endLine]. The editor is
+// pre-filled with lines in a ```suggestion block and the suggestion label is
+// selected if the label set has it.
+func (c *CommentEditor) OpenSuggestion(sectionID string, startLine, endLine int, side string, lines []string) tea.Cmd {
+	cmd := c.OpenWithLines(sectionID, nil, startLine, endLine, side)
+	if c.labels.HasAction(markdown.ActionSuggestion) {
+		c.labelIndex = c.labelIndexFor(markdown.ActionSuggestion)
+	}
+	c.original = slices.Clone(lines)
+	c.textarea.SetValue(markdown.SuggestionBlock(lines))
+	// Leave the cursor at the end of the last suggested line.
+	c.textarea.CursorUp()
+	c.textarea.CursorEnd()
+	return cmd
+}
+
+// OpenReply opens the comment editor for a plain-text reply to a review
+// thread, pre-filled with body (the reply queued so far).
+func (c *CommentEditor) OpenReply(body string) tea.Cmd {
+	return c.OpenWithBody("", body)
+}
+
+// OpenWithBody opens the comment editor for a new comment on sectionID,
+// pre-filled with body.
+func (c *CommentEditor) OpenWithBody(sectionID, body string) tea.Cmd {
+	cmd := c.Open(sectionID, nil)
+	c.textarea.SetValue(body)
+	return cmd
+}
+
+// Text returns the editor content without surrounding whitespace.
+func (c *CommentEditor) Text() string {
+	return strings.TrimSpace(c.textarea.Value())
+}
+
+// labelIndexFor returns the index of the given action in the label set.
 func (c *CommentEditor) labelIndexFor(action markdown.ActionType) int {
-	return indexInSlice(markdown.ActionLabels, action)
+	return indexInSlice(c.labels.Actions, action)
 }
 
 // Close closes the comment editor.
@@ -80,35 +139,43 @@ func (c *CommentEditor) SectionID() string {
 
 // Label returns the current action label.
 func (c *CommentEditor) Label() markdown.ActionType {
-	return markdown.ActionLabels[c.labelIndex]
+	if c.labelIndex < 0 {
+		return c.action
+	}
+	return c.labels.Actions[c.labelIndex]
 }
 
 // CycleLabel cycles to the next action label.
 func (c *CommentEditor) CycleLabel() {
-	c.labelIndex = (c.labelIndex + 1) % len(markdown.ActionLabels)
+	c.labelIndex = (c.labelIndex + 1) % len(c.labels.Actions)
 }
 
 // CycleLabelReverse cycles to the previous action label.
 func (c *CommentEditor) CycleLabelReverse() {
-	c.labelIndex = (c.labelIndex - 1 + len(markdown.ActionLabels)) % len(markdown.ActionLabels)
+	c.labelIndex = (max(c.labelIndex, 0) - 1 + len(c.labels.Actions)) % len(c.labels.Actions)
 }
 
 // DecorationLabel returns the current decoration.
 func (c *CommentEditor) DecorationLabel() markdown.Decoration {
-	return markdown.DecorationLabels[c.decoIndex]
+	if c.decoIndex < 0 {
+		return c.decoration
+	}
+	return c.labels.Decorations[c.decoIndex]
 }
 
 // CycleDecoration cycles to the next decoration.
 func (c *CommentEditor) CycleDecoration() {
-	c.decoIndex = (c.decoIndex + 1) % len(markdown.DecorationLabels)
+	c.decoIndex = (c.decoIndex + 1) % len(c.labels.Decorations)
 }
 
 // FormatLabel returns the combined action and decoration label for display.
 func (c *CommentEditor) FormatLabel() string {
-	return markdown.FormatActionLabel(
-		markdown.ActionLabels[c.labelIndex],
-		markdown.DecorationLabels[c.decoIndex],
-	)
+	return markdown.FormatActionLabel(c.Label(), c.DecorationLabel())
+}
+
+// LabelColor returns the configured colour of the current action label, or "".
+func (c *CommentEditor) LabelColor() string {
+	return c.labels.Color(c.Label())
 }
 
 // FormatLineRef returns a line reference string for display.
@@ -116,23 +183,24 @@ func (c *CommentEditor) FormatLineRef() string {
 	return markdown.FormatLineRef(c.startLine, c.endLine)
 }
 
-// decorationIndexFor returns the index of the given decoration in DecorationLabels.
+// decorationIndexFor returns the index of the given decoration in the label set.
 func (c *CommentEditor) decorationIndexFor(deco markdown.Decoration) int {
-	return indexInSlice(markdown.DecorationLabels, deco)
+	return indexInSlice(c.labels.Decorations, deco)
 }
 
-// indexInSlice returns the index of val in slice, or 0 if not found.
+// indexInSlice returns the index of val in slice, or -1 if not found.
 func indexInSlice[T comparable](slice []T, val T) int {
 	for i, v := range slice {
 		if v == val {
 			return i
 		}
 	}
-	return 0
+	return -1
 }
 
 // Result returns the review comment from the editor content.
-// Returns nil if the body is empty.
+// A ```suggestion block in a line comment on the new side of the file becomes
+// the comment's Suggestion. Returns nil if the body is empty.
 func (c *CommentEditor) Result() *markdown.ReviewComment {
 	body := strings.TrimSpace(c.textarea.Value())
 
@@ -140,14 +208,25 @@ func (c *CommentEditor) Result() *markdown.ReviewComment {
 		return nil
 	}
 
+	var suggestion *markdown.Suggestion
+	if c.startLine > 0 && c.side != "LEFT" {
+		if text, lines, ok := markdown.SplitSuggestion(body); ok {
+			body = text
+			suggestion = &markdown.Suggestion{Original: c.original, Replacement: lines}
+		}
+	}
+
 	return &markdown.ReviewComment{
 		SectionID:  c.sectionID,
-		Action:     markdown.ActionLabels[c.labelIndex],
-		Decoration: markdown.DecorationLabels[c.decoIndex],
+		Action:     c.Label(),
+		Decoration: c.DecorationLabel(),
 		Body:       body,
 		StartLine:  c.startLine,
 		EndLine:    c.endLine,
 		Side:       c.side,
+		Anchor:     c.anchor,
+		Orphaned:   c.orphaned,
+		Suggestion: suggestion,
 	}
 }
 
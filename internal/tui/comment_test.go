@@ -91,7 +91,7 @@ func TestCommentEditorLabelIndexFor(t *testing.T) {
 		{markdown.ActionSuggestion, 0},
 		{markdown.ActionIssue, 1},
 		{markdown.ActionQuestion, 2},
-		{markdown.ActionType("unknown"), 0},
+		{markdown.ActionType("unknown"), -1},
 	}
 
 	for _, tt := range tests {
@@ -182,7 +182,7 @@ func TestCommentEditorDecorationIndexFor(t *testing.T) {
 		{markdown.DecorationNonBlocking, 1},
 		{markdown.DecorationBlocking, 2},
 		{markdown.DecorationIfMinor, 3},
-		{markdown.Decoration("unknown"), 0},
+		{markdown.Decoration("unknown"), -1},
 	}
 
 	for _, tt := range tests {
@@ -257,3 +257,96 @@ func TestCommentEditorResultWithDecoration(t *testing.T) {
 		t.Errorf("decoration = %s, want non-blocking", result.Decoration)
 	}
 }
+
+func TestCommentEditorCustomLabels(t *testing.T) {
+	ce := NewCommentEditor()
+	ce.labels = markdown.LabelSet{
+		Actions:     []markdown.ActionType{"issue", "security", "decision"},
+		Decorations: []markdown.Decoration{markdown.DecorationNone, "ux"},
+		Default:     "security",
+		Colors:      map[markdown.ActionType]string{"security": "196"},
+	}
+	ce.Open("S1", nil)
+
+	if ce.Label() != "security" {
+		t.Errorf("default label = %s, want security", ce.Label())
+	}
+	if ce.LabelColor() != "196" {
+		t.Errorf("LabelColor() = %q, want 196", ce.LabelColor())
+	}
+	ce.CycleLabel()
+	ce.CycleDecoration()
+	if got := ce.FormatLabel(); got != "decision (ux)" {
+		t.Errorf("FormatLabel() = %q, want %q", got, "decision (ux)")
+	}
+	ce.CycleLabel()
+	if ce.Label() != "issue" {
+		t.Errorf("label after wrap = %s, want issue", ce.Label())
+	}
+}
+
+func TestCommentEditorKeepsUnknownLabel(t *testing.T) {
+	ce := NewCommentEditor()
+	ce.Open("S1", &markdown.ReviewComment{SectionID: "S1", Action: "security", Decoration: "ux", Body: "Check auth"})
+
+	if got := ce.FormatLabel(); got != "security (ux)" {
+		t.Errorf("FormatLabel() = %q, want %q", got, "security (ux)")
+	}
+	if r := ce.Result(); r.Action != "security" || r.Decoration != "ux" {
+		t.Errorf("Result() label = %s (%s), want security (ux)", r.Action, r.Decoration)
+	}
+
+	ce.CycleLabel()
+	ce.CycleDecoration()
+	if ce.Label() != ce.labels.Actions[0] || ce.DecorationLabel() != markdown.DecorationNone {
+		t.Errorf("after cycling: %s, want first label without decoration", ce.FormatLabel())
+	}
+
+	ce.Open("S1", &markdown.ReviewComment{SectionID: "S1", Action: "security", Body: "Check auth"})
+	ce.CycleLabelReverse()
+	if want := ce.labels.Actions[len(ce.labels.Actions)-1]; ce.Label() != want {
+		t.Errorf("after reverse cycling: label = %s, want %s", ce.Label(), want)
+	}
+}
+
+func TestCommentEditorSuggestion(t *testing.T) {
+	ce := NewCommentEditor()
+	ce.OpenSuggestion("S1", 5, 6, "", []string{"old one", "old two"})
+
+	if ce.Label() != markdown.ActionSuggestion {
+		t.Errorf("label = %s, want suggestion", ce.Label())
+	}
+	if got, want := ce.textarea.Value(), "```suggestion\nold one\nold two\n```"; got != want {
+		t.Errorf("prefill = %q, want %q", got, want)
+	}
+
+	ce.textarea.SetValue("Tighten the wording.\n\n```suggestion\nnew one\n```")
+	result := ce.Result()
+	if result == nil || result.Suggestion == nil {
+		t.Fatalf("Result() = %+v, want a suggestion", result)
+	}
+	if result.Body != "Tighten the wording." {
+		t.Errorf("Body = %q", result.Body)
+	}
+	if got := result.Suggestion; len(got.Original) != 2 || len(got.Replacement) != 1 || got.Replacement[0] != "new one" {
+		t.Errorf("Suggestion = %+v", got)
+	}
+
+	// Re-opening the comment puts the block back into the editor.
+	ce.Open("S1", result)
+	if got, want := ce.textarea.Value(), "Tighten the wording.\n\n```suggestion\nnew one\n```"; got != want {
+		t.Errorf("reopened value = %q, want %q", got, want)
+	}
+	if again := ce.Result(); again.Suggestion == nil || len(again.Suggestion.Original) != 2 {
+		t.Errorf("original lines lost on re-edit: %+v", again.Suggestion)
+	}
+}
+
+func TestCommentEditorSuggestionBlockIgnoredOnSections(t *testing.T) {
+	ce := NewCommentEditor()
+	ce.Open("S1", nil)
+	ce.textarea.SetValue("```suggestion\nx\n```")
+	if result := ce.Result(); result.Suggestion != nil {
+		t.Errorf("section comment got a suggestion: %+v", result.Suggestion)
+	}
+}
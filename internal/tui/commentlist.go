@@ -78,17 +78,21 @@ func (cl *CommentList) Render(width, height int, styles Styles) string {
 		if ref := c.FormatLineRef(); ref != "" {
 			lineRef = " (" + ref + ")"
 		}
-		header := fmt.Sprintf("%s#%d [%s]%s", prefix, i+1, c.FormatLabel(), lineRef)
+		if c.Orphaned {
+			lineRef += " orphaned"
+		}
+		style := styles.NormalSection
 		if i == cl.cursor {
-			sb.WriteString(styles.SelectedSection.Render(header))
-		} else {
-			sb.WriteString(styles.NormalSection.Render(header))
+			style = styles.SelectedSection
 		}
+		sb.WriteString(style.Render(fmt.Sprintf("%s#%d ", prefix, i+1)))
+		sb.WriteString(labelStyle(style, c.Action).Render("[" + c.FormatLabel() + "]"))
+		sb.WriteString(style.Render(lineRef))
 		sb.WriteString("\n")
 
 		// Show body preview (first line, truncated)
-		if c.Body != "" {
-			bodyLine := strings.SplitN(c.Body, "\n", 2)[0]
+		if body := c.BodyWithSuggestion(); body != "" {
+			bodyLine := strings.SplitN(body, "\n", 2)[0]
 			bodyLine = truncate(bodyLine, width-6)
 			sb.WriteString(styles.NormalSection.Render("    " + bodyLine))
 			sb.WriteString("\n")
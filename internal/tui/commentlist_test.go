@@ -133,3 +133,19 @@ func TestCommentListRender(t *testing.T) {
 		t.Error("render should contain comment body")
 	}
 }
+
+func TestCommentListRenderOrphaned(t *testing.T) {
+	cl := NewCommentList()
+	cl.Open("S1", []*markdown.ReviewComment{
+		{SectionID: "S1", Action: markdown.ActionNote, Body: "moved away", StartLine: 4, Orphaned: true},
+		{SectionID: "S1", Action: markdown.ActionNote, Body: "still here", StartLine: 6},
+	})
+
+	output := cl.Render(80, 20, stylesForTheme(DefaultTheme()))
+	if !strings.Contains(output, "(L4) orphaned") {
+		t.Errorf("orphaned comment should be flagged, got:\n%s", output)
+	}
+	if strings.Count(output, "orphaned") != 1 {
+		t.Errorf("only one comment should be flagged, got:\n%s", output)
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/koh-sh/commd/internal/markdown"
 	"github.com/mattn/go-runewidth"
+	"github.com/muesli/termenv"
 )
 
 // glamourHorizontalOverhead accounts for glamour's default left/right
@@ -27,19 +28,23 @@ type sectionOffset struct {
 type DetailPane struct {
 	viewport       viewport.Model
 	renderer       *glamour.TermRenderer
-	theme          string
+	theme          Theme
 	sectionOffsets []sectionOffset
+	threads        map[string][]*ReviewThread // existing PR review threads by section ID
 }
 
-// customStyle returns a glamour style with red background removed from
-// Chroma error tokens. Japanese text can be misidentified as error tokens
+// customStyle returns the theme's glamour style with red background removed
+// from Chroma error tokens. Japanese text can be misidentified as error tokens
 // by Chroma, causing distracting red backgrounds.
-func customStyle(theme string) ansi.StyleConfig {
+func customStyle(theme Theme) ansi.StyleConfig {
 	var style ansi.StyleConfig
-	if theme == ThemeLight {
-		style = glamourStyles.LightStyleConfig
-	} else {
+	switch {
+	case theme.glamour != nil:
+		style = *theme.glamour
+	case theme.Dark:
 		style = glamourStyles.DarkStyleConfig
+	default:
+		style = glamourStyles.LightStyleConfig
 	}
 	if style.CodeBlock.Chroma != nil {
 		chroma := *style.CodeBlock.Chroma
@@ -52,13 +57,18 @@ func customStyle(theme string) ansi.StyleConfig {
 }
 
 // NewDetailPane creates a new DetailPane.
-func NewDetailPane(width, height int, theme string) *DetailPane {
+func NewDetailPane(width, height int, theme Theme) *DetailPane {
+	theme = theme.orDefault()
 	vp := viewport.New(width, height)
-	// Intentionally ignore error: renderContent falls back to plain text when renderer is nil.
-	renderer, _ := glamour.NewTermRenderer(
+	opts := []glamour.TermRendererOption{
 		glamour.WithStyles(customStyle(theme)),
 		glamour.WithWordWrap(0),
-	)
+	}
+	if theme.NoColor {
+		opts = append(opts, glamour.WithColorProfile(termenv.Ascii))
+	}
+	// Intentionally ignore error: renderContent falls back to plain text when renderer is nil.
+	renderer, _ := glamour.NewTermRenderer(opts...)
 
 	return &DetailPane{
 		viewport: vp,
@@ -77,6 +87,12 @@ func (d *DetailPane) SetSize(width, height int) {
 	d.viewport.Height = height
 }
 
+// SetThreads sets the existing review threads shown after each section's
+// comments, keyed by section ID. Call before the Show methods.
+func (d *DetailPane) SetThreads(threads map[string][]*ReviewThread) {
+	d.threads = threads
+}
+
 // ShowSection renders and displays a section's content.
 func (d *DetailPane) ShowSection(section *markdown.Section, comments []*markdown.ReviewComment) {
 	d.sectionOffsets = nil
@@ -87,7 +103,7 @@ func (d *DetailPane) ShowSection(section *markdown.Section, comments []*markdown
 	}
 
 	rendered := d.renderMarkdown(md.String())
-	d.setViewportContent(d.appendCommentBoxes(rendered, comments))
+	d.setViewportContent(d.appendCommentBoxes(rendered, comments, d.threads[section.ID]))
 }
 
 // writeDocHeader writes the document title and preamble as Markdown to the builder.
@@ -107,12 +123,12 @@ func (d *DetailPane) ShowOverview(doc *markdown.Document, comments []*markdown.R
 	writeDocHeader(&content, doc)
 
 	rendered := d.renderMarkdown(content.String())
-	d.setViewportContent(d.appendCommentBoxes(rendered, comments))
+	d.setViewportContent(d.appendCommentBoxes(rendered, comments, d.threads[markdown.OverviewSectionID]))
 }
 
-// appendCommentBoxes appends rendered comment boxes to the given content.
-func (d *DetailPane) appendCommentBoxes(rendered string, comments []*markdown.ReviewComment) string {
-	if len(comments) == 0 {
+// appendCommentBoxes appends rendered comment boxes, then thread boxes, to the given content.
+func (d *DetailPane) appendCommentBoxes(rendered string, comments []*markdown.ReviewComment, threads []*ReviewThread) string {
+	if len(comments) == 0 && len(threads) == 0 {
 		return rendered
 	}
 	var sb strings.Builder
@@ -121,6 +137,10 @@ func (d *DetailPane) appendCommentBoxes(rendered string, comments []*markdown.Re
 		sb.WriteString(d.renderCommentBox(c, i, len(comments)))
 		sb.WriteString("\n")
 	}
+	for _, t := range threads {
+		sb.WriteString(d.renderThreadBox(t))
+		sb.WriteString("\n")
+	}
 	return sb.String()
 }
 
@@ -343,10 +363,7 @@ func (d *DetailPane) ScrollToSectionID(sectionID string) {
 }
 
 func (d *DetailPane) commentBorderColor() string {
-	if d.theme == ThemeLight {
-		return "33"
-	}
-	return "62"
+	return d.theme.palette.commentBoxBorder
 }
 
 func (d *DetailPane) renderCommentBox(comment *markdown.ReviewComment, index, total int) string {
@@ -356,10 +373,13 @@ func (d *DetailPane) renderCommentBox(comment *markdown.ReviewComment, index, to
 	} else {
 		header = fmt.Sprintf("Review Comment #%d [%s]", index+1, comment.FormatLabel())
 	}
+	if comment.Orphaned {
+		header += " (orphaned)"
+	}
 
 	content := header
-	if comment.Body != "" {
-		content += "\n\n" + comment.Body
+	if body := comment.BodyWithSuggestion(); body != "" {
+		content += "\n\n" + body
 	}
 
 	boxWidth := d.viewport.Width - glamourHorizontalOverhead
@@ -372,9 +392,16 @@ func (d *DetailPane) renderCommentBox(comment *markdown.ReviewComment, index, to
 	return style.Render(content)
 }
 
+func (d *DetailPane) renderThreadBox(t *ReviewThread) string {
+	border := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color(d.theme.palette.inactiveBorder))
+	return renderThread(t, d.viewport.Width-glamourHorizontalOverhead, border)
+}
+
 func (d *DetailPane) hasAnyComments(sectionOrder []string, getComments func(string) []*markdown.ReviewComment) bool {
 	for _, id := range sectionOrder {
-		if len(getComments(id)) > 0 {
+		if len(getComments(id)) > 0 || len(d.threads[id]) > 0 {
 			return true
 		}
 	}
@@ -396,7 +423,8 @@ func (d *DetailPane) insertCommentBoxes(rendered string, sectionOrder []string,
 	for i := len(sectionOrder) - 1; i >= 0; i-- {
 		sectionID := sectionOrder[i]
 		comments := getComments(sectionID)
-		if len(comments) == 0 {
+		threads := d.threads[sectionID]
+		if len(comments) == 0 && len(threads) == 0 {
 			continue
 		}
 		insertAt := endLines[sectionID]
@@ -405,6 +433,9 @@ func (d *DetailPane) insertCommentBoxes(rendered string, sectionOrder []string,
 			boxStr := d.renderCommentBox(c, ci, len(comments))
 			boxes = append(boxes, strings.Split(boxStr, "\n")...)
 		}
+		for _, t := range threads {
+			boxes = append(boxes, strings.Split(d.renderThreadBox(t), "\n")...)
+		}
 		boxes = append(boxes, "")
 		newLines := make([]string, 0, len(lines)+len(boxes))
 		newLines = append(newLines, lines[:insertAt]...)
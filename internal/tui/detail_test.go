@@ -9,7 +9,7 @@ import (
 )
 
 func TestNewDetailPane(t *testing.T) {
-	dp := NewDetailPane(80, 24, "dark")
+	dp := NewDetailPane(80, 24, DefaultTheme())
 	if dp == nil {
 		t.Fatal("NewDetailPane returned nil")
 		return
@@ -23,7 +23,7 @@ func TestNewDetailPane(t *testing.T) {
 }
 
 func TestDetailPaneShowSection(t *testing.T) {
-	dp := NewDetailPane(80, 40, "dark")
+	dp := NewDetailPane(80, 40, DefaultTheme())
 	section := &markdown.Section{ID: "S1", Title: "Test Step", Body: "Unique test body here"}
 
 	dp.ShowSection(section, nil)
@@ -38,7 +38,7 @@ func TestDetailPaneShowSection(t *testing.T) {
 }
 
 func TestDetailPaneShowSectionWithComments(t *testing.T) {
-	dp := NewDetailPane(80, 40, "dark")
+	dp := NewDetailPane(80, 40, DefaultTheme())
 	section := &markdown.Section{ID: "S1", Title: "Test Step", Body: "Body"}
 	comments := []*markdown.ReviewComment{
 		{SectionID: "S1", Action: markdown.ActionSuggestion, Body: "Review text"},
@@ -54,8 +54,24 @@ func TestDetailPaneShowSectionWithComments(t *testing.T) {
 	}
 }
 
+func TestDetailPaneShowSectionWithThreads(t *testing.T) {
+	dp := NewDetailPane(80, 40, DefaultTheme())
+	section := &markdown.Section{ID: "S1", Title: "Test Step", Body: "Body"}
+	dp.SetThreads(map[string][]*ReviewThread{
+		"S1": {{Line: 4, Resolved: true, Comments: []ThreadComment{{Author: "alice", Body: "Earlier note"}}}},
+	})
+
+	dp.ShowSection(section, nil)
+	content := dp.View()
+	for _, want := range []string{"Thread (L4) [resolved]", "@alice", "Earlier note"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("view should contain %q, got:\n%s", want, content)
+		}
+	}
+}
+
 func TestDetailPaneShowSectionWithMultipleComments(t *testing.T) {
-	dp := NewDetailPane(80, 40, "dark")
+	dp := NewDetailPane(80, 40, DefaultTheme())
 	section := &markdown.Section{ID: "S1", Title: "Test Step", Body: "Body"}
 	comments := []*markdown.ReviewComment{
 		{SectionID: "S1", Action: markdown.ActionSuggestion, Body: "First"},
@@ -96,7 +112,7 @@ func TestDetailPaneShowOverview(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			dp := NewDetailPane(80, 40, "dark")
+			dp := NewDetailPane(80, 40, DefaultTheme())
 			dp.ShowOverview(tt.doc, tt.comments)
 			content := dp.View()
 			for _, want := range tt.wantContains {
@@ -109,7 +125,7 @@ func TestDetailPaneShowOverview(t *testing.T) {
 }
 
 func TestDetailPaneSetSize(t *testing.T) {
-	dp := NewDetailPane(80, 24, "dark")
+	dp := NewDetailPane(80, 24, DefaultTheme())
 
 	// Same size should be no-op
 	dp.SetSize(80, 24)
@@ -129,7 +145,7 @@ func TestDetailPaneSetSize(t *testing.T) {
 
 func TestDetailPaneHorizontalScroll(t *testing.T) {
 	const width = 40
-	dp := NewDetailPane(width, 40, "dark")
+	dp := NewDetailPane(width, 40, DefaultTheme())
 
 	longCode := "```\n" + strings.Repeat("x", 100) + "\n```"
 	section := &markdown.Section{ID: "S1", Title: "Test", Body: longCode}
@@ -285,7 +301,7 @@ func TestHardWrapCJK(t *testing.T) {
 }
 
 func TestDetailPaneShowAll(t *testing.T) {
-	dp := NewDetailPane(80, 80, "dark")
+	dp := NewDetailPane(80, 80, DefaultTheme())
 	p := &markdown.Document{
 		Title:    "My Plan",
 		Preamble: "Unique preamble content",
@@ -316,7 +332,7 @@ func TestDetailPaneShowAll(t *testing.T) {
 }
 
 func TestDetailPaneShowAllWithComments(t *testing.T) {
-	dp := NewDetailPane(80, 80, "dark")
+	dp := NewDetailPane(80, 80, DefaultTheme())
 	p := &markdown.Document{
 		Title: "Plan",
 		Sections: []*markdown.Section{
@@ -340,7 +356,7 @@ func TestDetailPaneShowAllWithComments(t *testing.T) {
 }
 
 func TestDetailPaneShowAllNoPreamble(t *testing.T) {
-	dp := NewDetailPane(80, 80, "dark")
+	dp := NewDetailPane(80, 80, DefaultTheme())
 	p := &markdown.Document{
 		Title: "NoPreamblePlan",
 		Sections: []*markdown.Section{
@@ -360,7 +376,7 @@ func TestDetailPaneShowAllNoPreamble(t *testing.T) {
 }
 
 func TestBuildSectionOffsets(t *testing.T) {
-	dp := NewDetailPane(80, 80, "dark")
+	dp := NewDetailPane(80, 80, DefaultTheme())
 	p := &markdown.Document{
 		Title:    "Test Plan",
 		Preamble: "Preamble text",
@@ -388,7 +404,7 @@ func TestBuildSectionOffsets(t *testing.T) {
 }
 
 func TestBuildSectionOffsetsWithChildren(t *testing.T) {
-	dp := NewDetailPane(80, 80, "dark")
+	dp := NewDetailPane(80, 80, DefaultTheme())
 	s1 := &markdown.Section{ID: "S1", Title: "Parent", Level: 2, Body: "Body"}
 	s1_1 := &markdown.Section{ID: "S1.1", Title: "Child", Level: 3, Body: "Child body", Parent: s1}
 	s1.Children = []*markdown.Section{s1_1}
@@ -412,7 +428,7 @@ func TestBuildSectionOffsetsWithChildren(t *testing.T) {
 }
 
 func TestSectionIDAtOffset(t *testing.T) {
-	dp := NewDetailPane(80, 80, "dark")
+	dp := NewDetailPane(80, 80, DefaultTheme())
 	dp.sectionOffsets = []sectionOffset{
 		{line: 5, sectionID: "S1"},
 		{line: 20, sectionID: "S2"},
@@ -444,7 +460,7 @@ func TestSectionIDAtOffset(t *testing.T) {
 }
 
 func TestShowSectionClearsSectionOffsets(t *testing.T) {
-	dp := NewDetailPane(80, 40, "dark")
+	dp := NewDetailPane(80, 40, DefaultTheme())
 	dp.sectionOffsets = []sectionOffset{{line: 0, sectionID: "S1"}}
 
 	section := &markdown.Section{ID: "S1", Title: "Test", Body: "Body"}
@@ -456,7 +472,7 @@ func TestShowSectionClearsSectionOffsets(t *testing.T) {
 }
 
 func TestShowOverviewClearsSectionOffsets(t *testing.T) {
-	dp := NewDetailPane(80, 40, "dark")
+	dp := NewDetailPane(80, 40, DefaultTheme())
 	dp.sectionOffsets = []sectionOffset{{line: 0, sectionID: "S1"}}
 
 	p := &markdown.Document{Title: "Plan", Preamble: "Text"}
@@ -468,8 +484,8 @@ func TestShowOverviewClearsSectionOffsets(t *testing.T) {
 }
 
 func TestCustomStyle(t *testing.T) {
-	dark := customStyle("dark")
-	light := customStyle("light")
+	dark := customStyle(DefaultTheme())
+	light := customStyle(builtinThemes[ThemeLight])
 
 	// Both should not have BackgroundColor on Error token
 	if dark.CodeBlock.Chroma != nil && dark.CodeBlock.Chroma.Error.BackgroundColor != nil {
@@ -481,7 +497,7 @@ func TestCustomStyle(t *testing.T) {
 }
 
 func TestRenderCommentBox(t *testing.T) {
-	dp := NewDetailPane(80, 40, "dark")
+	dp := NewDetailPane(80, 40, DefaultTheme())
 	comment := &markdown.ReviewComment{
 		SectionID: "S1",
 		Action:    markdown.ActionSuggestion,
@@ -505,7 +521,7 @@ func TestRenderCommentBox(t *testing.T) {
 }
 
 func TestRenderCommentBoxNumbered(t *testing.T) {
-	dp := NewDetailPane(80, 40, "dark")
+	dp := NewDetailPane(80, 40, DefaultTheme())
 	comment := &markdown.ReviewComment{
 		SectionID: "S1",
 		Action:    markdown.ActionIssue,
@@ -519,7 +535,7 @@ func TestRenderCommentBoxNumbered(t *testing.T) {
 }
 
 func TestRenderCommentBoxEmptyBody(t *testing.T) {
-	dp := NewDetailPane(80, 40, "dark")
+	dp := NewDetailPane(80, 40, DefaultTheme())
 	comment := &markdown.ReviewComment{
 		SectionID: "S1",
 		Action:    markdown.ActionSuggestion,
@@ -532,19 +548,19 @@ func TestRenderCommentBoxEmptyBody(t *testing.T) {
 }
 
 func TestRenderCommentBoxLightTheme(t *testing.T) {
-	dp := NewDetailPane(80, 40, "light")
+	dp := NewDetailPane(80, 40, builtinThemes[ThemeLight])
 	if dp.commentBorderColor() != "33" {
 		t.Errorf("light theme border color = %s, want 33", dp.commentBorderColor())
 	}
 
-	dpDark := NewDetailPane(80, 40, "dark")
+	dpDark := NewDetailPane(80, 40, DefaultTheme())
 	if dpDark.commentBorderColor() != "62" {
 		t.Errorf("dark theme border color = %s, want 62", dpDark.commentBorderColor())
 	}
 }
 
 func TestScrollToSectionID(t *testing.T) {
-	dp := NewDetailPane(80, 10, "dark")
+	dp := NewDetailPane(80, 10, DefaultTheme())
 	// Set enough content so viewport allows scrolling
 	lines := make([]string, 100)
 	for i := range lines {
@@ -582,7 +598,7 @@ func TestScrollToSectionID(t *testing.T) {
 }
 
 func TestRenderCommentBoxWithDecoration(t *testing.T) {
-	dp := NewDetailPane(80, 40, "dark")
+	dp := NewDetailPane(80, 40, DefaultTheme())
 	comment := &markdown.ReviewComment{
 		SectionID:  "S1",
 		Action:     markdown.ActionSuggestion,
@@ -600,7 +616,7 @@ func TestRenderCommentBoxWithDecoration(t *testing.T) {
 }
 
 func TestInsertCommentBoxes(t *testing.T) {
-	dp := NewDetailPane(80, 80, "dark")
+	dp := NewDetailPane(80, 80, DefaultTheme())
 	p := &markdown.Document{
 		Title: "Plan",
 		Sections: []*markdown.Section{
@@ -1,6 +1,20 @@
 package tui
 
-import "github.com/charmbracelet/bubbles/key"
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+)
+
+// Key map presets.
+const (
+	KeyPresetVim   = "vim"
+	KeyPresetEmacs = "emacs"
+)
 
 // KeyMap defines all key bindings for the TUI.
 type KeyMap struct {
@@ -13,6 +27,7 @@ type KeyMap struct {
 	Comment     key.Binding
 	CommentList key.Binding
 	Viewed      key.Binding
+	Verdict     key.Binding
 	Search      key.Binding
 	Submit      key.Binding
 	Quit        key.Binding
@@ -23,6 +38,10 @@ type KeyMap struct {
 	// View mode
 	FullView key.Binding
 
+	// Jump to first/last item. A single-character key must be pressed twice (e.g. "gg").
+	Top    key.Binding
+	Bottom key.Binding
+
 	// Right pane specific
 	ScrollToStart key.Binding
 	ScrollToEnd   key.Binding
@@ -41,125 +60,279 @@ type KeyMap struct {
 	Edit   key.Binding
 	Delete key.Binding
 
+	// Comment editor specific
+	CycleLabel        key.Binding
+	CycleLabelReverse key.Binding
+	CycleDecoration   key.Binding
+
 	// Line mode
 	RawView      key.Binding
 	VisualSelect key.Binding
+	Suggest      key.Binding
+	DiffView     key.Binding
+	ExpandUp     key.Binding
+	ExpandDown   key.Binding
+
+	// PR mode
+	HideResolved key.Binding
+	ThreadList   key.Binding
+	Reply        key.Binding
+	Resolve      key.Binding
 }
 
-// DefaultKeyMap returns the default key bindings.
+// binding creates a key binding whose help key is its first key.
+func binding(desc string, keys ...string) key.Binding {
+	return key.NewBinding(
+		key.WithKeys(keys...),
+		key.WithHelp(keys[0], desc),
+	)
+}
+
+// DefaultKeyMap returns the default (vim-style) key bindings.
 func DefaultKeyMap() KeyMap {
 	return KeyMap{
-		Up: key.NewBinding(
-			key.WithKeys("up", "k"),
-			key.WithHelp("j/k", "navigate"),
-		),
-		Down: key.NewBinding(
-			key.WithKeys("down", "j"),
-			key.WithHelp("", ""),
-		),
-		ScrollRight: key.NewBinding(
-			key.WithKeys("right", "l"),
-			key.WithHelp("h/l", "scroll left/right"),
-		),
-		ScrollLeft: key.NewBinding(
-			key.WithKeys("left", "h"),
-			key.WithHelp("", ""),
-		),
-		Toggle: key.NewBinding(
-			key.WithKeys("enter"),
-			key.WithHelp("enter", "toggle"),
-		),
-		Tab: key.NewBinding(
-			key.WithKeys("tab"),
-			key.WithHelp("tab", "switch pane"),
-		),
-		Comment: key.NewBinding(
-			key.WithKeys("c"),
-			key.WithHelp("c", "comment"),
-		),
-		CommentList: key.NewBinding(
-			key.WithKeys("C"),
-			key.WithHelp("C", "manage comments"),
-		),
-		Viewed: key.NewBinding(
-			key.WithKeys("v"),
-			key.WithHelp("v", "viewed"),
-		),
-		Search: key.NewBinding(
-			key.WithKeys("/"),
-			key.WithHelp("/", "search"),
-		),
-		Submit: key.NewBinding(
-			key.WithKeys("s"),
-			key.WithHelp("s", "submit"),
-		),
-		Quit: key.NewBinding(
-			key.WithKeys("q", "ctrl+c"),
-			key.WithHelp("q", "quit"),
-		),
-		Help: key.NewBinding(
-			key.WithKeys("?"),
-			key.WithHelp("?", "help"),
-		),
-		Save: key.NewBinding(
-			key.WithKeys("ctrl+s"),
-			key.WithHelp("ctrl+s", "save comment"),
-		),
-		Cancel: key.NewBinding(
-			key.WithKeys("esc"),
-			key.WithHelp("esc", "cancel"),
-		),
-		FullView: key.NewBinding(
-			key.WithKeys("f"),
-			key.WithHelp("f", "full/section view"),
-		),
-		ScrollToStart: key.NewBinding(
-			key.WithKeys("H"),
-			key.WithHelp("H", "scroll to start"),
-		),
-		ScrollToEnd: key.NewBinding(
-			key.WithKeys("L"),
-			key.WithHelp("L", "scroll to end"),
-		),
-		PaneGrow: key.NewBinding(
-			key.WithKeys(">"),
-			key.WithHelp(">/<", "resize pane"),
-		),
-		PaneShrink: key.NewBinding(
-			key.WithKeys("<"),
-			key.WithHelp("", ""),
-		),
-		HalfPageDown: key.NewBinding(
-			key.WithKeys("ctrl+d"),
-			key.WithHelp("ctrl+d/u", "half page"),
-		),
-		HalfPageUp: key.NewBinding(
-			key.WithKeys("ctrl+u"),
-			key.WithHelp("", ""),
-		),
-		PageDown: key.NewBinding(
-			key.WithKeys("ctrl+f"),
-			key.WithHelp("ctrl+f/b", "full page"),
-		),
-		PageUp: key.NewBinding(
-			key.WithKeys("ctrl+b"),
-			key.WithHelp("", ""),
-		),
-		Edit: key.NewBinding(
-			key.WithKeys("e"),
-			key.WithHelp("e", "edit"),
-		),
-		Delete: key.NewBinding(
-			key.WithKeys("d"),
-			key.WithHelp("d", "delete"),
-		),
-		RawView: key.NewBinding(
-			key.WithKeys("r"),
-			key.WithHelp("r", "raw/rendered"),
-		),
-		VisualSelect: key.NewBinding(
-			key.WithKeys("V"),
-			key.WithHelp("V", "visual select"),
-		),
+		Up:                binding("move up", "k", "up"),
+		Down:              binding("move down", "j", "down"),
+		ScrollRight:       binding("scroll right", "l", "right"),
+		ScrollLeft:        binding("scroll left", "h", "left"),
+		Toggle:            binding("toggle", "enter"),
+		Tab:               binding("switch pane", "tab"),
+		Comment:           binding("comment", "c"),
+		CommentList:       binding("manage comments", "C"),
+		Viewed:            binding("viewed", "v"),
+		Verdict:           binding("verdict", "a"),
+		HideResolved:      binding("hide resolved threads", "R"),
+		ThreadList:        binding("review threads", "T"),
+		Reply:             binding("reply", "r"),
+		Resolve:           binding("resolve/unresolve", "x"),
+		Search:            binding("search", "/"),
+		Submit:            binding("submit", "s"),
+		Quit:              binding("quit", "q", "ctrl+c"),
+		Help:              binding("help", "?"),
+		Save:              binding("save comment", "ctrl+s"),
+		Cancel:            binding("cancel", "esc"),
+		FullView:          binding("full/section view", "f"),
+		Top:               binding("go to top", "g"),
+		Bottom:            binding("go to bottom", "G"),
+		ScrollToStart:     binding("scroll to start", "H"),
+		ScrollToEnd:       binding("scroll to end", "L"),
+		PaneGrow:          binding("grow left pane", ">"),
+		PaneShrink:        binding("shrink left pane", "<"),
+		HalfPageDown:      binding("half page down", "ctrl+d"),
+		HalfPageUp:        binding("half page up", "ctrl+u"),
+		PageDown:          binding("full page down", "ctrl+f"),
+		PageUp:            binding("full page up", "ctrl+b"),
+		Edit:              binding("edit", "e"),
+		Delete:            binding("delete", "d"),
+		CycleLabel:        binding("cycle label", "tab"),
+		CycleLabelReverse: binding("cycle label (reverse)", "shift+tab"),
+		CycleDecoration:   binding("cycle decoration", "ctrl+d"),
+		RawView:           binding("raw/rendered", "r"),
+		VisualSelect:      binding("visual select", "V"),
+		Suggest:           binding("suggest change", "p"),
+		DiffView:          binding("hunks/full file", "D"),
+		ExpandUp:          binding("expand context above", "["),
+		ExpandDown:        binding("expand context below", "]"),
+	}
+}
+
+// EmacsKeyMap returns emacs-style key bindings. Keys without an obvious emacs
+// counterpart keep their default binding.
+func EmacsKeyMap() KeyMap {
+	km := DefaultKeyMap()
+	km.Up = binding("move up", "ctrl+p", "up")
+	km.Down = binding("move down", "ctrl+n", "down")
+	km.ScrollRight = binding("scroll right", "ctrl+f", "right")
+	km.ScrollLeft = binding("scroll left", "ctrl+b", "left")
+	km.Search = binding("search", "ctrl+s", "/")
+	km.Cancel = binding("cancel", "esc", "ctrl+g")
+	km.Top = binding("go to top", "alt+<", "home")
+	km.Bottom = binding("go to bottom", "alt+>", "end")
+	km.ScrollToStart = binding("scroll to start", "ctrl+a")
+	km.ScrollToEnd = binding("scroll to end", "ctrl+e")
+	km.HalfPageDown = binding("half page down", "alt+n")
+	km.HalfPageUp = binding("half page up", "alt+p")
+	km.PageDown = binding("full page down", "ctrl+v", "pgdown")
+	km.PageUp = binding("full page up", "alt+v", "pgup")
+	km.CycleDecoration = binding("cycle decoration", "ctrl+o")
+	km.VisualSelect = binding("visual select", "ctrl+@", "V")
+	return km
+}
+
+// NewKeyMap returns the key map for preset ("" means vim) with overrides
+// applied. overrides maps binding names (e.g. "comment-list") to their new keys.
+// Returns an error if a name or preset is unknown or if two bindings in the
+// same mode share a key.
+func NewKeyMap(preset string, overrides map[string][]string) (KeyMap, error) {
+	var km KeyMap
+	switch preset {
+	case "", KeyPresetVim:
+		km = DefaultKeyMap()
+	case KeyPresetEmacs:
+		km = EmacsKeyMap()
+	default:
+		return KeyMap{}, fmt.Errorf("unknown key preset %q (want %s or %s)", preset, KeyPresetVim, KeyPresetEmacs)
+	}
+
+	bindings := km.named()
+	for _, name := range slices.Sorted(maps.Keys(overrides)) {
+		keys := overrides[name]
+		b, ok := bindings[name]
+		if !ok {
+			return KeyMap{}, fmt.Errorf("unknown key binding %q", name)
+		}
+		if len(keys) == 0 || slices.Contains(keys, "") {
+			return KeyMap{}, fmt.Errorf("key binding %q: keys must not be empty", name)
+		}
+		b.SetKeys(keys...)
+		b.SetHelp(keys[0], b.Help().Desc)
+	}
+
+	if err := km.Validate(); err != nil {
+		return KeyMap{}, err
+	}
+	return km, nil
+}
+
+// keyModes lists, for each input mode, the bindings that are active together.
+var keyModes = []struct {
+	name     string
+	bindings []string
+}{
+	{"normal", []string{
+		"up", "down", "scroll-right", "scroll-left", "toggle", "switch-pane",
+		"comment", "comment-list", "viewed", "verdict", "search", "submit", "quit", "help",
+		"full-view", "top", "bottom", "scroll-to-start", "scroll-to-end",
+		"pane-grow", "pane-shrink", "half-page-down", "half-page-up",
+		"page-down", "page-up", "raw-view", "visual-select", "suggest", "hide-resolved",
+		"thread-list", "diff-view", "expand-up", "expand-down",
+	}},
+	{"comment", []string{"save", "cancel", "cycle-label", "cycle-label-reverse", "cycle-decoration"}},
+	{"comment list", []string{"up", "down", "edit", "delete", "cancel"}},
+	{"thread list", []string{"up", "down", "reply", "resolve", "delete", "cancel"}},
+	{"visual select", []string{"up", "down", "comment", "suggest", "comment-list", "cancel"}},
+}
+
+// named returns pointers to the bindings of km keyed by binding name.
+func (km *KeyMap) named() map[string]*key.Binding {
+	return map[string]*key.Binding{
+		"up":                  &km.Up,
+		"down":                &km.Down,
+		"scroll-right":        &km.ScrollRight,
+		"scroll-left":         &km.ScrollLeft,
+		"toggle":              &km.Toggle,
+		"switch-pane":         &km.Tab,
+		"comment":             &km.Comment,
+		"comment-list":        &km.CommentList,
+		"viewed":              &km.Viewed,
+		"verdict":             &km.Verdict,
+		"hide-resolved":       &km.HideResolved,
+		"thread-list":         &km.ThreadList,
+		"reply":               &km.Reply,
+		"resolve":             &km.Resolve,
+		"search":              &km.Search,
+		"submit":              &km.Submit,
+		"quit":                &km.Quit,
+		"help":                &km.Help,
+		"save":                &km.Save,
+		"cancel":              &km.Cancel,
+		"full-view":           &km.FullView,
+		"top":                 &km.Top,
+		"bottom":              &km.Bottom,
+		"scroll-to-start":     &km.ScrollToStart,
+		"scroll-to-end":       &km.ScrollToEnd,
+		"pane-grow":           &km.PaneGrow,
+		"pane-shrink":         &km.PaneShrink,
+		"half-page-down":      &km.HalfPageDown,
+		"half-page-up":        &km.HalfPageUp,
+		"page-down":           &km.PageDown,
+		"page-up":             &km.PageUp,
+		"edit":                &km.Edit,
+		"delete":              &km.Delete,
+		"cycle-label":         &km.CycleLabel,
+		"cycle-label-reverse": &km.CycleLabelReverse,
+		"cycle-decoration":    &km.CycleDecoration,
+		"raw-view":            &km.RawView,
+		"visual-select":       &km.VisualSelect,
+		"suggest":             &km.Suggest,
+		"diff-view":           &km.DiffView,
+		"expand-up":           &km.ExpandUp,
+		"expand-down":         &km.ExpandDown,
+	}
+}
+
+// Validate reports keys bound to more than one binding within the same mode.
+// Comment-mode bindings must also not use plain printable keys, which would
+// make those characters impossible to type.
+func (km KeyMap) Validate() error {
+	bindings := km.named()
+	var errs []error
+	for _, mode := range keyModes {
+		owner := make(map[string]string)
+		for _, name := range mode.bindings {
+			for _, k := range bindings[name].Keys() {
+				if prev, ok := owner[k]; ok && prev != name {
+					errs = append(errs, fmt.Errorf("key %q is bound to both %s and %s in %s mode", k, prev, name, mode.name))
+					continue
+				}
+				owner[k] = name
+				if mode.name == "comment" && isPrintableKey(k) {
+					errs = append(errs, fmt.Errorf("key %q for %s would block typing %q in the comment editor", k, name, k))
+				}
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// isPrintableKey reports whether k is a key that inserts text (a single character or space).
+func isPrintableKey(k string) bool {
+	return k == "space" || k == " " || len([]rune(k)) == 1
+}
+
+// keyHint returns the status bar hint for bindings, e.g. "j/k".
+func keyHint(bindings ...key.Binding) string {
+	parts := make([]string, 0, len(bindings))
+	for _, b := range bindings {
+		if keys := b.Keys(); len(keys) > 0 {
+			parts = append(parts, keys[0])
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+// helpKeys returns the help overlay notation for bindings, e.g. "j/k, Down/Up":
+// the n-th keys of each binding are joined with "/", and alternatives with ", ".
+func helpKeys(bindings ...key.Binding) string {
+	var groups []string
+	for i := 0; ; i++ {
+		var parts []string
+		for _, b := range bindings {
+			if keys := b.Keys(); i < len(keys) {
+				parts = append(parts, displayKey(keys[i]))
+			}
+		}
+		if len(parts) == 0 {
+			break
+		}
+		groups = append(groups, strings.Join(parts, "/"))
+	}
+	return strings.Join(groups, ", ")
+}
+
+// displayKey capitalizes named keys for the help overlay ("ctrl+s" -> "Ctrl+S").
+func displayKey(k string) string {
+	if len([]rune(k)) == 1 {
+		return k
+	}
+	parts := strings.Split(k, "+")
+	for i, p := range parts {
+		switch {
+		case len([]rune(p)) == 1:
+			parts[i] = strings.ToUpper(p)
+		case p != "":
+			parts[i] = strings.ToUpper(p[:1]) + p[1:]
+		}
 	}
+	return strings.Join(parts, "+")
 }
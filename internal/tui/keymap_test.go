@@ -0,0 +1,180 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestPresetsHaveNoConflicts(t *testing.T) {
+	for _, preset := range []string{KeyPresetVim, KeyPresetEmacs} {
+		if _, err := NewKeyMap(preset, nil); err != nil {
+			t.Errorf("NewKeyMap(%q) error: %v", preset, err)
+		}
+	}
+}
+
+func TestNewKeyMap(t *testing.T) {
+	tests := []struct {
+		name      string
+		preset    string
+		overrides map[string][]string
+		wantErr   []string
+	}{
+		{
+			name:      "remap submit and comment list",
+			overrides: map[string][]string{"submit": {"S"}, "comment-list": {"ctrl+l"}},
+		},
+		{
+			name:      "remap comment editor keys",
+			overrides: map[string][]string{"save": {"ctrl+x"}, "cycle-decoration": {"ctrl+t"}},
+		},
+		{
+			name:    "unknown preset",
+			preset:  "nano",
+			wantErr: []string{`unknown key preset "nano"`},
+		},
+		{
+			name:      "unknown binding",
+			overrides: map[string][]string{"sumbit": {"S"}},
+			wantErr:   []string{`unknown key binding "sumbit"`},
+		},
+		{
+			name:      "empty key",
+			overrides: map[string][]string{"submit": {""}},
+			wantErr:   []string{"must not be empty"},
+		},
+		{
+			name:      "conflict in normal mode",
+			overrides: map[string][]string{"submit": {"v"}},
+			wantErr:   []string{`key "v" is bound to both viewed and submit in normal mode`},
+		},
+		{
+			name:      "conflicts in several modes",
+			overrides: map[string][]string{"edit": {"d"}, "save": {"esc"}},
+			wantErr: []string{
+				`key "esc" is bound to both save and cancel in comment mode`,
+				`key "d" is bound to both edit and delete in comment list mode`,
+			},
+		},
+		{
+			name:      "same key in different modes is fine",
+			overrides: map[string][]string{"edit": {"s"}},
+		},
+		{
+			name:      "printable key in comment editor",
+			overrides: map[string][]string{"cycle-label": {"x"}},
+			wantErr:   []string{`would block typing "x"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			km, err := NewKeyMap(tt.preset, tt.overrides)
+			if len(tt.wantErr) == 0 {
+				if err != nil {
+					t.Fatalf("NewKeyMap() error: %v", err)
+				}
+				bindings := km.named()
+				for name, keys := range tt.overrides {
+					if got := bindings[name].Keys(); strings.Join(got, ",") != strings.Join(keys, ",") {
+						t.Errorf("%s keys = %v, want %v", name, got, keys)
+					}
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			for _, want := range tt.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error = %q, want to contain %q", err.Error(), want)
+				}
+			}
+		})
+	}
+}
+
+func TestHelpKeys(t *testing.T) {
+	km := DefaultKeyMap()
+	tests := []struct {
+		got, want string
+	}{
+		{helpKeys(km.Down, km.Up), "j/k, Down/Up"},
+		{helpKeys(km.Quit), "q, Ctrl+C"},
+		{helpKeys(km.HalfPageDown, km.HalfPageUp), "Ctrl+D/Ctrl+U"},
+		{helpKeys(km.CycleLabelReverse), "Shift+Tab"},
+		{keyHint(km.Down, km.Up), "j/k"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("got %q, want %q", tt.got, tt.want)
+		}
+	}
+}
+
+func TestRemappedKeys(t *testing.T) {
+	km, err := NewKeyMap("", map[string][]string{
+		"submit":           {"S"},
+		"cycle-decoration": {"ctrl+t"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	app := NewApp(makeLargeDoc(3, 0), AppOptions{KeyMap: &km})
+	app.Update(tea.WindowSizeMsg{Width: 200, Height: 40})
+
+	if !strings.Contains(app.renderStatusBar(), "S submit") {
+		t.Errorf("status bar should show remapped submit key: %q", app.renderStatusBar())
+	}
+
+	app.Update(keyMsg("s"))
+	if app.mode != ModeNormal {
+		t.Errorf("old submit key should do nothing, mode = %v", app.mode)
+	}
+	app.Update(keyMsg("S"))
+	if app.mode != ModeConfirm || app.confirmAction != confirmSubmit {
+		t.Errorf("remapped submit key should open the submit dialog, mode = %v", app.mode)
+	}
+	app.Update(keyMsg("n"))
+
+	app.Update(keyMsg("c"))
+	if app.mode != ModeComment {
+		t.Fatalf("mode = %v, want ModeComment", app.mode)
+	}
+	if bar := app.renderStatusBar(); !strings.Contains(bar, "ctrl+t deco") {
+		t.Errorf("comment status bar should show remapped decoration key: %q", bar)
+	}
+	app.Update(tea.KeyMsg{Type: tea.KeyCtrlT})
+	if got := app.comment.DecorationLabel(); got == "" {
+		t.Error("ctrl+t should cycle the decoration")
+	}
+
+	app.mode = ModeHelp
+	if help := app.renderHelp(); !strings.Contains(help, "Ctrl+T") || strings.Contains(help, "Ctrl+D          Cycle decoration") {
+		t.Errorf("help should list the effective decoration key:\n%s", help)
+	}
+}
+
+func TestEmacsPresetNavigation(t *testing.T) {
+	km := EmacsKeyMap()
+	app := NewApp(makeLargeDoc(5, 0), AppOptions{KeyMap: &km})
+	app.Update(tea.WindowSizeMsg{Width: 120, Height: 30})
+
+	app.Update(tea.KeyMsg{Type: tea.KeyCtrlN})
+	app.Update(tea.KeyMsg{Type: tea.KeyCtrlN})
+	if got := app.sectionList.Selected(); got == nil || got.ID != "S2" {
+		t.Errorf("after ctrl+n twice, selected = %v, want S2", got)
+	}
+
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'>'}, Alt: true})
+	if got := app.sectionList.Selected(); got == nil || got.ID != "S5" {
+		t.Errorf("after alt+>, selected = %v, want S5", got)
+	}
+
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'<'}, Alt: true})
+	if !app.sectionList.IsOverviewSelected() {
+		t.Error("alt+< should jump to the top in a single press")
+	}
+}
@@ -29,12 +29,14 @@ type LinePane struct {
 	gutterWidth   int
 	styles        Styles
 	comments      []*markdown.ReviewComment
+	threads       []*ReviewThread // existing PR review threads (read-only)
 	sectionRanges []sectionRange
 
 	// Diff mode fields (set when reviewing PR diffs)
 	diffLineMap []int    // maps display line index → file line number (0 = not commentable)
 	diffSideMap []string // maps display line index → "RIGHT" or "LEFT"
 	diffTypeMap []byte   // maps display line index → diff line type ('+', '-', ' ')
+	diffOutside []bool   // maps display line index → line outside the patch (nil = none)
 	emptyRange  bool     // true when SetViewRange found no matching diff lines
 }
 
@@ -53,6 +55,33 @@ func NewLinePane(lines []string, width, height int, styles Styles, sections []*m
 	return lp
 }
 
+// setDiff shows the lines of d, mapped through its line, side and type maps,
+// with the cursor back at the top.
+func (lp *LinePane) setDiff(d *DiffData) {
+	lp.lines = d.DisplayLines
+	lp.diffLineMap = d.LineMap
+	lp.diffSideMap = d.SideMap
+	lp.diffTypeMap = d.TypeMap
+	lp.diffOutside = d.Outside
+	// Gutter width from the largest file line number
+	maxLine := 1
+	for _, l := range d.LineMap {
+		maxLine = max(maxLine, l)
+	}
+	lp.gutterWidth = len(fmt.Sprintf("%d", maxLine)) + 1
+	lp.cursor = 0
+	lp.scrollOffset = 0
+	lp.selectAnchor = -1
+	lp.ClearViewRange()
+}
+
+// setCursor moves the cursor to display index i within the visible range.
+func (lp *LinePane) setCursor(i int) {
+	lp.cursor = i
+	lp.clampCursor()
+	lp.ensureVisible()
+}
+
 func (lp *LinePane) buildSectionRanges(sections []*markdown.Section) {
 	lp.sectionRanges = nil
 	for _, s := range sections {
@@ -286,6 +315,21 @@ func (lp *LinePane) CanComment() bool {
 	return false
 }
 
+// SelectionOutsideDiff reports whether the cursor line, or any line of the
+// visual selection, is outside the PR patch (full-file or expanded view).
+func (lp *LinePane) SelectionOutsideDiff() bool {
+	lo, hi := lp.cursor, lp.cursor
+	if lp.selectAnchor >= 0 {
+		lo, hi = min(lp.selectAnchor, lp.cursor), max(lp.selectAnchor, lp.cursor)
+	}
+	for i := lo; i <= hi && i < len(lp.diffOutside); i++ {
+		if lp.diffOutside[i] {
+			return true
+		}
+	}
+	return false
+}
+
 // ScrollToLine scrolls the viewport so the given 1-based line is visible,
 // centered if possible.
 func (lp *LinePane) ScrollToLine(line int) {
@@ -339,6 +383,11 @@ func (lp *LinePane) SetComments(comments []*markdown.ReviewComment) {
 	lp.comments = comments
 }
 
+// SetThreads sets the existing review threads for inline display.
+func (lp *LinePane) SetThreads(threads []*ReviewThread) {
+	lp.threads = threads
+}
+
 // View renders the line pane content.
 func (lp *LinePane) View() string {
 	if lp.emptyRange {
@@ -413,24 +462,27 @@ func (lp *LinePane) View() string {
 			sb.WriteString("\n")
 		}
 
-		// Render inline comment boxes after their target lines
-		if comments, ok := commentMap[lineNum]; ok && linesRendered < lp.height {
-			for _, c := range comments {
+		// Render inline comment boxes, then other reviewers' threads, after their target lines
+		var boxes []string
+		for _, c := range commentMap[lineNum] {
+			boxes = append(boxes, lp.renderInlineCommentBox(c, contentWidth))
+		}
+		for _, t := range lp.threadsAt(i, lineNum) {
+			boxes = append(boxes, renderThread(t, max(contentWidth-4, 10), lp.styles.ThreadBorder))
+		}
+		for _, box := range boxes {
+			if linesRendered >= lp.height {
+				break
+			}
+			for boxLine := range strings.SplitSeq(box, "\n") {
 				if linesRendered >= lp.height {
 					break
 				}
-				box := lp.renderInlineCommentBox(c, contentWidth)
-				boxLines := strings.SplitSeq(box, "\n")
-				for boxLine := range boxLines {
-					if linesRendered >= lp.height {
-						break
-					}
-					padding := strings.Repeat(" ", lp.gutterWidth+2)
-					sb.WriteString(padding + " " + boxLine)
-					linesRendered++
-					if linesRendered < lp.height {
-						sb.WriteString("\n")
-					}
+				padding := strings.Repeat(" ", lp.gutterWidth+2)
+				sb.WriteString(padding + " " + boxLine)
+				linesRendered++
+				if linesRendered < lp.height {
+					sb.WriteString("\n")
 				}
 			}
 		}
@@ -500,8 +552,8 @@ func (lp *LinePane) isInSelection(idx int) bool {
 func (lp *LinePane) buildCommentMap() map[int][]*markdown.ReviewComment {
 	m := make(map[int][]*markdown.ReviewComment)
 	for _, c := range lp.comments {
-		if c.StartLine == 0 {
-			continue // section-level comment, skip
+		if c.StartLine == 0 || c.Orphaned {
+			continue // section-level or orphaned comment, skip
 		}
 		displayLine := c.StartLine
 		if c.EndLine > 0 {
@@ -512,6 +564,25 @@ func (lp *LinePane) buildCommentMap() map[int][]*markdown.ReviewComment {
 	return m
 }
 
+// threadsAt returns the threads attached to display index idx, whose file
+// line is lineNum. In diff mode the thread's side must match the line's.
+func (lp *LinePane) threadsAt(idx, lineNum int) []*ReviewThread {
+	if lineNum == 0 {
+		return nil
+	}
+	side := ""
+	if idx < len(lp.diffSideMap) {
+		side = lp.diffSideMap[idx]
+	}
+	var threads []*ReviewThread
+	for _, t := range lp.threads {
+		if t.Line == lineNum && (side == "" || t.Side == "" || t.Side == side) {
+			threads = append(threads, t)
+		}
+	}
+	return threads
+}
+
 func (lp *LinePane) renderInlineCommentBox(c *markdown.ReviewComment, maxWidth int) string {
 	lineRef := c.FormatLineRef()
 	header := fmt.Sprintf("Review Comment [%s]", c.FormatLabel())
@@ -520,8 +591,8 @@ func (lp *LinePane) renderInlineCommentBox(c *markdown.ReviewComment, maxWidth i
 	}
 
 	content := header
-	if c.Body != "" {
-		content += "\n" + c.Body
+	if body := c.BodyWithSuggestion(); body != "" {
+		content += "\n" + body
 	}
 
 	// maxWidth is contentWidth (pane width minus gutter).
@@ -9,7 +9,7 @@ import (
 )
 
 func newTestLinePane(lines []string, sections []*markdown.Section) *LinePane {
-	styles := stylesForTheme(ThemeDark)
+	styles := stylesForTheme(DefaultTheme())
 	return NewLinePane(lines, 40, 10, styles, sections)
 }
 
@@ -359,6 +359,28 @@ func TestLinePaneDiffMode(t *testing.T) {
 	}
 }
 
+func TestLinePaneThreads(t *testing.T) {
+	lines := []string{" context", "-removed", "+added", " more"}
+	lp := NewLinePane(lines, 60, 30, stylesForTheme(DefaultTheme()), nil)
+	lp.diffLineMap = []int{1, 1, 2, 3}
+	lp.diffSideMap = []string{"RIGHT", "LEFT", "RIGHT", "RIGHT"}
+	lp.SetThreads([]*ReviewThread{
+		{Line: 1, Side: "LEFT", Comments: []ThreadComment{{Author: "alice", Body: "old text"}}},
+		{Line: 3, Side: "RIGHT", Comments: []ThreadComment{{Author: "bob", Body: "new text"}}},
+	})
+
+	view := lp.View()
+	removed := strings.Index(view, "-removed")
+	added := strings.Index(view, "+added")
+	alice := strings.Index(view, "@alice")
+	if alice < removed || alice > added {
+		t.Errorf("LEFT thread should follow the removed line, not the context line with the same number:\n%s", view)
+	}
+	if bob := strings.Index(view, "@bob"); bob < strings.Index(view, " more") {
+		t.Errorf("RIGHT thread should follow line 3:\n%s", view)
+	}
+}
+
 func TestLinePaneDiffModeSetViewRange(t *testing.T) {
 	lines := []string{" ctx1", "+add", "-del", " ctx2", " ctx3", "+add2"}
 	lp := newTestLinePane(lines, nil)
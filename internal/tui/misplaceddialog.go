@@ -0,0 +1,118 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// MisplacedAction is the user's choice for comments outside the PR diff.
+type MisplacedAction int
+
+const (
+	MisplacedCancel  MisplacedAction = iota // do not submit
+	MisplacedNearest                        // move to the nearest commentable line
+	MisplacedBody                           // move to the review body
+)
+
+// MisplacedDialog is a Bubble Tea model asking what to do with review
+// comments whose lines are not in the PR diff, which GitHub refuses.
+type MisplacedDialog struct {
+	comments []string // one line per comment, e.g. "a.md L3 → L5"
+	options  []string
+	actions  []MisplacedAction
+	cursor   int
+	result   MisplacedAction
+	quitting bool
+	width    int
+	height   int
+}
+
+// NewMisplacedDialog creates the dialog. comments describes each misplaced
+// comment; canRemap offers moving them to the nearest commentable line.
+func NewMisplacedDialog(comments []string, canRemap bool) *MisplacedDialog {
+	d := &MisplacedDialog{comments: comments}
+	if canRemap {
+		d.options = append(d.options, "Move to the nearest line in the diff")
+		d.actions = append(d.actions, MisplacedNearest)
+	}
+	d.options = append(d.options, "Move to the review comment", "Cancel")
+	d.actions = append(d.actions, MisplacedBody, MisplacedCancel)
+	return d
+}
+
+// Result returns the chosen action.
+func (d *MisplacedDialog) Result() MisplacedAction {
+	return d.result
+}
+
+// Init implements tea.Model.
+func (d *MisplacedDialog) Init() tea.Cmd {
+	return nil
+}
+
+// Update implements tea.Model.
+func (d *MisplacedDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		d.width = msg.Width
+		d.height = msg.Height
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, key.NewBinding(key.WithKeys("q", "esc", "ctrl+c"))):
+			d.result = MisplacedCancel
+			d.quitting = true
+			return d, tea.Quit
+		case key.Matches(msg, key.NewBinding(key.WithKeys("j", "down"))):
+			if d.cursor < len(d.options)-1 {
+				d.cursor++
+			}
+		case key.Matches(msg, key.NewBinding(key.WithKeys("k", "up"))):
+			if d.cursor > 0 {
+				d.cursor--
+			}
+		case key.Matches(msg, key.NewBinding(key.WithKeys("enter"))):
+			d.result = d.actions[d.cursor]
+			d.quitting = true
+			return d, tea.Quit
+		}
+	}
+	return d, nil
+}
+
+// View implements tea.Model.
+func (d *MisplacedDialog) View() string {
+	if d.quitting {
+		return ""
+	}
+
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	var content strings.Builder
+	content.WriteString("GitHub refuses comments on lines outside the diff:\n\n")
+	for _, c := range d.comments {
+		content.WriteString("  " + c + "\n")
+	}
+	content.WriteString("\n")
+	for i, opt := range d.options {
+		line := "  " + opt
+		if i == d.cursor {
+			line = lipgloss.NewStyle().Foreground(lipgloss.Color("14")).Bold(true).Render("▸ " + opt)
+		}
+		content.WriteString(line + "\n")
+	}
+	content.WriteString("\n")
+	content.WriteString(dim.Render("↑/↓") + " navigate  " + dim.Render("enter") + " select  " + dim.Render("q") + " cancel")
+
+	dialog := lipgloss.NewStyle().
+		Width(min(d.width-4, 60)).
+		Padding(1, 2).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("170")).
+		Render(content.String())
+
+	return lipgloss.Place(d.width, d.height,
+		lipgloss.Center, lipgloss.Center,
+		dialog)
+}
@@ -0,0 +1,46 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestMisplacedDialog(t *testing.T) {
+	tests := []struct {
+		name     string
+		canRemap bool
+		keys     []tea.KeyMsg
+		want     MisplacedAction
+	}{
+		{"nearest", true, []tea.KeyMsg{keyMsg("enter")}, MisplacedNearest},
+		{"body", true, []tea.KeyMsg{keyMsg("j"), keyMsg("enter")}, MisplacedBody},
+		{"body without remap", false, []tea.KeyMsg{keyMsg("enter")}, MisplacedBody},
+		{"cancel option", false, []tea.KeyMsg{keyMsg("j"), keyMsg("j"), keyMsg("enter")}, MisplacedCancel},
+		{"cancel with q", true, []tea.KeyMsg{keyMsg("q")}, MisplacedCancel},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var model tea.Model = NewMisplacedDialog([]string{"a.md L3 → L5"}, tt.canRemap)
+			model, _ = model.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+			for _, k := range tt.keys {
+				model, _ = model.Update(k)
+			}
+			if got := model.(*MisplacedDialog).Result(); got != tt.want {
+				t.Errorf("Result() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMisplacedDialogView(t *testing.T) {
+	d := NewMisplacedDialog([]string{"a.md L3 → L5", "a.md L40 → review comment"}, true)
+	d.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	view := d.View()
+	for _, want := range []string{"a.md L3 → L5", "a.md L40 → review comment", "nearest line"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("View should contain %q, got %q", want, view)
+		}
+	}
+}
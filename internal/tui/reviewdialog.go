@@ -2,6 +2,7 @@ package tui
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/key"
@@ -14,9 +15,11 @@ import (
 type ReviewAction int
 
 const (
-	ReviewActionExit    ReviewAction = iota // do nothing
-	ReviewActionApprove                     // approve the PR
-	ReviewActionComment                     // submit as comment
+	ReviewActionExit           ReviewAction = iota // do nothing
+	ReviewActionApprove                            // approve the PR
+	ReviewActionComment                            // submit as comment
+	ReviewActionPending                            // save as a pending review without submitting
+	ReviewActionRequestChanges                     // request changes
 )
 
 // ReviewDialogResult holds the outcome of the review dialog.
@@ -36,8 +39,12 @@ const (
 // ReviewDialog is a Bubble Tea model for the post-review action selection.
 type ReviewDialog struct {
 	// Input
-	fileSummary []string // "file.md: N comment(s)" lines
-	hasComments bool
+	fileSummary     []string // "file.md: N comment(s)" lines
+	hasComments     bool
+	approvalBlocked []string // why the submit policy refuses approval (nil = allowed)
+	changesReasons  []string // why Request changes is preselected
+	changesNeedBody bool     // Request changes needs a PR comment (see RequireChangesBody)
+	ownPR           bool     // the viewer authored the PR: no Approve or Request changes
 
 	// State
 	mode     reviewDialogMode
@@ -66,8 +73,8 @@ func NewReviewDialog(fileSummary []string, hasComments bool) *ReviewDialog {
 	}
 
 	if hasComments {
-		d.options = []string{"Comment", "Approve", "Exit"}
-		d.actions = []ReviewAction{ReviewActionComment, ReviewActionApprove, ReviewActionExit}
+		d.options = []string{"Comment", "Approve", "Request changes", "Save as pending", "Exit"}
+		d.actions = []ReviewAction{ReviewActionComment, ReviewActionApprove, ReviewActionRequestChanges, ReviewActionPending, ReviewActionExit}
 	} else {
 		d.options = []string{"Approve", "Exit"}
 		d.actions = []ReviewAction{ReviewActionApprove, ReviewActionExit}
@@ -76,6 +83,54 @@ func NewReviewDialog(fileSummary []string, hasComments bool) *ReviewDialog {
 	return d
 }
 
+// BlockApproval refuses the Approve option, showing reasons under it.
+func (d *ReviewDialog) BlockApproval(reasons []string) {
+	d.approvalBlocked = reasons
+}
+
+// SuggestRequestChanges preselects the Request changes option, showing
+// reasons under it. It has no effect without comments or after HideVerdicts.
+func (d *ReviewDialog) SuggestRequestChanges(reasons []string) {
+	if i := slices.Index(d.actions, ReviewActionRequestChanges); i >= 0 && len(reasons) > 0 {
+		d.changesReasons = reasons
+		d.cursor = i
+	}
+}
+
+// RequireChangesBody makes Request changes require a PR comment, which GitHub
+// needs when nothing else is posted with the review, e.g. when it only
+// resolves threads.
+func (d *ReviewDialog) RequireChangesBody() {
+	d.changesNeedBody = true
+}
+
+// HideVerdicts removes the Approve and Request changes options, which GitHub
+// refuses on the viewer's own PR. A Comment option is offered instead when
+// there are no comments.
+func (d *ReviewDialog) HideVerdicts() {
+	d.ownPR = true
+	d.changesReasons = nil
+	d.cursor = 0
+	var options []string
+	var actions []ReviewAction
+	for i, a := range d.actions {
+		if a != ReviewActionApprove && a != ReviewActionRequestChanges {
+			options = append(options, d.options[i])
+			actions = append(actions, a)
+		}
+	}
+	if !slices.Contains(actions, ReviewActionComment) {
+		options = append([]string{"Comment"}, options...)
+		actions = append([]ReviewAction{ReviewActionComment}, actions...)
+	}
+	d.options, d.actions = options, actions
+}
+
+// SetBody pre-fills the PR comment, e.g. with the body of a pending review.
+func (d *ReviewDialog) SetBody(body string) {
+	d.textarea.SetValue(body)
+}
+
 // Result returns the dialog result.
 func (d *ReviewDialog) Result() ReviewDialogResult {
 	return d.result
@@ -124,7 +179,10 @@ func (d *ReviewDialog) updateSelect(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			d.quitting = true
 			return d, tea.Quit
 		}
-		// Approve or Comment: move to body input
+		if action == ReviewActionApprove && len(d.approvalBlocked) > 0 {
+			return d, nil
+		}
+		// Approve, Comment or Save as pending: move to body input
 		d.result.Action = action
 		d.mode = dialogModeBody
 		return d, d.textarea.Focus()
@@ -139,7 +197,11 @@ func (d *ReviewDialog) updateBody(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		d.textarea.Blur()
 		return d, nil
 	case key.Matches(msg, key.NewBinding(key.WithKeys("ctrl+s"))):
-		d.result.Body = strings.TrimSpace(d.textarea.Value())
+		body := strings.TrimSpace(d.textarea.Value())
+		if body == "" && d.bodyRequired() {
+			return d, nil
+		}
+		d.result.Body = body
 		d.quitting = true
 		return d, tea.Quit
 	}
@@ -149,6 +211,20 @@ func (d *ReviewDialog) updateBody(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return d, cmd
 }
 
+// bodyRequired reports whether the chosen action cannot be submitted without
+// a PR comment.
+func (d *ReviewDialog) bodyRequired() bool {
+	return d.result.Action == ReviewActionRequestChanges && d.changesNeedBody
+}
+
+// saveLabel returns the body input's hint for ctrl+s.
+func (d *ReviewDialog) saveLabel() string {
+	if d.result.Action == ReviewActionPending {
+		return "save"
+	}
+	return "submit"
+}
+
 // View implements tea.Model.
 func (d *ReviewDialog) View() string {
 	if d.quitting {
@@ -179,6 +255,22 @@ func (d *ReviewDialog) View() string {
 					Render(line)
 			}
 			content.WriteString(line + "\n")
+			switch d.actions[i] {
+			case ReviewActionApprove:
+				for _, reason := range d.approvalBlocked {
+					content.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("8")).
+						Render("    ✗ "+reason) + "\n")
+				}
+			case ReviewActionRequestChanges:
+				for _, reason := range d.changesReasons {
+					content.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("8")).
+						Render("    ! "+reason) + "\n")
+				}
+			}
+		}
+		if d.ownPR {
+			content.WriteString("\n" + lipgloss.NewStyle().Foreground(lipgloss.Color("8")).
+				Render("Approve and Request changes are not available on your own PR.") + "\n")
 		}
 		content.WriteString("\n")
 		content.WriteString(
@@ -191,15 +283,24 @@ func (d *ReviewDialog) View() string {
 		)
 	} else {
 		action := "Comment"
-		if d.result.Action == ReviewActionApprove {
+		switch d.result.Action {
+		case ReviewActionApprove:
 			action = "Approve"
+		case ReviewActionRequestChanges:
+			action = "Request changes"
+		case ReviewActionPending:
+			action = "Save as pending"
 		}
 		fmt.Fprintf(&content, "Action: %s\n\n", lipgloss.NewStyle().Bold(true).Render(action))
 		content.WriteString(d.textarea.View())
 		content.WriteString("\n\n")
+		if d.bodyRequired() {
+			content.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("8")).
+				Render("A PR comment is required: the review has no comments.") + "\n\n")
+		}
 		content.WriteString(
 			lipgloss.NewStyle().Foreground(lipgloss.Color("8")).
-				Render("ctrl+s") + " submit  " +
+				Render("ctrl+s") + " " + d.saveLabel() + "  " +
 				lipgloss.NewStyle().Foreground(lipgloss.Color("8")).
 					Render("esc") + " back",
 		)
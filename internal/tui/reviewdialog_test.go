@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"slices"
 	"strings"
 	"testing"
 
@@ -45,6 +46,22 @@ func TestReviewDialog(t *testing.T) {
 			keys:        []tea.KeyMsg{keyMsg("j"), keyMsg("enter"), ctrlKeyMsg(tea.KeyCtrlS)},
 			wantAction:  ReviewActionApprove,
 		},
+		{
+			name:        "has comments save as pending",
+			summary:     []string{"file.md: 1 comment(s)"},
+			hasComments: true,
+			keys:        []tea.KeyMsg{keyMsg("j"), keyMsg("j"), keyMsg("j"), keyMsg("enter"), keyMsg("y"), ctrlKeyMsg(tea.KeyCtrlS)},
+			wantAction:  ReviewActionPending,
+			wantBody:    "y",
+		},
+		{
+			name:        "has comments request changes",
+			summary:     []string{"file.md: 1 comment(s)"},
+			hasComments: true,
+			keys:        []tea.KeyMsg{keyMsg("j"), keyMsg("j"), keyMsg("enter"), keyMsg("z"), ctrlKeyMsg(tea.KeyCtrlS)},
+			wantAction:  ReviewActionRequestChanges,
+			wantBody:    "z",
+		},
 		{
 			name:        "cancel with q",
 			summary:     []string{"No comments"},
@@ -154,6 +171,109 @@ func TestReviewDialogView(t *testing.T) {
 	})
 }
 
+func TestReviewDialogBlockApproval(t *testing.T) {
+	d := NewReviewDialog([]string{"No comments"}, false)
+	d.BlockApproval([]string{"approval requires every section to be viewed (1/3 viewed)"})
+	var model tea.Model = d
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+
+	if view := model.(*ReviewDialog).View(); !strings.Contains(view, "1/3 viewed") {
+		t.Errorf("View should explain why approval is refused, got %q", view)
+	}
+	model, _ = model.Update(keyMsg("enter"))
+	if d.mode != dialogModeSelect {
+		t.Fatal("selecting a blocked Approve should stay in select mode")
+	}
+	model, _ = model.Update(keyMsg("j"))
+	model.Update(keyMsg("enter"))
+	if got := d.Result().Action; got != ReviewActionExit {
+		t.Errorf("Action = %d, want exit", got)
+	}
+}
+
+func TestReviewDialogSuggestRequestChanges(t *testing.T) {
+	d := NewReviewDialog([]string{"file.md: 1 comment(s)"}, true)
+	d.SuggestRequestChanges([]string{"file.md: 1 blocking"})
+	var model tea.Model = d
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+
+	if view := model.(*ReviewDialog).View(); !strings.Contains(view, "file.md: 1 blocking") {
+		t.Errorf("View should explain why Request changes is preselected, got %q", view)
+	}
+	model, _ = model.Update(keyMsg("enter"))
+	model.Update(ctrlKeyMsg(tea.KeyCtrlS))
+	if got := d.Result().Action; got != ReviewActionRequestChanges {
+		t.Errorf("Action = %d, want request changes", got)
+	}
+}
+
+func TestReviewDialogRequireChangesBody(t *testing.T) {
+	d := NewReviewDialog([]string{"Threads to resolve: 1"}, true)
+	d.RequireChangesBody()
+	d.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	d.Update(keyMsg("j"))
+	d.Update(keyMsg("j"))
+	d.Update(keyMsg("enter"))
+
+	d.Update(ctrlKeyMsg(tea.KeyCtrlS))
+	if d.quitting {
+		t.Fatal("Request changes without a body should not be submitted")
+	}
+	if view := d.View(); !strings.Contains(view, "A PR comment is required") {
+		t.Errorf("View should ask for a PR comment, got %q", view)
+	}
+	d.SetBody("Please rework the intro")
+	d.Update(ctrlKeyMsg(tea.KeyCtrlS))
+	if got := d.Result(); got.Action != ReviewActionRequestChanges || got.Body != "Please rework the intro" {
+		t.Errorf("Result() = %+v, want request changes with the body", got)
+	}
+
+	// Other actions may still be submitted without a body.
+	d = NewReviewDialog([]string{"Threads to resolve: 1"}, true)
+	d.RequireChangesBody()
+	d.Update(keyMsg("enter"))
+	d.Update(ctrlKeyMsg(tea.KeyCtrlS))
+	if got := d.Result(); !d.quitting || got.Action != ReviewActionComment {
+		t.Errorf("Result() = %+v, want comment without a body", got)
+	}
+}
+
+func TestReviewDialogHideVerdicts(t *testing.T) {
+	tests := []struct {
+		name        string
+		hasComments bool
+		wantOptions []string
+	}{
+		{"has comments", true, []string{"Comment", "Save as pending", "Exit"}},
+		{"no comments", false, []string{"Comment", "Exit"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := NewReviewDialog([]string{"x"}, tt.hasComments)
+			d.SuggestRequestChanges([]string{"file.md: 1 issue"})
+			d.HideVerdicts()
+			if !slices.Equal(d.options, tt.wantOptions) {
+				t.Errorf("options = %q, want %q", d.options, tt.wantOptions)
+			}
+			d.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+			if view := d.View(); !strings.Contains(view, "your own PR") || strings.Contains(view, "1 issue") {
+				t.Errorf("View should explain the missing options only, got %q", view)
+			}
+		})
+	}
+}
+
 func ctrlKeyMsg(k tea.KeyType) tea.KeyMsg {
 	return tea.KeyMsg{Type: k}
 }
+
+func TestReviewDialogSetBody(t *testing.T) {
+	d := NewReviewDialog([]string{"file.md: 1 comment(s)"}, true)
+	d.SetBody("Saved earlier")
+	d.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	d.Update(keyMsg("enter"))
+	d.Update(ctrlKeyMsg(tea.KeyCtrlS))
+	if got := d.Result(); got.Action != ReviewActionComment || got.Body != "Saved earlier" {
+		t.Errorf("Result() = %+v, want the pre-filled body", got)
+	}
+}
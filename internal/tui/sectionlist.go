@@ -2,6 +2,7 @@ package tui
 
 import (
 	"fmt"
+	"maps"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
@@ -25,6 +26,7 @@ type SectionList struct {
 	scrollOffset int
 	comments     map[string][]*markdown.ReviewComment // sectionID -> comments
 	viewed       map[string]bool                      // sectionID -> viewed flag
+	verdicts     map[string]markdown.Verdict          // sectionID -> verdict (absent = none)
 	viewedState  *markdown.ViewedState
 	doc          *markdown.Document
 }
@@ -34,6 +36,7 @@ func NewSectionList(doc *markdown.Document, state *markdown.ViewedState) *Sectio
 	sl := &SectionList{
 		comments:    make(map[string][]*markdown.ReviewComment),
 		viewed:      make(map[string]bool),
+		verdicts:    make(map[string]markdown.Verdict),
 		viewedState: state,
 		doc:         doc,
 	}
@@ -63,6 +66,7 @@ func NewSectionList(doc *markdown.Document, state *markdown.ViewedState) *Sectio
 
 	// Restore viewed flags from persisted state
 	if state != nil {
+		state.Reconcile(doc)
 		for i, item := range sl.items {
 			if item.Section != nil && state.IsSectionViewed(item.Section) {
 				sl.viewed[sl.items[i].Section.ID] = true
@@ -229,7 +233,7 @@ func (sl *SectionList) IsOverviewSelected() bool {
 
 // AddComment appends a comment for a section.
 func (sl *SectionList) AddComment(sectionID string, comment *markdown.ReviewComment) {
-	if comment == nil || comment.Body == "" {
+	if comment == nil || comment.IsEmpty() {
 		return
 	}
 	sl.comments[sectionID] = append(sl.comments[sectionID], comment)
@@ -241,7 +245,7 @@ func (sl *SectionList) UpdateComment(sectionID string, index int, comment *markd
 	if index < 0 || index >= len(comments) {
 		return
 	}
-	if comment == nil || comment.Body == "" {
+	if comment == nil || comment.IsEmpty() {
 		sl.DeleteComment(sectionID, index)
 		return
 	}
@@ -286,6 +290,40 @@ func (sl *SectionList) IsViewed(sectionID string) bool {
 	return sl.viewed[sectionID]
 }
 
+// CycleVerdict moves a section to the next verdict (none, approve, needs
+// changes, reject). The overview has no verdict.
+func (sl *SectionList) CycleVerdict(sectionID string) {
+	if sl.doc.FindSection(sectionID) == nil {
+		return
+	}
+	sl.SetVerdict(sectionID, sl.verdicts[sectionID].Next())
+}
+
+// SetVerdict sets the verdict of a section (VerdictNone clears it).
+func (sl *SectionList) SetVerdict(sectionID string, v markdown.Verdict) {
+	if v == markdown.VerdictNone {
+		delete(sl.verdicts, sectionID)
+		return
+	}
+	sl.verdicts[sectionID] = v
+}
+
+// Verdict returns the verdict of a section.
+func (sl *SectionList) Verdict(sectionID string) markdown.Verdict {
+	return sl.verdicts[sectionID]
+}
+
+// VerdictCount returns the number of sections with verdict v.
+func (sl *SectionList) VerdictCount(v markdown.Verdict) int {
+	count := 0
+	for _, sv := range sl.verdicts {
+		if sv == v {
+			count++
+		}
+	}
+	return count
+}
+
 // GetComments returns all comments for a section.
 func (sl *SectionList) GetComments(sectionID string) []*markdown.ReviewComment {
 	return sl.comments[sectionID]
@@ -301,9 +339,12 @@ func (sl *SectionList) HasComments() bool {
 	return false
 }
 
-// BuildReviewResult creates a ReviewResult from all comments.
+// BuildReviewResult creates a ReviewResult from all comments and verdicts.
 func (sl *SectionList) BuildReviewResult() *markdown.ReviewResult {
 	result := &markdown.ReviewResult{}
+	if len(sl.verdicts) > 0 {
+		result.Verdicts = maps.Clone(sl.verdicts)
+	}
 
 	// Include overview comments first
 	for _, c := range sl.comments[markdown.OverviewSectionID] {
@@ -395,7 +436,14 @@ func (sl *SectionList) Render(width, height int, styles Styles) string {
 	return sb.String()
 }
 
-// renderBadge renders the badge for a section (comment indicator, viewed mark).
+// verdictBadges are the section list badges for each verdict.
+var verdictBadges = map[markdown.Verdict]string{
+	markdown.VerdictApprove:      " [+]",
+	markdown.VerdictNeedsChanges: " [~]",
+	markdown.VerdictReject:       " [✗]",
+}
+
+// renderBadge renders the badge for a section (comment indicator, verdict, viewed mark).
 func (sl *SectionList) renderBadge(sectionID string, styles Styles) string {
 	commentCount := len(sl.comments[sectionID])
 	isViewed := sl.viewed[sectionID]
@@ -406,6 +454,9 @@ func (sl *SectionList) renderBadge(sectionID string, styles Styles) string {
 	} else if commentCount > 1 {
 		badge += styles.SectionBadge.Render(fmt.Sprintf(" [*%d]", commentCount))
 	}
+	if v := sl.verdicts[sectionID]; v != markdown.VerdictNone {
+		badge += styles.verdictBadge(v).Render(verdictBadges[v])
+	}
 	if isViewed {
 		badge += styles.ViewedBadge.Render(" [✓]")
 	}
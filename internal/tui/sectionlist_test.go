@@ -277,6 +277,45 @@ func TestToggleViewed(t *testing.T) {
 	}
 }
 
+func TestCycleVerdict(t *testing.T) {
+	sl := NewSectionList(makeDocWithChildren(), nil)
+	styles := defaultStyles()
+
+	want := []struct {
+		verdict markdown.Verdict
+		badge   string
+	}{
+		{markdown.VerdictApprove, "[+]"},
+		{markdown.VerdictNeedsChanges, "[~]"},
+		{markdown.VerdictReject, "[✗]"},
+		{markdown.VerdictNone, ""},
+	}
+	for _, w := range want {
+		sl.CycleVerdict("S1")
+		if got := sl.Verdict("S1"); got != w.verdict {
+			t.Errorf("Verdict = %q, want %q", got, w.verdict)
+		}
+		if badge := sl.renderBadge("S1", styles); w.badge != "" && !strings.Contains(badge, w.badge) || w.badge == "" && badge != "" {
+			t.Errorf("badge for %q = %q, want %q", w.verdict, badge, w.badge)
+		}
+	}
+
+	sl.CycleVerdict(markdown.OverviewSectionID)
+	if got := sl.Verdict(markdown.OverviewSectionID); got != markdown.VerdictNone {
+		t.Errorf("overview verdict = %q, want none", got)
+	}
+
+	sl.SetVerdict("S1", markdown.VerdictReject)
+	sl.SetVerdict("S2", markdown.VerdictReject)
+	if got := sl.VerdictCount(markdown.VerdictReject); got != 2 {
+		t.Errorf("VerdictCount(reject) = %d, want 2", got)
+	}
+	result := sl.BuildReviewResult()
+	if len(result.Verdicts) != 2 || result.IsEmpty() {
+		t.Errorf("BuildReviewResult().Verdicts = %v", result.Verdicts)
+	}
+}
+
 func TestHasComments(t *testing.T) {
 	sl := NewSectionList(makeDocWithChildren(), nil)
 
@@ -1,11 +1,17 @@
 package tui
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/koh-sh/commd/internal/markdown"
+)
 
 // Theme constants.
 const (
-	ThemeDark  = "dark"
-	ThemeLight = "light"
+	ThemeDark         = "dark"
+	ThemeLight        = "light"
+	ThemeHighContrast = "high-contrast"
+	ThemeSolarized    = "solarized"
+	ThemeAuto         = "auto" // dark or light, detected from the terminal background
 )
 
 // Styles holds all the lipgloss styles for the TUI.
@@ -20,12 +26,18 @@ type Styles struct {
 	SectionBadge    lipgloss.Style
 	ViewedBadge     lipgloss.Style
 
+	// Section verdict badges
+	VerdictApprove      lipgloss.Style
+	VerdictNeedsChanges lipgloss.Style
+	VerdictReject       lipgloss.Style
+
 	// Status bar
 	StatusBar lipgloss.Style
 	StatusKey lipgloss.Style
 
 	// Comment
 	CommentBorder lipgloss.Style
+	ThreadBorder  lipgloss.Style // existing PR review threads (read-only)
 
 	// Line mode
 	LineGutter   lipgloss.Style
@@ -39,61 +51,126 @@ type Styles struct {
 
 // colorPalette defines the color values for a theme.
 type colorPalette struct {
-	activeBorder    string
-	inactiveBorder  string
-	title           string
-	selectedSection string
-	normalSection   string
-	sectionBadge    string
-	viewedBadge     string
-	statusBar       string
-	statusKey       string
-	commentBorder   string
-	lineGutter      string
-	lineCursorBg    string
-	lineSelectedBg  string
-	diffAddedFg     string
-	diffRemovedFg   string
+	activeBorder     string
+	inactiveBorder   string
+	title            string
+	selectedSection  string
+	normalSection    string
+	sectionBadge     string
+	viewedBadge      string
+	statusBar        string
+	statusKey        string
+	commentBorder    string
+	commentBoxBorder string
+	lineGutter       string
+	lineCursorBg     string
+	lineSelectedBg   string
+	diffAddedFg      string
+	diffRemovedFg    string
 }
 
 var (
 	darkPalette = colorPalette{
-		activeBorder:    "62",
-		inactiveBorder:  "240",
-		title:           "170",
-		selectedSection: "212",
-		normalSection:   "252",
-		sectionBadge:    "170",
-		viewedBadge:     "82",
-		statusBar:       "240",
-		statusKey:       "62",
-		commentBorder:   "62",
-		lineGutter:      "240",
-		lineCursorBg:    "236",
-		lineSelectedBg:  "235",
-		diffAddedFg:     "114",
-		diffRemovedFg:   "210",
+		activeBorder:     "62",
+		inactiveBorder:   "240",
+		title:            "170",
+		selectedSection:  "212",
+		normalSection:    "252",
+		sectionBadge:     "170",
+		viewedBadge:      "82",
+		statusBar:        "240",
+		statusKey:        "62",
+		commentBorder:    "62",
+		commentBoxBorder: "62",
+		lineGutter:       "240",
+		lineCursorBg:     "236",
+		lineSelectedBg:   "235",
+		diffAddedFg:      "114",
+		diffRemovedFg:    "210",
 	}
 
 	lightPalette = colorPalette{
-		activeBorder:    "33",
-		inactiveBorder:  "250",
-		title:           "130",
-		selectedSection: "33",
-		normalSection:   "236",
-		sectionBadge:    "130",
-		viewedBadge:     "28",
-		statusBar:       "245",
-		statusKey:       "33",
-		commentBorder:   "33",
-		lineGutter:      "245",
-		lineCursorBg:    "254",
-		lineSelectedBg:  "253",
-		diffAddedFg:     "28",
-		diffRemovedFg:   "160",
+		activeBorder:     "33",
+		inactiveBorder:   "250",
+		title:            "130",
+		selectedSection:  "33",
+		normalSection:    "236",
+		sectionBadge:     "130",
+		viewedBadge:      "28",
+		statusBar:        "245",
+		statusKey:        "33",
+		commentBorder:    "33",
+		commentBoxBorder: "33",
+		lineGutter:       "245",
+		lineCursorBg:     "254",
+		lineSelectedBg:   "253",
+		diffAddedFg:      "28",
+		diffRemovedFg:    "160",
+	}
+
+	// highContrastPalette uses only the 16 basic ANSI colours at full intensity.
+	highContrastPalette = colorPalette{
+		activeBorder:     "15",
+		inactiveBorder:   "7",
+		title:            "11",
+		selectedSection:  "14",
+		normalSection:    "15",
+		sectionBadge:     "11",
+		viewedBadge:      "10",
+		statusBar:        "7",
+		statusKey:        "14",
+		commentBorder:    "14",
+		commentBoxBorder: "14",
+		lineGutter:       "7",
+		lineCursorBg:     "4",
+		lineSelectedBg:   "8",
+		diffAddedFg:      "10",
+		diffRemovedFg:    "9",
+	}
+
+	// solarizedPalette follows the Solarized dark palette.
+	solarizedPalette = colorPalette{
+		activeBorder:     "#268bd2",
+		inactiveBorder:   "#586e75",
+		title:            "#b58900",
+		selectedSection:  "#2aa198",
+		normalSection:    "#839496",
+		sectionBadge:     "#cb4b16",
+		viewedBadge:      "#859900",
+		statusBar:        "#586e75",
+		statusKey:        "#268bd2",
+		commentBorder:    "#6c71c4",
+		commentBoxBorder: "#6c71c4",
+		lineGutter:       "#586e75",
+		lineCursorBg:     "#073642",
+		lineSelectedBg:   "#0d4a57",
+		diffAddedFg:      "#859900",
+		diffRemovedFg:    "#dc322f",
 	}
 )
 
+// named returns pointers to the colours of p keyed by their config name.
+func (p *colorPalette) named() map[string]*string {
+	return map[string]*string{
+		"active-border":      &p.activeBorder,
+		"inactive-border":    &p.inactiveBorder,
+		"title":              &p.title,
+		"selected-section":   &p.selectedSection,
+		"normal-section":     &p.normalSection,
+		"section-badge":      &p.sectionBadge,
+		"viewed-badge":       &p.viewedBadge,
+		"status-bar":         &p.statusBar,
+		"status-key":         &p.statusKey,
+		"comment-border":     &p.commentBorder,
+		"comment-box-border": &p.commentBoxBorder,
+		"line-gutter":        &p.lineGutter,
+		"line-cursor-bg":     &p.lineCursorBg,
+		"line-selected-bg":   &p.lineSelectedBg,
+		"diff-added":         &p.diffAddedFg,
+		"diff-removed":       &p.diffRemovedFg,
+	}
+}
+
 func buildStyles(p colorPalette) Styles {
 	return Styles{
 		ActiveBorder: lipgloss.NewStyle().
@@ -114,6 +191,13 @@ func buildStyles(p colorPalette) Styles {
 			Foreground(lipgloss.Color(p.sectionBadge)),
 		ViewedBadge: lipgloss.NewStyle().
 			Foreground(lipgloss.Color(p.viewedBadge)),
+		VerdictApprove: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(p.diffAddedFg)),
+		VerdictNeedsChanges: lipgloss.NewStyle().
+			Foreground(lipgloss.Color(p.title)),
+		VerdictReject: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color(p.diffRemovedFg)),
 		StatusBar: lipgloss.NewStyle().
 			Foreground(lipgloss.Color(p.statusBar)),
 		StatusKey: lipgloss.NewStyle().
@@ -122,6 +206,10 @@ func buildStyles(p colorPalette) Styles {
 		CommentBorder: lipgloss.NewStyle().
 			Border(lipgloss.RoundedBorder()).
 			BorderForeground(lipgloss.Color(p.commentBorder)),
+		ThreadBorder: lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color(p.inactiveBorder)).
+			Foreground(lipgloss.Color(p.lineGutter)),
 		LineGutter: lipgloss.NewStyle().
 			Foreground(lipgloss.Color(p.lineGutter)),
 		LineCursor: lipgloss.NewStyle().
@@ -135,10 +223,35 @@ func buildStyles(p colorPalette) Styles {
 	}
 }
 
-// stylesForTheme returns styles for the given theme.
-func stylesForTheme(theme string) Styles {
-	if theme == ThemeLight {
-		return buildStyles(lightPalette)
+// stylesForTheme returns styles for the given theme. Without colours, the
+// line cursor and selection fall back to reverse video and underline.
+func stylesForTheme(theme Theme) Styles {
+	theme = theme.orDefault()
+	s := buildStyles(theme.palette)
+	if theme.NoColor {
+		s.LineCursor = lipgloss.NewStyle().Reverse(true)
+		s.LineSelected = lipgloss.NewStyle().Underline(true)
+	}
+	return s
+}
+
+// labelStyle returns base with its foreground replaced by the configured colour
+// of the given action label, or base unchanged if the label has no colour.
+func labelStyle(base lipgloss.Style, action markdown.ActionType) lipgloss.Style {
+	if color := markdown.Labels.Color(action); color != "" {
+		return base.Foreground(lipgloss.Color(color))
+	}
+	return base
+}
+
+// verdictBadge returns the style of the section list badge for v.
+func (s Styles) verdictBadge(v markdown.Verdict) lipgloss.Style {
+	switch v {
+	case markdown.VerdictApprove:
+		return s.VerdictApprove
+	case markdown.VerdictReject:
+		return s.VerdictReject
+	default:
+		return s.VerdictNeedsChanges
 	}
-	return buildStyles(darkPalette)
 }
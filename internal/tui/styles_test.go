@@ -16,12 +16,12 @@ func TestStylesForTheme(t *testing.T) {
 	}{
 		{"dark", true},
 		{"light", false},
-		{"", true}, // empty defaults to dark
+		{"", true}, // zero value defaults to dark
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.theme, func(t *testing.T) {
-			s := stylesForTheme(tt.theme)
+			s := stylesForTheme(builtinThemes[tt.theme])
 
 			if s.Title.GetBold() != true {
 				t.Error("Title style should be bold")
@@ -31,8 +31,8 @@ func TestStylesForTheme(t *testing.T) {
 			}
 
 			// Verify dark and light produce different colors
-			dark := stylesForTheme("dark")
-			light := stylesForTheme("light")
+			dark := stylesForTheme(builtinThemes[ThemeDark])
+			light := stylesForTheme(builtinThemes[ThemeLight])
 			darkFg := dark.SelectedSection.GetForeground()
 			lightFg := light.SelectedSection.GetForeground()
 			if darkFg == lightFg {
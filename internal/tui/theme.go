@@ -0,0 +1,130 @@
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/glamour/ansi"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/koh-sh/commd/internal/markdown"
+	"github.com/muesli/termenv"
+)
+
+// Theme is a resolved colour theme for the TUI.
+type Theme struct {
+	Name    string
+	Dark    bool // targets a dark background; selects the built-in glamour style
+	NoColor bool // render without colours (NO_COLOR or --no-color)
+
+	palette colorPalette
+	glamour *ansi.StyleConfig // custom glamour style, nil for the built-in one
+}
+
+// builtinThemes are the themes available without configuration.
+var builtinThemes = map[string]Theme{
+	ThemeDark:         {Name: ThemeDark, Dark: true, palette: darkPalette},
+	ThemeLight:        {Name: ThemeLight, Dark: false, palette: lightPalette},
+	ThemeHighContrast: {Name: ThemeHighContrast, Dark: true, palette: highContrastPalette},
+	ThemeSolarized:    {Name: ThemeSolarized, Dark: true, palette: solarizedPalette},
+}
+
+// hasDarkBackground queries the terminal background colour (OSC 11).
+// Replaced in tests.
+var hasDarkBackground = lipgloss.HasDarkBackground
+
+// DefaultTheme returns the built-in dark theme.
+func DefaultTheme() Theme {
+	return builtinThemes[ThemeDark]
+}
+
+// BuiltinThemeNames returns the names of the built-in themes, sorted.
+func BuiltinThemeNames() []string {
+	return slices.Sorted(maps.Keys(builtinThemes))
+}
+
+// NewTheme creates a custom theme named name. It starts from the built-in
+// theme base ("" means dark), overrides the colours given by name (e.g.
+// "diff-added", see colorPalette.named), and optionally replaces the glamour
+// style with the JSON style file at glamourStyle.
+func NewTheme(name, base string, colors map[string]string, glamourStyle string) (Theme, error) {
+	if name == "" || name == ThemeAuto {
+		return Theme{}, fmt.Errorf("invalid theme name %q", name)
+	}
+	if base == "" {
+		base = ThemeDark
+	}
+	t, ok := builtinThemes[base]
+	if !ok {
+		return Theme{}, fmt.Errorf("theme %s: unknown base theme %q (want one of %s)", name, base, strings.Join(BuiltinThemeNames(), ", "))
+	}
+	t.Name = name
+
+	fields := t.palette.named()
+	for _, key := range slices.Sorted(maps.Keys(colors)) {
+		field, ok := fields[key]
+		if !ok {
+			return Theme{}, fmt.Errorf("theme %s: unknown colour %q", name, key)
+		}
+		if !markdown.ValidColor(colors[key]) {
+			return Theme{}, fmt.Errorf("theme %s: invalid colour %q for %s: want an ANSI code (0-255) or #rrggbb", name, colors[key], key)
+		}
+		*field = colors[key]
+	}
+
+	if glamourStyle != "" {
+		data, err := os.ReadFile(glamourStyle)
+		if err != nil {
+			return Theme{}, fmt.Errorf("theme %s: reading glamour style: %w", name, err)
+		}
+		var style ansi.StyleConfig
+		if err := json.Unmarshal(data, &style); err != nil {
+			return Theme{}, fmt.Errorf("theme %s: parsing glamour style %s: %w", name, glamourStyle, err)
+		}
+		t.glamour = &style
+	}
+	return t, nil
+}
+
+// LookupTheme resolves a theme name. Custom themes take precedence over
+// built-in ones. "auto" picks dark or light from the terminal background,
+// and "" means dark.
+func LookupTheme(name string, custom map[string]Theme) (Theme, error) {
+	switch name {
+	case "":
+		return DefaultTheme(), nil
+	case ThemeAuto:
+		if hasDarkBackground() {
+			return builtinThemes[ThemeDark], nil
+		}
+		return builtinThemes[ThemeLight], nil
+	}
+	if t, ok := custom[name]; ok {
+		return t, nil
+	}
+	if t, ok := builtinThemes[name]; ok {
+		return t, nil
+	}
+	names := append(BuiltinThemeNames(), ThemeAuto)
+	names = append(names, slices.Sorted(maps.Keys(custom))...)
+	return Theme{}, fmt.Errorf("unknown theme %q (want one of %s)", name, strings.Join(names, ", "))
+}
+
+// DisableColor makes every lipgloss style render without colours. Attributes
+// such as bold and reverse video are kept.
+func DisableColor() {
+	lipgloss.SetColorProfile(termenv.Ascii)
+}
+
+// orDefault returns t, or the default theme if t is the zero value.
+func (t Theme) orDefault() Theme {
+	if t.Name == "" {
+		d := DefaultTheme()
+		d.NoColor = t.NoColor
+		return d
+	}
+	return t
+}
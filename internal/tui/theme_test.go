@@ -0,0 +1,154 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/koh-sh/commd/internal/markdown"
+)
+
+func TestBuiltinThemes(t *testing.T) {
+	for _, name := range BuiltinThemeNames() {
+		t.Run(name, func(t *testing.T) {
+			th := builtinThemes[name]
+			for key, color := range th.palette.named() {
+				if !markdown.ValidColor(*color) {
+					t.Errorf("%s = %q is not a valid colour", key, *color)
+				}
+			}
+		})
+	}
+}
+
+func TestNewTheme(t *testing.T) {
+	dir := t.TempDir()
+	stylePath := filepath.Join(dir, "style.json")
+	if err := os.WriteFile(stylePath, []byte(`{"document": {"color": "#ffffff"}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	badStyle := filepath.Join(dir, "bad.json")
+	if err := os.WriteFile(badStyle, []byte(`{`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("overrides colours on base", func(t *testing.T) {
+		th, err := NewTheme("mine", ThemeLight, map[string]string{
+			"diff-added":         "#00ff00",
+			"comment-box-border": "99",
+		}, stylePath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if th.Name != "mine" || th.Dark {
+			t.Errorf("theme = %+v, want light-based theme named mine", th)
+		}
+		if th.palette.diffAddedFg != "#00ff00" || th.palette.commentBoxBorder != "99" {
+			t.Errorf("palette = %+v, want overrides applied", th.palette)
+		}
+		if th.palette.title != lightPalette.title {
+			t.Errorf("title = %s, want base colour %s", th.palette.title, lightPalette.title)
+		}
+		if th.glamour == nil || th.glamour.Document.Color == nil || *th.glamour.Document.Color != "#ffffff" {
+			t.Errorf("glamour style not loaded: %+v", th.glamour)
+		}
+		if lightPalette.diffAddedFg == "#00ff00" {
+			t.Error("NewTheme modified the built-in palette")
+		}
+	})
+
+	t.Run("default base is dark", func(t *testing.T) {
+		th, err := NewTheme("mine", "", nil, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !th.Dark || th.palette != darkPalette || th.glamour != nil {
+			t.Errorf("theme = %+v, want dark copy", th)
+		}
+	})
+
+	errTests := []struct {
+		name    string
+		theme   string
+		base    string
+		colors  map[string]string
+		style   string
+		wantErr string
+	}{
+		{"unknown base", "mine", "neon", nil, "", `unknown base theme "neon"`},
+		{"unknown colour", "mine", "", map[string]string{"background": "1"}, "", `unknown colour "background"`},
+		{"invalid colour", "mine", "", map[string]string{"title": "red"}, "", `invalid colour "red"`},
+		{"colour out of range", "mine", "", map[string]string{"title": "256"}, "", `invalid colour "256"`},
+		{"reserved name", ThemeAuto, "", nil, "", `invalid theme name "auto"`},
+		{"missing style", "mine", "", nil, filepath.Join(dir, "missing.json"), "reading glamour style"},
+		{"bad style", "mine", "", nil, badStyle, "parsing glamour style"},
+	}
+	for _, tt := range errTests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewTheme(tt.theme, tt.base, tt.colors, tt.style)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("err = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLookupTheme(t *testing.T) {
+	custom, err := NewTheme("mine", ThemeSolarized, nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	themes := map[string]Theme{"mine": custom}
+
+	tests := []struct {
+		name     string
+		dark     bool // terminal background reported by the OSC 11 query
+		wantName string
+	}{
+		{"", false, ThemeDark},
+		{ThemeDark, false, ThemeDark},
+		{ThemeHighContrast, false, ThemeHighContrast},
+		{"mine", false, "mine"},
+		{ThemeAuto, true, ThemeDark},
+		{ThemeAuto, false, ThemeLight},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orig := hasDarkBackground
+			hasDarkBackground = func() bool { return tt.dark }
+			defer func() { hasDarkBackground = orig }()
+
+			th, err := LookupTheme(tt.name, themes)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if th.Name != tt.wantName {
+				t.Errorf("theme = %s, want %s", th.Name, tt.wantName)
+			}
+		})
+	}
+
+	if _, err := LookupTheme("neon", themes); err == nil || !strings.Contains(err.Error(), "mine") {
+		t.Errorf("err = %v, want unknown theme error listing custom themes", err)
+	}
+}
+
+func TestNoColorTheme(t *testing.T) {
+	th := DefaultTheme()
+	th.NoColor = true
+
+	s := stylesForTheme(th)
+	if !s.LineCursor.GetReverse() {
+		t.Error("line cursor should use reverse video without colours")
+	}
+	if !s.LineSelected.GetUnderline() {
+		t.Error("line selection should be underlined without colours")
+	}
+
+	dp := NewDetailPane(80, 40, th)
+	dp.ShowOverview(makeLargeDoc(1, 1), nil)
+	if view := dp.View(); strings.Contains(view, "38;5;") || strings.Contains(view, "38;2;") {
+		t.Errorf("rendered markdown contains colour escapes:\n%q", view)
+	}
+}
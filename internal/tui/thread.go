@@ -0,0 +1,122 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/koh-sh/commd/internal/markdown"
+)
+
+// ReviewThread is an existing review thread on the PR, shown read-only
+// next to our own comments.
+type ReviewThread struct {
+	ID       string // identifies the thread in ThreadReply and ThreadResolution
+	Line     int    // file line the thread is attached to (0 = file-level or outdated)
+	Side     string // "RIGHT" or "LEFT"
+	Resolved bool
+	Outdated bool
+	Comments []ThreadComment
+	Reply    string // queued reply, e.g. from a pending review ("" = none)
+
+	toggleResolve bool // queued resolve (or unresolve, if Resolved)
+}
+
+// ThreadReply is a reply queued on an existing review thread.
+type ThreadReply struct {
+	ThreadID string
+	Body     string
+}
+
+// ThreadResolution is a queued change of a review thread's resolved state.
+type ThreadResolution struct {
+	ThreadID string
+	Resolve  bool // true = resolve, false = unresolve
+}
+
+// ThreadComment is one comment of a ReviewThread.
+type ThreadComment struct {
+	Author    string
+	Body      string
+	CreatedAt time.Time
+}
+
+// threadTimeFormat is the timestamp layout of thread comments.
+const threadTimeFormat = "2006-01-02 15:04"
+
+// state returns the thread's state for its header, e.g. "resolved, outdated".
+func (t *ReviewThread) state() string {
+	var parts []string
+	if t.Resolved {
+		parts = append(parts, "resolved")
+	}
+	if t.Outdated {
+		parts = append(parts, "outdated")
+	}
+	if t.toggleResolve {
+		if t.Resolved {
+			parts = append(parts, "will unresolve")
+		} else {
+			parts = append(parts, "will resolve")
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// renderThread renders t as a box whose content is width columns wide.
+func renderThread(t *ReviewThread, width int, border lipgloss.Style) string {
+	header := "Thread"
+	if t.Line > 0 {
+		header += fmt.Sprintf(" (L%d)", t.Line)
+	}
+	if state := t.state(); state != "" {
+		header += " [" + state + "]"
+	}
+	var sb strings.Builder
+	sb.WriteString(header)
+	for _, c := range t.Comments {
+		fmt.Fprintf(&sb, "\n@%s · %s", c.Author, c.CreatedAt.Local().Format(threadTimeFormat))
+		if c.Body != "" {
+			sb.WriteString("\n" + c.Body)
+		}
+	}
+	if t.Reply != "" {
+		sb.WriteString("\nYour reply (queued)\n" + t.Reply)
+	}
+	return border.Width(width).Padding(0, 1).Render(sb.String())
+}
+
+// threadChanges returns the replies and resolutions queued on threads.
+func threadChanges(threads []*ReviewThread) ([]ThreadReply, []ThreadResolution) {
+	var replies []ThreadReply
+	var resolutions []ThreadResolution
+	for _, t := range threads {
+		if t.Reply != "" {
+			replies = append(replies, ThreadReply{ThreadID: t.ID, Body: t.Reply})
+		}
+		if t.toggleResolve {
+			resolutions = append(resolutions, ThreadResolution{ThreadID: t.ID, Resolve: !t.Resolved})
+		}
+	}
+	return replies, resolutions
+}
+
+// groupThreads assigns each thread to the section containing its line.
+// Threads without a line, or before the first section, go to the overview.
+func groupThreads(doc *markdown.Document, threads []*ReviewThread) map[string][]*ReviewThread {
+	sections := doc.AllSections()
+	m := make(map[string][]*ReviewThread)
+	for _, t := range threads {
+		id := markdown.OverviewSectionID
+		if t.Line > 0 {
+			for _, s := range sections {
+				if s.StartLine > 0 && s.StartLine <= t.Line {
+					id = s.ID
+				}
+			}
+		}
+		m[id] = append(m[id], t)
+	}
+	return m
+}
@@ -0,0 +1,55 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/koh-sh/commd/internal/markdown"
+)
+
+func TestRenderThread(t *testing.T) {
+	created := time.Date(2026, 1, 2, 3, 4, 0, 0, time.Local)
+	thread := &ReviewThread{
+		Line:     12,
+		Resolved: true,
+		Outdated: true,
+		Comments: []ThreadComment{
+			{Author: "alice", Body: "Is this needed?", CreatedAt: created},
+			{Author: "bob", Body: "Yes.", CreatedAt: created.Add(time.Hour)},
+		},
+	}
+	got := renderThread(thread, 60, lipgloss.NewStyle().Border(lipgloss.RoundedBorder()))
+	for _, want := range []string{"Thread (L12) [resolved, outdated]", "@alice · 2026-01-02 03:04", "Is this needed?", "@bob · 2026-01-02 04:04", "Yes."} {
+		if !strings.Contains(got, want) {
+			t.Errorf("renderThread() missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestGroupThreads(t *testing.T) {
+	doc := &markdown.Document{Sections: []*markdown.Section{
+		{ID: "S1", StartLine: 3, Children: []*markdown.Section{{ID: "S1.1", StartLine: 8}}},
+		{ID: "S2", StartLine: 20},
+	}}
+	threads := []*ReviewThread{{Line: 1}, {Line: 5}, {Line: 9}, {Line: 25}, {Outdated: true}}
+	got := groupThreads(doc, threads)
+	want := map[string][]*ReviewThread{
+		markdown.OverviewSectionID: {threads[0], threads[4]},
+		"S1":                       {threads[1]},
+		"S1.1":                     {threads[2]},
+		"S2":                       {threads[3]},
+	}
+	for id, w := range want {
+		if len(got[id]) != len(w) {
+			t.Errorf("section %s: got %d threads, want %d", id, len(got[id]), len(w))
+			continue
+		}
+		for i := range w {
+			if got[id][i] != w[i] {
+				t.Errorf("section %s thread %d = %+v, want %+v", id, i, got[id][i], w[i])
+			}
+		}
+	}
+}
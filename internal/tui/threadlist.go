@@ -0,0 +1,110 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ThreadList lists the existing review threads of a section for replying
+// and resolving.
+type ThreadList struct {
+	sectionID string
+	threads   []*ReviewThread
+	cursor    int
+}
+
+// NewThreadList creates a new ThreadList.
+func NewThreadList() *ThreadList {
+	return &ThreadList{}
+}
+
+// Open opens the thread list for a section.
+func (tl *ThreadList) Open(sectionID string, threads []*ReviewThread) {
+	tl.sectionID = sectionID
+	tl.threads = threads
+	if tl.cursor >= len(threads) {
+		tl.cursor = len(threads) - 1
+	}
+	if tl.cursor < 0 {
+		tl.cursor = 0
+	}
+}
+
+// Close closes the thread list.
+func (tl *ThreadList) Close() {
+	tl.threads = nil
+	tl.cursor = 0
+}
+
+// SectionID returns the section ID.
+func (tl *ThreadList) SectionID() string {
+	return tl.sectionID
+}
+
+// Selected returns the thread under the cursor, or nil if the list is empty.
+func (tl *ThreadList) Selected() *ReviewThread {
+	if tl.cursor < 0 || tl.cursor >= len(tl.threads) {
+		return nil
+	}
+	return tl.threads[tl.cursor]
+}
+
+// CursorUp moves the cursor up.
+func (tl *ThreadList) CursorUp() {
+	if tl.cursor > 0 {
+		tl.cursor--
+	}
+}
+
+// CursorDown moves the cursor down.
+func (tl *ThreadList) CursorDown() {
+	if tl.cursor < len(tl.threads)-1 {
+		tl.cursor++
+	}
+}
+
+// Render renders the thread list.
+func (tl *ThreadList) Render(width, height int, styles Styles) string {
+	var sb strings.Builder
+
+	sb.WriteString(styles.Title.Render(fmt.Sprintf("Threads on %s", tl.sectionID)))
+	sb.WriteString("\n\n")
+
+	for i, t := range tl.threads {
+		prefix := "  "
+		style := styles.NormalSection
+		if i == tl.cursor {
+			prefix = "> "
+			style = styles.SelectedSection
+		}
+
+		header := fmt.Sprintf("%s#%d", prefix, i+1)
+		if t.Line > 0 {
+			header += fmt.Sprintf(" (L%d)", t.Line)
+		}
+		if len(t.Comments) > 0 {
+			header += " @" + t.Comments[0].Author
+		}
+		if state := t.state(); state != "" {
+			header += " [" + state + "]"
+		}
+		sb.WriteString(style.Render(header))
+		sb.WriteString("\n")
+
+		// Show the first comment and the queued reply (first line, truncated)
+		if len(t.Comments) > 0 && t.Comments[0].Body != "" {
+			line := strings.SplitN(t.Comments[0].Body, "\n", 2)[0]
+			sb.WriteString(styles.NormalSection.Render("    " + truncate(line, width-6)))
+			sb.WriteString("\n")
+		}
+		if t.Reply != "" {
+			line := "reply: " + strings.SplitN(t.Reply, "\n", 2)[0]
+			sb.WriteString(styles.NormalSection.Render("    " + truncate(line, width-6)))
+			sb.WriteString("\n")
+		}
+
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
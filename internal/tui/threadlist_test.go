@@ -0,0 +1,52 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestThreadListNavigation(t *testing.T) {
+	tl := NewThreadList()
+	if tl.Selected() != nil {
+		t.Error("Selected() on an empty list should be nil")
+	}
+
+	threads := []*ReviewThread{{ID: "T1"}, {ID: "T2"}}
+	tl.cursor = 5
+	tl.Open("S1", threads)
+	if tl.Selected() != threads[1] {
+		t.Errorf("cursor beyond range should clamp to the last thread, got %+v", tl.Selected())
+	}
+	tl.CursorDown()
+	if tl.Selected() != threads[1] {
+		t.Error("CursorDown at the end should stay")
+	}
+	tl.CursorUp()
+	tl.CursorUp()
+	if tl.Selected() != threads[0] {
+		t.Error("CursorUp at the top should stay")
+	}
+
+	tl.Close()
+	if tl.Selected() != nil {
+		t.Error("Selected() after Close should be nil")
+	}
+}
+
+func TestThreadListRender(t *testing.T) {
+	tl := NewThreadList()
+	tl.Open("S2", []*ReviewThread{
+		{Line: 7, Resolved: true, toggleResolve: true, Comments: []ThreadComment{{Author: "alice", Body: "First line\nsecond line"}}},
+		{Reply: "On it", Comments: []ThreadComment{{Author: "bob", Body: "File note"}}},
+	})
+
+	got := tl.Render(80, 20, stylesForTheme(DefaultTheme()))
+	for _, want := range []string{"Threads on S2", "#1 (L7) @alice [resolved, will unresolve]", "First line", "#2 @bob", "reply: On it"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Render() missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "second line") {
+		t.Errorf("Render() should show only the first line of a comment:\n%s", got)
+	}
+}
@@ -1,20 +1,32 @@
 package main
 
 import (
+	"fmt"
+	"os"
+
 	"github.com/alecthomas/kong"
 	"github.com/koh-sh/commd/cmd"
+	"github.com/koh-sh/commd/internal/config"
 )
 
 var version = "dev"
 
 func main() {
+	cfg, err := config.Load(".")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "commd: %v\n", err)
+		os.Exit(1)
+	}
+
 	var cli cmd.CLI
 	ctx := kong.Parse(&cli,
 		kong.Name("commd"),
 		kong.Description("Interactive Markdown reviewer"),
 		kong.UsageOnError(),
 		kong.Vars{"version": version},
+		kong.Resolvers(cmd.ConfigResolver(cfg)),
+		kong.Bind(cfg),
 	)
-	err := ctx.Run()
+	err = ctx.Run()
 	ctx.FatalIfErrorf(err)
 }